@@ -0,0 +1,293 @@
+// Package adf converts Atlassian Document Format (ADF) content to and from
+// plain text, and to HTML.
+package adf
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Node is a node in an ADF document tree.
+type Node struct {
+	Version int            `json:"version,omitempty"` // set on the root "doc" node
+	Type    string         `json:"type"`
+	Text    string         `json:"text,omitempty"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+	Marks   []Mark         `json:"marks,omitempty"`
+	Content []Node         `json:"content,omitempty"`
+}
+
+// Mark is a formatting mark applied to a text node.
+type Mark struct {
+	Type  string         `json:"type"`
+	Attrs map[string]any `json:"attrs,omitempty"`
+}
+
+// TableFormat selects how tables are rendered.
+type TableFormat string
+
+const (
+	// TablePlain renders each row as cells separated by " | ".
+	TablePlain TableFormat = "plain"
+
+	// TableMarkdown renders tables as Markdown, with a separator line
+	// after a header row.
+	TableMarkdown TableFormat = "markdown"
+)
+
+// Options controls plain-text rendering.
+type Options struct {
+	// TableFormat defaults to TablePlain.
+	TableFormat TableFormat
+
+	// KeepIssueURLs renders links to Jira issues as "PROJ-2 (url)" instead
+	// of just the issue key.
+	KeepIssueURLs bool
+}
+
+// FromText builds an ADF document from plain text. Blank lines separate
+// paragraphs and single newlines become hard breaks.
+func FromText(text string) Node {
+	doc := Node{Version: 1, Type: "doc"}
+	for _, para := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		para = strings.Trim(para, "\n")
+		if para == "" {
+			continue
+		}
+		p := Node{Type: "paragraph"}
+		for i, line := range strings.Split(para, "\n") {
+			if i > 0 {
+				p.Content = append(p.Content, Node{Type: "hardBreak"})
+			}
+			if line != "" {
+				p.Content = append(p.Content, Node{Type: "text", Text: line})
+			}
+		}
+		doc.Content = append(doc.Content, p)
+	}
+	if len(doc.Content) == 0 {
+		doc.Content = []Node{{Type: "paragraph"}}
+	}
+	return doc
+}
+
+// PlainText converts a JSON value holding either an ADF document or a plain
+// string to plain text. A null or empty value yields an empty string.
+func PlainText(raw json.RawMessage) (string, error) {
+	return PlainTextWithOptions(raw, Options{})
+}
+
+// PlainTextWithOptions is PlainText with rendering options.
+func PlainTextWithOptions(raw json.RawMessage, opts Options) (string, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return "", nil
+	}
+
+	switch raw[0] {
+	case '"':
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return "", fmt.Errorf("decode string: %w", err)
+		}
+		return s, nil
+	case '{':
+		var doc Node
+		if err := json.Unmarshal(raw, &doc); err != nil {
+			return "", fmt.Errorf("decode adf: %w", err)
+		}
+		return ToTextWithOptions(doc, opts), nil
+	default:
+		return "", fmt.Errorf("unexpected value: %.20s", raw)
+	}
+}
+
+// IsDocument reports whether raw holds an ADF document rather than a
+// plain string.
+func IsDocument(raw json.RawMessage) bool {
+	raw = bytes.TrimSpace(raw)
+	return len(raw) > 0 && raw[0] == '{'
+}
+
+// ToText renders an ADF node tree as plain text.
+func ToText(doc Node) string {
+	return ToTextWithOptions(doc, Options{})
+}
+
+// ToTextWithOptions renders an ADF node tree as plain text with options.
+func ToTextWithOptions(doc Node, opts Options) string {
+	r := renderer{opts: opts}
+	var b strings.Builder
+	r.render(&b, doc)
+	return strings.TrimSpace(collapseBlankLines(b.String()))
+}
+
+type renderer struct {
+	opts Options
+
+	// inline is set while rendering content flattened to one line, such
+	// as a table cell.
+	inline bool
+}
+
+func (r renderer) render(b *strings.Builder, n Node) {
+	if fn, ok := lookupRenderer(n.Type); ok {
+		b.WriteString(fn(n))
+		return
+	}
+
+	switch n.Type {
+	case "text":
+		b.WriteString(r.linkText(n))
+	case "hardBreak":
+		b.WriteString("\n")
+	case "mention", "emoji", "status", "date":
+		b.WriteString(attr(n, "text"))
+	case "inlineCard", "blockCard", "embedCard":
+		b.WriteString(r.issueRef(attr(n, "url")))
+	case "rule":
+		b.WriteString("\n---\n")
+	case "bulletList":
+		r.renderList(b, n, func(int) string { return "- " })
+	case "orderedList":
+		r.renderList(b, n, func(i int) string { return fmt.Sprintf("%d. ", i+1) })
+	case "table":
+		if r.inline {
+			r.renderInlineTable(b, n)
+			break
+		}
+		r.renderTable(b, n)
+		b.WriteString("\n")
+	case "panel":
+		panelType := attr(n, "panelType")
+		if panelType == "" {
+			panelType = "info"
+		}
+		b.WriteString("[" + strings.ToUpper(panelType) + "] ")
+		b.WriteString(r.flatten(n))
+		b.WriteString("\n\n")
+	case "media":
+		name := attr(n, "alt")
+		if name == "" {
+			name = attr(n, "id")
+		}
+		b.WriteString("[attachment: " + name + "]")
+	case "mediaSingle", "mediaGroup":
+		r.renderChildren(b, n)
+		b.WriteString("\n\n")
+	case "paragraph", "heading", "blockquote", "codeBlock":
+		r.renderChildren(b, n)
+		b.WriteString("\n\n")
+	default:
+		r.renderChildren(b, n)
+	}
+}
+
+func (r renderer) renderChildren(b *strings.Builder, n Node) {
+	for _, child := range n.Content {
+		r.render(b, child)
+	}
+}
+
+// flatten renders the children of n on a single line.
+func (r renderer) flatten(n Node) string {
+	r.inline = true
+	var b strings.Builder
+	r.renderChildren(&b, n)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+func (r renderer) renderList(b *strings.Builder, n Node, prefix func(int) string) {
+	for i, item := range n.Content {
+		var inner strings.Builder
+		r.renderChildren(&inner, item)
+		b.WriteString(prefix(i))
+		b.WriteString(strings.TrimSpace(collapseBlankLines(inner.String())))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+}
+
+// renderTable renders one line per row. Cells are flattened to a single
+// line, so nested tables appear inline within their cell.
+func (r renderer) renderTable(b *strings.Builder, n Node) {
+	for i, row := range n.Content {
+		cells := make([]string, 0, len(row.Content))
+		header := len(row.Content) > 0
+		for _, cell := range row.Content {
+			cells = append(cells, r.flatten(cell))
+			header = header && cell.Type == "tableHeader"
+		}
+
+		if r.opts.TableFormat == TableMarkdown {
+			b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+			if i == 0 && header {
+				b.WriteString(strings.Repeat("| --- ", len(cells)) + "|\n")
+			}
+			continue
+		}
+		b.WriteString(strings.Join(cells, " | ") + "\n")
+	}
+}
+
+// renderInlineTable renders a table nested in a cell as
+// "[a | b; c | d]" so its structure survives flattening.
+func (r renderer) renderInlineTable(b *strings.Builder, n Node) {
+	rows := make([]string, 0, len(n.Content))
+	for _, row := range n.Content {
+		cells := make([]string, 0, len(row.Content))
+		for _, cell := range row.Content {
+			cells = append(cells, r.flatten(cell))
+		}
+		rows = append(rows, strings.Join(cells, " | "))
+	}
+	b.WriteString(" [" + strings.Join(rows, "; ") + "] ")
+}
+
+// browseURL matches links to Jira issues, absolute or relative, such as
+// "https://acme.atlassian.net/browse/PROJ-2" or "/browse/PROJ-2?focusedCommentId=1".
+var browseURL = regexp.MustCompile(`^(?:https?://[^/\s]+)?(?:/[^\s?#]*)?/browse/([A-Z][A-Z0-9_]+-[0-9]+)(?:[?#][^\s]*)?$`)
+
+// issueRef renders a link URL: the issue key for links to Jira issues,
+// with the URL too when KeepIssueURLs is set, and the URL otherwise.
+func (r renderer) issueRef(url string) string {
+	m := browseURL.FindStringSubmatch(url)
+	if m == nil {
+		return url
+	}
+	if r.opts.KeepIssueURLs {
+		return m[1] + " (" + url + ")"
+	}
+	return m[1]
+}
+
+// linkText renders a text node. A smart link pasted as a plain link, whose
+// text is its own URL, is rendered like an inline card.
+func (r renderer) linkText(n Node) string {
+	for _, mark := range n.Marks {
+		if mark.Type != "link" {
+			continue
+		}
+		if href, _ := mark.Attrs["href"].(string); href == n.Text {
+			return r.issueRef(n.Text)
+		}
+	}
+	return n.Text
+}
+
+func attr(n Node, key string) string {
+	if v, ok := n.Attrs[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+func collapseBlankLines(s string) string {
+	for strings.Contains(s, "\n\n\n") {
+		s = strings.ReplaceAll(s, "\n\n\n", "\n\n")
+	}
+	return s
+}
@@ -0,0 +1,262 @@
+package adf
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func text(s string, marks ...Mark) Node {
+	return Node{Type: "text", Text: s, Marks: marks}
+}
+
+func paragraph(content ...Node) Node {
+	return Node{Type: "paragraph", Content: content}
+}
+
+func doc(content ...Node) Node {
+	return Node{Version: 1, Type: "doc", Content: content}
+}
+
+func link(href string) Mark {
+	return Mark{Type: "link", Attrs: map[string]any{"href": href}}
+}
+
+func card(url string) Node {
+	return Node{Type: "inlineCard", Attrs: map[string]any{"url": url}}
+}
+
+func table(rows ...[]Node) Node {
+	t := Node{Type: "table"}
+	for _, cells := range rows {
+		t.Content = append(t.Content, Node{Type: "tableRow", Content: cells})
+	}
+	return t
+}
+
+func cell(cellType string, s string) Node {
+	return Node{Type: cellType, Content: []Node{paragraph(text(s))}}
+}
+
+func TestToText(t *testing.T) {
+	header := []Node{cell("tableHeader", "Name"), cell("tableHeader", "State")}
+	row := []Node{cell("tableCell", "api"), cell("tableCell", "up")}
+
+	tests := []struct {
+		name string
+		doc  Node
+		opts Options
+		want string
+	}{
+		{
+			name: "paragraphs",
+			doc:  doc(paragraph(text("one"), Node{Type: "hardBreak"}, text("two")), paragraph(text("three"))),
+			want: "one\ntwo\n\nthree",
+		},
+		{
+			name: "lists",
+			doc: doc(
+				Node{Type: "bulletList", Content: []Node{
+					{Type: "listItem", Content: []Node{paragraph(text("a"))}},
+					{Type: "listItem", Content: []Node{paragraph(text("b"))}},
+				}},
+				Node{Type: "orderedList", Content: []Node{
+					{Type: "listItem", Content: []Node{paragraph(text("first"))}},
+				}},
+			),
+			want: "- a\n- b\n\n1. first",
+		},
+		{
+			name: "plain table",
+			doc:  doc(table(header, row)),
+			want: "Name | State\napi | up",
+		},
+		{
+			name: "markdown table",
+			doc:  doc(table(header, row)),
+			opts: Options{TableFormat: TableMarkdown},
+			want: "| Name | State |\n| --- | --- |\n| api | up |",
+		},
+		{
+			name: "nested table",
+			doc: doc(table([]Node{
+				cell("tableCell", "outer"),
+				{Type: "tableCell", Content: []Node{table([]Node{cell("tableCell", "a"), cell("tableCell", "b")})}},
+			})),
+			want: "outer | [a | b]",
+		},
+		{
+			name: "panel",
+			doc:  doc(Node{Type: "panel", Attrs: map[string]any{"panelType": "warning"}, Content: []Node{paragraph(text("Careful"))}}),
+			want: "[WARNING] Careful",
+		},
+		{
+			name: "media",
+			doc:  doc(Node{Type: "mediaSingle", Content: []Node{{Type: "media", Attrs: map[string]any{"id": "abc", "alt": "trace.png"}}}}),
+			want: "[attachment: trace.png]",
+		},
+		{
+			name: "mention",
+			doc:  doc(paragraph(text("ping "), Node{Type: "mention", Attrs: map[string]any{"text": "@dev"}})),
+			want: "ping @dev",
+		},
+		{
+			name: "issue card",
+			doc:  doc(paragraph(text("see "), card("https://acme.atlassian.net/browse/PROJ-2"))),
+			want: "see PROJ-2",
+		},
+		{
+			name: "issue card with URL",
+			doc:  doc(paragraph(text("see "), card("https://acme.atlassian.net/browse/PROJ-2"))),
+			opts: Options{KeepIssueURLs: true},
+			want: "see PROJ-2 (https://acme.atlassian.net/browse/PROJ-2)",
+		},
+		{
+			name: "relative issue link",
+			doc:  doc(paragraph(text("/browse/PROJ-2?focusedCommentId=1", link("/browse/PROJ-2?focusedCommentId=1")))),
+			want: "PROJ-2",
+		},
+		{
+			name: "other card",
+			doc:  doc(paragraph(card("https://example.com/docs"))),
+			want: "https://example.com/docs",
+		},
+		{
+			name: "link with its own text",
+			doc:  doc(paragraph(text("the ticket", link("https://acme.atlassian.net/browse/PROJ-2")))),
+			want: "the ticket",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToTextWithOptions(tt.doc, tt.opts); got != tt.want {
+				t.Errorf("text = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{raw: ``},
+		{raw: `null`},
+		{raw: `"plain"`, want: "plain"},
+		{raw: `{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"adf"}]}]}`, want: "adf"},
+		{raw: `{"type":"doc","content":"broken"}`, wantErr: true},
+		{raw: `42`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := PlainText(json.RawMessage(tt.raw))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("PlainText(%s) error = %v, want error %v", tt.raw, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("PlainText(%s) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestFromText(t *testing.T) {
+	in := "first line\nsecond line\n\n\n\nnext paragraph"
+	if got := ToText(FromText(in)); got != "first line\nsecond line\n\nnext paragraph" {
+		t.Errorf("round trip = %q", got)
+	}
+	if empty := FromText(""); len(empty.Content) != 1 || empty.Content[0].Type != "paragraph" {
+		t.Errorf("FromText(\"\") = %+v, want one empty paragraph", empty)
+	}
+}
+
+func TestToHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  Node
+		want string
+	}{
+		{
+			name: "escaped text",
+			doc:  doc(paragraph(text(`<script>alert("x")</script>`))),
+			want: `<p>&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;</p>`,
+		},
+		{
+			name: "marks",
+			doc:  doc(paragraph(text("bold link", Mark{Type: "strong"}, link("https://example.com/?a=1&b=2")))),
+			want: `<p><strong><a href="https://example.com/?a=1&amp;b=2">bold link</a></strong></p>`,
+		},
+		{
+			name: "script link",
+			doc:  doc(paragraph(text("click", link("javascript:alert(1)")))),
+			want: `<p>click</p>`,
+		},
+		{
+			name: "obfuscated script link",
+			doc:  doc(paragraph(text("click", link(" JavaScript:alert(1)")))),
+			want: `<p>click</p>`,
+		},
+		{
+			name: "data card",
+			doc:  doc(paragraph(card("data:text/html,<b>x</b>"))),
+			want: `<p>data:text/html,&lt;b&gt;x&lt;/b&gt;</p>`,
+		},
+		{
+			name: "relative card",
+			doc:  doc(paragraph(card("/browse/PROJ-2"))),
+			want: `<p><a href="/browse/PROJ-2">/browse/PROJ-2</a></p>`,
+		},
+		{
+			name: "heading and code",
+			doc:  doc(Node{Type: "heading", Attrs: map[string]any{"level": 2.0}, Content: []Node{text("Title")}}, Node{Type: "codeBlock", Content: []Node{text("a < b")}}),
+			want: `<h2>Title</h2><pre><code>a &lt; b</code></pre>`,
+		},
+		{
+			name: "panel type",
+			doc:  doc(Node{Type: "panel", Attrs: map[string]any{"panelType": `"><script>`}, Content: []Node{paragraph(text("x"))}}),
+			want: `<div class="panel-&#34;&gt;&lt;script&gt;"><p>x</p></div>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToHTML(tt.doc); got != tt.want {
+				t.Errorf("html = %s\nwant   %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterRenderer(t *testing.T) {
+	macro := Node{Type: "extension", Attrs: map[string]any{"extensionKey": "jira-chart"}}
+	input := doc(paragraph(text("before")), macro, paragraph(text("after")))
+
+	if got := ToText(input); got != "before\n\nafter" {
+		t.Fatalf("text without a renderer = %q", got)
+	}
+
+	RegisterRenderer("extension", func(n Node) string {
+		return "[macro <" + attr(n, "extensionKey") + ">]\nsee Jira\n\n"
+	})
+	defer RegisterRenderer("extension", nil)
+
+	if got, want := ToText(input), "before\n\n[macro <jira-chart>]\nsee Jira\n\nafter"; got != want {
+		t.Errorf("text = %q, want %q", got, want)
+	}
+	if got, want := ToHTML(input), "<p>before</p><span>[macro &lt;jira-chart&gt;]<br>see Jira</span><p>after</p>"; got != want {
+		t.Errorf("html = %s\nwant   %s", got, want)
+	}
+
+	// A renderer also replaces a built-in rendering.
+	RegisterRenderer("mention", func(n Node) string { return "" })
+	defer RegisterRenderer("mention", nil)
+	mention := doc(paragraph(text("ping "), Node{Type: "mention", Attrs: map[string]any{"text": "@dev"}}))
+	if got := ToText(mention); strings.Contains(got, "@dev") {
+		t.Errorf("text = %q, want the mention left out", got)
+	}
+	if got := ToHTML(mention); got != "<p>ping </p>" {
+		t.Errorf("html = %s, want the mention left out", got)
+	}
+}
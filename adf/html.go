@@ -0,0 +1,185 @@
+package adf
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+)
+
+// HTML converts a JSON value holding either an ADF document or a plain
+// string to HTML. A plain string becomes a single escaped paragraph. A null
+// or empty value yields an empty string.
+func HTML(raw json.RawMessage) (string, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return "", nil
+	}
+
+	switch raw[0] {
+	case '"':
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return "", fmt.Errorf("decode string: %w", err)
+		}
+		return "<p>" + html.EscapeString(s) + "</p>", nil
+	case '{':
+		var doc Node
+		if err := json.Unmarshal(raw, &doc); err != nil {
+			return "", fmt.Errorf("decode adf: %w", err)
+		}
+		return ToHTML(doc), nil
+	default:
+		return "", fmt.Errorf("unexpected value: %.20s", raw)
+	}
+}
+
+// ToHTML renders an ADF node tree as HTML. Text is escaped; unknown nodes
+// render their children only.
+func ToHTML(doc Node) string {
+	var b strings.Builder
+	renderHTML(&b, doc)
+	return b.String()
+}
+
+func renderHTML(b *strings.Builder, n Node) {
+	if fn, ok := lookupRenderer(n.Type); ok {
+		// Renderers produce plain text; escape it and keep its line breaks.
+		if text := strings.TrimSpace(fn(n)); text != "" {
+			lines := strings.Split(html.EscapeString(text), "\n")
+			b.WriteString("<span>" + strings.Join(lines, "<br>") + "</span>")
+		}
+		return
+	}
+
+	switch n.Type {
+	case "text":
+		renderHTMLText(b, n)
+	case "hardBreak":
+		b.WriteString("<br>")
+	case "rule":
+		b.WriteString("<hr>")
+	case "mention", "emoji", "status", "date":
+		b.WriteString(html.EscapeString(attr(n, "text")))
+	case "inlineCard", "blockCard", "embedCard":
+		href := attr(n, "url")
+		text := html.EscapeString(href)
+		if !safeHref(href) {
+			b.WriteString(text)
+			break
+		}
+		b.WriteString(`<a href="` + text + `">` + text + "</a>")
+	case "media":
+		name := attr(n, "alt")
+		if name == "" {
+			name = attr(n, "id")
+		}
+		b.WriteString("[attachment: " + html.EscapeString(name) + "]")
+	case "heading":
+		level := 1
+		if l, ok := n.Attrs["level"].(float64); ok && l >= 1 && l <= 6 {
+			level = int(l)
+		}
+		fmt.Fprintf(b, "<h%d>", level)
+		renderHTMLChildren(b, n)
+		fmt.Fprintf(b, "</h%d>", level)
+	case "codeBlock":
+		b.WriteString("<pre><code>")
+		renderHTMLChildren(b, n)
+		b.WriteString("</code></pre>")
+	case "panel":
+		panelType := attr(n, "panelType")
+		if panelType == "" {
+			panelType = "info"
+		}
+		b.WriteString(`<div class="panel-` + html.EscapeString(panelType) + `">`)
+		renderHTMLChildren(b, n)
+		b.WriteString("</div>")
+	default:
+		tag := htmlTags[n.Type]
+		if tag != "" {
+			b.WriteString("<" + tag + ">")
+		}
+		renderHTMLChildren(b, n)
+		if tag != "" {
+			b.WriteString("</" + tag + ">")
+		}
+	}
+}
+
+// htmlTags maps ADF node types to the HTML element wrapping their children.
+var htmlTags = map[string]string{
+	"paragraph":   "p",
+	"blockquote":  "blockquote",
+	"bulletList":  "ul",
+	"orderedList": "ol",
+	"listItem":    "li",
+	"table":       "table",
+	"tableRow":    "tr",
+	"tableHeader": "th",
+	"tableCell":   "td",
+}
+
+// markTags maps ADF mark types to HTML elements.
+var markTags = map[string]string{
+	"strong":    "strong",
+	"em":        "em",
+	"code":      "code",
+	"strike":    "s",
+	"underline": "u",
+}
+
+func renderHTMLChildren(b *strings.Builder, n Node) {
+	for _, child := range n.Content {
+		renderHTML(b, child)
+	}
+}
+
+// safeHref reports whether href may be rendered as a link: an http,
+// https or mailto URL, or a relative one. Other schemes, such as
+// javascript: or data:, could run script where the HTML is displayed.
+func safeHref(href string) bool {
+	u, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https", "mailto":
+		return true
+	default:
+		return false
+	}
+}
+
+// renderHTMLText writes a text node wrapped in the elements of its marks,
+// the first mark outermost.
+func renderHTMLText(b *strings.Builder, n Node) {
+	var closing []string
+	for _, mark := range n.Marks {
+		if mark.Type == "link" {
+			href, _ := mark.Attrs["href"].(string)
+			if !safeHref(href) {
+				// Rendered as plain text, as the link could run script.
+				continue
+			}
+			b.WriteString(`<a href="` + html.EscapeString(href) + `">`)
+			closing = append(closing, "</a>")
+			continue
+		}
+		tag := markTags[mark.Type]
+		if t, _ := mark.Attrs["type"].(string); mark.Type == "subsup" && (t == "sub" || t == "sup") {
+			tag = t
+		}
+		if tag != "" {
+			b.WriteString("<" + tag + ">")
+			closing = append(closing, "</"+tag+">")
+		}
+	}
+
+	b.WriteString(html.EscapeString(n.Text))
+	for i := len(closing) - 1; i >= 0; i-- {
+		b.WriteString(closing[i])
+	}
+}
@@ -0,0 +1,40 @@
+package adf
+
+import "sync"
+
+// RendererFunc renders a node as plain text.
+type RendererFunc func(node Node) string
+
+var (
+	renderersMu sync.RWMutex
+	renderers   = map[string]RendererFunc{}
+)
+
+// RegisterRenderer sets the rendering of nodes of nodeType, such as
+// "extension" and "bodiedExtension" nodes injected by Jira apps, which are
+// otherwise rendered as their children only, usually nothing. A
+// registered renderer also replaces the built-in rendering of its node
+// type. It returns plain text: text rendering inserts it as is, so block
+// nodes should end with a blank line, and HTML rendering escapes it into a
+// span, with newlines as line breaks. To render the body of a
+// bodiedExtension, pass its children to ToText as
+// Node{Type: "doc", Content: node.Content}; passing the node itself would
+// recurse. Register renderers before converting, typically from an init
+// function; a nil fn restores the built-in rendering.
+func RegisterRenderer(nodeType string, fn RendererFunc) {
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	if fn == nil {
+		delete(renderers, nodeType)
+		return
+	}
+	renderers[nodeType] = fn
+}
+
+// lookupRenderer returns the renderer registered for nodeType, if any.
+func lookupRenderer(nodeType string) (RendererFunc, bool) {
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+	fn, ok := renderers[nodeType]
+	return fn, ok
+}
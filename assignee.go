@@ -0,0 +1,112 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Deployment types reported in ServerInfo.DeploymentType.
+const (
+	DeploymentCloud      = "Cloud"
+	DeploymentServer     = "Server"
+	DeploymentDataCenter = "DataCenter"
+)
+
+// ServerInfo describes the Jira instance.
+type ServerInfo struct {
+	BaseURL        string `json:"baseUrl"`
+	Version        string `json:"version"`
+	BuildNumber    int    `json:"buildNumber"`
+	DeploymentType string `json:"deploymentType"`
+	ServerTitle    string `json:"serverTitle"`
+}
+
+// IsCloud reports whether the instance is Jira Cloud.
+func (s ServerInfo) IsCloud() bool {
+	return s.DeploymentType == DeploymentCloud
+}
+
+// GetServerInfo returns information about the Jira instance. The result is
+// cached for the life of the client; failures are not cached.
+func (c *Client) GetServerInfo(ctx context.Context) (*ServerInfo, error) {
+	c.serverInfoMu.Lock()
+	defer c.serverInfoMu.Unlock()
+
+	if c.serverInfo != nil {
+		info := *c.serverInfo
+		return &info, nil
+	}
+
+	// The v2 endpoint exists on every deployment type.
+	var info ServerInfo
+	if err := c.do(ctx, http.MethodGet, "/rest/api/2/serverInfo", nil, nil, &info); err != nil {
+		return nil, err
+	}
+
+	c.serverInfo = &info
+	result := info
+	return &result, nil
+}
+
+// SetAssigneeByEmail assigns an issue to the user with the given email. On
+// Cloud the email is resolved to an account ID; on Server and Data Center,
+// which assign by username, it is resolved to the username. It fails when
+// the email matches no user or several. An empty email unassigns the issue.
+func (c *Client) SetAssigneeByEmail(ctx context.Context, issueKey, email string) error {
+	info, err := c.GetServerInfo(ctx)
+	if err != nil {
+		return fmt.Errorf("get server info: %w", err)
+	}
+
+	if !info.IsCloud() {
+		body := map[string]any{"name": nil}
+		if email != "" {
+			username, err := c.findUsername(ctx, email)
+			if err != nil {
+				return err
+			}
+			body["name"] = username
+		}
+		return c.do(ctx, http.MethodPut, "/rest/api/2/issue/"+url.PathEscape(issueKey)+"/assignee", nil, body, nil)
+	}
+
+	body := map[string]any{"accountId": nil}
+	if email != "" {
+		accountID, err := c.FindAccountID(ctx, email)
+		if err != nil {
+			return fmt.Errorf("resolve assignee: %w", err)
+		}
+		body["accountId"] = accountID
+	}
+	return c.do(ctx, http.MethodPut, "/rest/api/3/issue/"+url.PathEscape(issueKey)+"/assignee", nil, body, nil)
+}
+
+// findUsername resolves an email to a username on Server and Data Center.
+func (c *Client) findUsername(ctx context.Context, email string) (string, error) {
+	query := url.Values{}
+	query.Set("username", email)
+
+	var users []User
+	if err := c.do(ctx, http.MethodGet, "/rest/api/2/user/search", query, nil, &users); err != nil {
+		return "", fmt.Errorf("search users: %w", err)
+	}
+
+	var matches []User
+	for _, u := range users {
+		if strings.EqualFold(u.EmailAddress, email) {
+			matches = append(matches, u)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("resolve assignee: no user found for %s", email)
+	case 1:
+		return matches[0].Name, nil
+	default:
+		return "", fmt.Errorf("resolve assignee: multiple users found for %s", email)
+	}
+}
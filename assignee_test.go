@@ -0,0 +1,86 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSetAssigneeByEmail(t *testing.T) {
+	tests := []struct {
+		name       string
+		deployment string
+		email      string
+		wantPath   string
+		wantBody   string
+		wantErr    string
+	}{
+		{name: "cloud", deployment: DeploymentCloud, email: "Dana@acme.com", wantPath: "/rest/api/3/issue/PROJ-1/assignee", wantBody: `{"accountId":"acc-dana"}`},
+		{name: "cloud, hidden email", deployment: DeploymentCloud, email: "solo@acme.com", wantPath: "/rest/api/3/issue/PROJ-1/assignee", wantBody: `{"accountId":"acc-solo"}`},
+		{name: "cloud, unassign", deployment: DeploymentCloud, wantPath: "/rest/api/3/issue/PROJ-1/assignee", wantBody: `{"accountId":null}`},
+		{name: "cloud, ambiguous", deployment: DeploymentCloud, email: "team@acme.com", wantErr: "resolve assignee: multiple users found for team@acme.com"},
+		{name: "data center", deployment: DeploymentDataCenter, email: "dana@acme.com", wantPath: "/rest/api/2/issue/PROJ-1/assignee", wantBody: `{"name":"dana"}`},
+		{name: "data center, unassign", deployment: DeploymentDataCenter, wantPath: "/rest/api/2/issue/PROJ-1/assignee", wantBody: `{"name":null}`},
+		{name: "server, unknown", deployment: DeploymentServer, email: "nobody@acme.com", wantErr: "resolve assignee: no user found for nobody@acme.com"},
+	}
+
+	users := map[string][]any{
+		"dana@acme.com": {
+			map[string]any{"accountId": "acc-dana", "name": "dana", "emailAddress": "dana@acme.com"},
+			map[string]any{"accountId": "acc-dan", "name": "dan", "emailAddress": "dana@acme.com.au"},
+		},
+		"solo@acme.com": {map[string]any{"accountId": "acc-solo", "name": "solo"}},
+		"team@acme.com": {
+			map[string]any{"accountId": "acc-1", "emailAddress": "team@acme.com"},
+			map[string]any{"accountId": "acc-2", "emailAddress": "TEAM@acme.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var infoRequests atomic.Int32
+			var path, body string
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/rest/api/2/serverInfo":
+					infoRequests.Add(1)
+					writeJSON(w, map[string]any{"deploymentType": tt.deployment})
+				case "/rest/api/3/user/search":
+					writeJSON(w, users[strings.ToLower(r.URL.Query().Get("query"))])
+				case "/rest/api/2/user/search":
+					writeJSON(w, users[strings.ToLower(r.URL.Query().Get("username"))])
+				default:
+					var raw json.RawMessage
+					if err := decodeBody(r, &raw); err != nil {
+						t.Errorf("decode body: %v", err)
+					}
+					path, body = r.URL.Path, string(raw)
+					w.WriteHeader(http.StatusNoContent)
+				}
+			}), ClientConfig{})
+
+			for range 2 {
+				err := client.SetAssigneeByEmail(context.Background(), "PROJ-1", tt.email)
+				if tt.wantErr != "" {
+					if err == nil || err.Error() != tt.wantErr {
+						t.Fatalf("error = %v, want %q", err, tt.wantErr)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("SetAssigneeByEmail: %v", err)
+				}
+			}
+
+			if path != tt.wantPath || body != tt.wantBody {
+				t.Errorf("sent %s %s, want %s %s", path, body, tt.wantPath, tt.wantBody)
+			}
+			if n := infoRequests.Load(); n != 1 {
+				t.Errorf("server info requested %d times, want once", n)
+			}
+		})
+	}
+}
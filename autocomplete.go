@@ -0,0 +1,66 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+)
+
+// JQLMetadata lists the fields, functions and reserved words usable in JQL
+// queries by the caller.
+type JQLMetadata struct {
+	VisibleFieldNames    []JQLField    `json:"visibleFieldNames"`
+	VisibleFunctionNames []JQLFunction `json:"visibleFunctionNames"`
+	JQLReservedWords     []string      `json:"jqlReservedWords"`
+}
+
+// JQLField is a field that can be used in JQL. Jira reports the boolean
+// attributes as the strings "true" and "false".
+type JQLField struct {
+	Value       string   `json:"value"`
+	DisplayName string   `json:"displayName"`
+	Orderable   string   `json:"orderable,omitempty"`
+	Searchable  string   `json:"searchable,omitempty"`
+	Auto        string   `json:"auto,omitempty"`
+	CfID        string   `json:"cfid,omitempty"`
+	Operators   []string `json:"operators"`
+	Types       []string `json:"types"`
+}
+
+// JQLFunction is a function that can be used in JQL.
+type JQLFunction struct {
+	Value       string   `json:"value"`
+	DisplayName string   `json:"displayName"`
+	IsList      string   `json:"isList,omitempty"`
+	Types       []string `json:"types"`
+}
+
+// Field returns the field whose value (the name used in JQL) is name.
+func (m *JQLMetadata) Field(name string) (JQLField, bool) {
+	for _, f := range m.VisibleFieldNames {
+		if f.Value == name {
+			return f, true
+		}
+	}
+	return JQLField{}, false
+}
+
+// GetJQLAutocomplete returns the JQL autocomplete data, for validating and
+// suggesting queries client-side. The result is cached for the life of the
+// client; failures are not cached. The returned value is shared and must
+// not be modified.
+func (c *Client) GetJQLAutocomplete(ctx context.Context) (*JQLMetadata, error) {
+	c.jqlMetadataMu.Lock()
+	defer c.jqlMetadataMu.Unlock()
+
+	if c.jqlMetadata != nil {
+		return c.jqlMetadata, nil
+	}
+
+	var meta JQLMetadata
+	if err := c.do(ctx, http.MethodGet, "/rest/api/3/jql/autocompletedata", nil, nil, &meta); err != nil {
+		return nil, err
+	}
+
+	c.jqlMetadata = &meta
+	return c.jqlMetadata, nil
+}
@@ -0,0 +1,63 @@
+package jira
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+const autocompleteResponse = `{
+	"visibleFieldNames": [
+		{"value": "status", "displayName": "Status", "orderable": "true", "searchable": "true", "operators": ["=", "!=", "in", "not in", "was"], "types": ["com.atlassian.jira.issue.status.Status"]},
+		{"value": "cf[10016]", "displayName": "Story Points - cf[10016]", "orderable": "true", "searchable": "true", "cfid": "cf[10016]", "operators": ["=", ">", "<"], "types": ["java.lang.Number"]}
+	],
+	"visibleFunctionNames": [
+		{"value": "currentUser()", "displayName": "currentUser()", "types": ["com.atlassian.jira.user.ApplicationUser"]},
+		{"value": "membersOf(\"\")", "displayName": "membersOf(\"\")", "isList": "true", "types": ["com.atlassian.jira.user.ApplicationUser"]}
+	],
+	"jqlReservedWords": ["and", "or", "order"]
+}`
+
+func TestGetJQLAutocomplete(t *testing.T) {
+	var requests atomic.Int32
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/rest/api/3/jql/autocompletedata" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, autocompleteResponse)
+	}), ClientConfig{})
+
+	meta, err := client.GetJQLAutocomplete(context.Background())
+	if err != nil {
+		t.Fatalf("GetJQLAutocomplete: %v", err)
+	}
+
+	status, ok := meta.Field("status")
+	if !ok || status.DisplayName != "Status" || len(status.Operators) != 5 || status.Types[0] != "com.atlassian.jira.issue.status.Status" {
+		t.Errorf("status = %+v, %v, want its operators and type", status, ok)
+	}
+	if points, _ := meta.Field("cf[10016]"); points.CfID != "cf[10016]" || points.Orderable != "true" {
+		t.Errorf("story points = %+v, want cfid cf[10016], orderable", points)
+	}
+	if _, ok := meta.Field("missing"); ok {
+		t.Errorf("Field(missing) found a field")
+	}
+	if len(meta.VisibleFunctionNames) != 2 || meta.VisibleFunctionNames[1].IsList != "true" {
+		t.Errorf("functions = %+v, want 2 with membersOf a list", meta.VisibleFunctionNames)
+	}
+	if len(meta.JQLReservedWords) != 3 {
+		t.Errorf("reserved words = %v, want 3", meta.JQLReservedWords)
+	}
+
+	if _, err := client.GetJQLAutocomplete(context.Background()); err != nil {
+		t.Fatalf("second GetJQLAutocomplete: %v", err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("sent %d requests, want 1 with the cache", n)
+	}
+}
@@ -0,0 +1,105 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/resolute-sh/resolute/core"
+)
+
+// BoardConfiguration is the column layout of an agile board.
+type BoardConfiguration struct {
+	ID      int           `json:"id"`
+	Name    string        `json:"name"`
+	Columns []BoardColumn `json:"columns"`
+}
+
+// BoardColumn is a board column, in board order, with the IDs of the
+// statuses mapped to it. Min and Max are the column's issue count
+// constraints, nil when unset.
+type BoardColumn struct {
+	Name      string   `json:"name"`
+	StatusIDs []string `json:"statusIds"`
+	Min       *int     `json:"min,omitempty"`
+	Max       *int     `json:"max,omitempty"`
+}
+
+// GetBoardConfiguration returns a board's columns and their mapped
+// statuses.
+func (c *Client) GetBoardConfiguration(ctx context.Context, boardID int) (*BoardConfiguration, error) {
+	var resp struct {
+		ID           int    `json:"id"`
+		Name         string `json:"name"`
+		ColumnConfig struct {
+			Columns []struct {
+				Name     string `json:"name"`
+				Min      *int   `json:"min"`
+				Max      *int   `json:"max"`
+				Statuses []struct {
+					ID string `json:"id"`
+				} `json:"statuses"`
+			} `json:"columns"`
+		} `json:"columnConfig"`
+	}
+	path := fmt.Sprintf("/rest/agile/1.0/board/%d/configuration", boardID)
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	config := &BoardConfiguration{ID: resp.ID, Name: resp.Name}
+	for _, col := range resp.ColumnConfig.Columns {
+		column := BoardColumn{Name: col.Name, Min: col.Min, Max: col.Max, StatusIDs: []string{}}
+		for _, status := range col.Statuses {
+			column.StatusIDs = append(column.StatusIDs, status.ID)
+		}
+		config.Columns = append(config.Columns, column)
+	}
+
+	return config, nil
+}
+
+// FetchBoardColumnsInput is the input for FetchBoardColumnsActivity.
+type FetchBoardColumnsInput struct {
+	BaseURL  string
+	Email    string
+	APIToken string
+	BoardID  int
+
+	ClientOptions
+}
+
+// FetchBoardColumnsOutput is the output of FetchBoardColumnsActivity.
+type FetchBoardColumnsOutput struct {
+	BoardName string
+	Columns   []BoardColumn
+}
+
+// FetchBoardColumnsActivity returns a board's columns in order with their
+// mapped status IDs, for computing time in column rather than in status.
+func FetchBoardColumnsActivity(ctx context.Context, input FetchBoardColumnsInput) (FetchBoardColumnsOutput, error) {
+	client, err := input.ClientOptions.newClient(ClientConfig{
+		BaseURL:  input.BaseURL,
+		Email:    input.Email,
+		APIToken: input.APIToken,
+	})
+	if err != nil {
+		return FetchBoardColumnsOutput{}, err
+	}
+	defer client.Close()
+
+	config, err := client.GetBoardConfiguration(ctx, input.BoardID)
+	if err != nil {
+		return FetchBoardColumnsOutput{}, fmt.Errorf("get board configuration: %w", err)
+	}
+
+	return FetchBoardColumnsOutput{
+		BoardName: config.Name,
+		Columns:   config.Columns,
+	}, nil
+}
+
+// FetchBoardColumns creates a node for fetching a board's columns.
+func FetchBoardColumns(input FetchBoardColumnsInput) *core.Node[FetchBoardColumnsInput, FetchBoardColumnsOutput] {
+	return core.NewNode("jira.FetchBoardColumns", FetchBoardColumnsActivity, input)
+}
@@ -0,0 +1,56 @@
+package jira
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+)
+
+const boardConfigurationResponse = `{
+	"id": 7,
+	"name": "PROJ board",
+	"type": "kanban",
+	"columnConfig": {
+		"columns": [
+			{"name": "Backlog", "statuses": []},
+			{"name": "To Do", "statuses": [{"id": "10000", "self": "https://example.atlassian.net/rest/api/2/status/10000"}]},
+			{"name": "In Progress", "max": 3, "statuses": [{"id": "3"}, {"id": "10001"}]},
+			{"name": "Done", "min": 1, "statuses": [{"id": "10002"}]}
+		],
+		"constraintType": "issueCount"
+	}
+}`
+
+func TestFetchBoardColumnsActivity(t *testing.T) {
+	fake := &fakeJira{routes: map[string]http.HandlerFunc{
+		"/rest/agile/1.0/board/7/configuration": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			io.WriteString(w, boardConfigurationResponse)
+		},
+	}}
+
+	out, err := runActivity(t, FetchBoardColumnsActivity, FetchBoardColumnsInput{BaseURL: fake.start(t), BoardID: 7})
+	if err != nil {
+		t.Fatalf("FetchBoardColumnsActivity: %v", err)
+	}
+
+	if out.BoardName != "PROJ board" {
+		t.Errorf("board name = %q, want PROJ board", out.BoardName)
+	}
+	var columns []string
+	for _, column := range out.Columns {
+		limits := ""
+		if column.Min != nil {
+			limits += fmt.Sprintf(" min %d", *column.Min)
+		}
+		if column.Max != nil {
+			limits += fmt.Sprintf(" max %d", *column.Max)
+		}
+		columns = append(columns, fmt.Sprintf("%s%v%s", column.Name, column.StatusIDs, limits))
+	}
+	want := "[Backlog[] To Do[10000] In Progress[3 10001] max 3 Done[10002] min 1]"
+	if got := fmt.Sprint(columns); got != want {
+		t.Errorf("columns = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,122 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/resolute-sh/resolute/core"
+	"go.temporal.io/sdk/activity"
+)
+
+// BulkAddLabelInput is the input for BulkAddLabelActivity.
+type BulkAddLabelInput struct {
+	BaseURL  string
+	Email    string
+	APIToken string
+	JQL      string
+	Labels   []string
+
+	// Concurrency bounds the number of issues edited at once. Default 4.
+	Concurrency int
+
+	MaxResults int // per search page, default 100
+
+	ClientOptions
+}
+
+// BulkAddLabelOutput is the output of BulkAddLabelActivity.
+type BulkAddLabelOutput struct {
+	Matched    int
+	Succeeded  int
+	Failed     int
+	FailedKeys []string
+}
+
+// BulkAddLabelActivity adds labels to every issue matching a JQL query. The
+// matching keys are collected first, so edits cannot shift the pages of
+// the search, and then labeled with bounded concurrency. Adding a label is
+// idempotent, so the activity is safe to re-run after a partial failure.
+// Per-issue failures are counted rather than returned; cancellation stops
+// further edits and returns the context's error.
+func BulkAddLabelActivity(ctx context.Context, input BulkAddLabelInput) (BulkAddLabelOutput, error) {
+	if len(input.Labels) == 0 {
+		return BulkAddLabelOutput{}, fmt.Errorf("labels must be set")
+	}
+
+	client, err := input.ClientOptions.newClient(ClientConfig{
+		BaseURL:  input.BaseURL,
+		Email:    input.Email,
+		APIToken: input.APIToken,
+	})
+	if err != nil {
+		return BulkAddLabelOutput{}, err
+	}
+	defer client.Close()
+
+	var keys []string
+	_, _, err = paginateSearch(ctx, client, input.JQL, paginateOptions{
+		PageSize: input.MaxResults,
+		Fields:   []string{"key"},
+	}, func(issues []Issue) error {
+		for _, issue := range issues {
+			keys = append(keys, issue.Key)
+		}
+		return nil
+	})
+	if err != nil {
+		return BulkAddLabelOutput{}, err
+	}
+
+	concurrency := input.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	out := BulkAddLabelOutput{Matched: len(keys)}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for _, key := range keys {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			err := client.AddLabels(ctx, key, input.Labels...)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				client.logger.WarnContext(ctx, "jira: add labels failed", "issue", key, "error", err)
+				out.Failed++
+				out.FailedKeys = append(out.FailedKeys, key)
+			} else {
+				out.Succeeded++
+			}
+			activity.RecordHeartbeat(ctx, out.Succeeded+out.Failed)
+		}(key)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return out, err
+	}
+
+	return out, nil
+}
+
+// BulkAddLabel creates a node for adding labels to every issue matching a JQL query.
+func BulkAddLabel(input BulkAddLabelInput) *core.Node[BulkAddLabelInput, BulkAddLabelOutput] {
+	return core.NewNode("jira.BulkAddLabel", BulkAddLabelActivity, input).
+		WithTimeout(30 * time.Minute)
+}
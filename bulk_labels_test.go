@@ -0,0 +1,51 @@
+package jira
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func TestBulkAddLabelActivity(t *testing.T) {
+	var mu sync.Mutex
+	var labeled []string
+	fake := &fakeJira{issues: testIssues(5, false), routes: map[string]http.HandlerFunc{}}
+	for i := 1; i <= 5; i++ {
+		key := fmt.Sprintf("PROJ-%d", i)
+		fake.routes["/rest/api/3/issue/"+key] = func(w http.ResponseWriter, r *http.Request) {
+			if key == "PROJ-3" {
+				http.Error(w, `{"errorMessages":["Field 'labels' cannot be set."]}`, http.StatusBadRequest)
+				return
+			}
+			mu.Lock()
+			labeled = append(labeled, key)
+			mu.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+
+	out, err := runActivity(t, BulkAddLabelActivity, BulkAddLabelInput{
+		BaseURL:     fake.start(t),
+		JQL:         "project = PROJ",
+		Labels:      []string{"migrated"},
+		Concurrency: 2,
+		MaxResults:  2,
+	})
+	if err != nil {
+		t.Fatalf("BulkAddLabelActivity: %v", err)
+	}
+	if out.Matched != 5 || out.Succeeded != 4 || out.Failed != 1 || fmt.Sprint(out.FailedKeys) != "[PROJ-3]" {
+		t.Errorf("output = %+v, want 5 matched, 4 succeeded, PROJ-3 failed", out)
+	}
+	if len(labeled) != 4 {
+		t.Errorf("labeled %v, want 4 issues", labeled)
+	}
+	if searches := fake.recorded(); len(searches) != 3 {
+		t.Errorf("%d searches, want 3 pages of 2", len(searches))
+	}
+
+	if _, err := runActivity(t, BulkAddLabelActivity, BulkAddLabelInput{BaseURL: fake.start(t), JQL: "project = PROJ"}); err == nil {
+		t.Errorf("BulkAddLabelActivity without labels succeeded")
+	}
+}
@@ -0,0 +1,59 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ChangelogEntry is one history entry of an issue's changelog.
+type ChangelogEntry struct {
+	ID      string       `json:"id"`
+	Author  User         `json:"author"`
+	Created string       `json:"created"`
+	Items   []ChangeItem `json:"items"`
+}
+
+// ChangeItem is a single field change within a changelog entry.
+type ChangeItem struct {
+	Field      string `json:"field"`
+	FieldID    string `json:"fieldId"`
+	From       string `json:"from"`
+	FromString string `json:"fromString"`
+	To         string `json:"to"`
+	ToString   string `json:"toString"`
+}
+
+// changelogPage is a page of an issue's changelog.
+type changelogPage struct {
+	StartAt    int              `json:"startAt"`
+	MaxResults int              `json:"maxResults"`
+	Total      int              `json:"total"`
+	IsLast     bool             `json:"isLast"`
+	Values     []ChangelogEntry `json:"values"`
+}
+
+// GetChangelog returns the full changelog of an issue, oldest first.
+func (c *Client) GetChangelog(ctx context.Context, issueKey string) ([]ChangelogEntry, error) {
+	path := "/rest/api/3/issue/" + url.PathEscape(issueKey) + "/changelog"
+
+	var entries []ChangelogEntry
+	startAt := 0
+	for {
+		query := url.Values{}
+		query.Set("startAt", strconv.Itoa(startAt))
+		query.Set("maxResults", "100")
+
+		var page changelogPage
+		if err := c.do(ctx, http.MethodGet, path, query, nil, &page); err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, page.Values...)
+		startAt += len(page.Values)
+		if page.IsLast || len(page.Values) == 0 || startAt >= page.Total {
+			return entries, nil
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package jira
+
+import "strings"
+
+// Classifier derives a normalized category, such as "bug" or "feature",
+// from an issue. An empty result writes no category.
+type Classifier func(Issue) string
+
+// DefaultClassifierName names the built-in DefaultClassifier.
+const DefaultClassifierName = "default"
+
+var classifiers = newRegistry[Classifier]("classifier")
+
+func init() {
+	classifiers.register(DefaultClassifierName, DefaultClassifier)
+}
+
+// RegisterClassifier makes a classifier available under name for the
+// DocumentOptions.ClassifierName option.
+func RegisterClassifier(name string, classifier Classifier) {
+	classifiers.register(name, classifier)
+}
+
+// classifier returns Classifier, or else the classifier registered under
+// ClassifierName.
+func (opts DocumentOptions) classifier() (Classifier, error) {
+	if opts.Classifier != nil {
+		return opts.Classifier, nil
+	}
+	return classifiers.lookup(opts.ClassifierName)
+}
+
+// DefaultClassifier maps common issue type names to "bug", "feature",
+// "task", "epic" or "support", and anything else to "other".
+func DefaultClassifier(issue Issue) string {
+	switch strings.ToLower(strings.TrimSpace(issue.Fields.IssueType.Name)) {
+	case "bug", "defect", "incident", "problem", "error":
+		return "bug"
+	case "story", "user story", "feature", "new feature", "improvement", "enhancement":
+		return "feature"
+	case "task", "sub-task", "subtask", "technical task", "chore", "spike":
+		return "task"
+	case "epic", "initiative":
+		return "epic"
+	case "question", "support", "service request", "it help":
+		return "support"
+	default:
+		return "other"
+	}
+}
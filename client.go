@@ -1,13 +1,23 @@
 package jira
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/resolute-sh/resolute-jira/adf"
+	"github.com/resolute-sh/resolute-jira/jql"
 )
 
 // Client is a Jira REST API client.
@@ -16,21 +26,180 @@ type Client struct {
 	email      string
 	apiToken   string
 	httpClient *http.Client
+	logger     *slog.Logger
+	metrics    Metrics
+
+	slowRequestThreshold time.Duration
+	requestTimeout       time.Duration
+
+	extraHeaders      http.Header
+	allowAuthOverride bool
+	impersonation     *impersonation
+
+	// configErr fails every request of a client built from an invalid
+	// ClientConfig, as NewClient does not return an error.
+	configErr error
+
+	cursorSearch  bool
+	updateHistory bool
+	closed        atomic.Bool
+
+	rateLimitMu sync.Mutex
+	rateLimit   RateLimitInfo
+
+	serverInfoMu sync.Mutex
+	serverInfo   *ServerInfo
+
+	jqlMetadataMu sync.Mutex
+	jqlMetadata   *JQLMetadata
+
+	sprintCacheMu sync.Mutex
+	sprintCache   map[int]Sprint
+
+	parentCacheMu sync.Mutex
+	parentCache   map[string]Issue
+
+	linkTypesMu sync.Mutex
+	linkTypes   []IssueLinkType
+
+	userLocationMu sync.Mutex
+	userLocation   *time.Location
+
+	projectCacheMu sync.Mutex
+	projectCache   map[string]Project
+
+	versionCacheMu sync.Mutex
+	versionCache   map[string][]Version
+
+	componentCacheMu sync.Mutex
+	componentCache   map[string][]Component
 }
 
 // ClientConfig contains configuration for creating a Jira client.
+//
+// By default the client does not bound the total duration of a request,
+// so large responses that keep streaming are allowed to complete. Use the
+// request context or RequestTimeout to bound overall duration.
 type ClientConfig struct {
 	BaseURL  string
 	Email    string
 	APIToken string
-	Timeout  time.Duration
+
+	// Timeout is the default for ResponseHeaderTimeout when it is unset.
+	Timeout time.Duration
+
+	// DialTimeout bounds establishing the TCP connection and the TLS
+	// handshake. Default 10s.
+	DialTimeout time.Duration
+
+	// ResponseHeaderTimeout bounds waiting for the response headers after
+	// the request is written. It does not apply to reading the body.
+	// Default 30s.
+	ResponseHeaderTimeout time.Duration
+
+	// RequestTimeout bounds each request, response body included. The
+	// deadline is the earlier of now plus RequestTimeout and the caller's
+	// context deadline, so a request started near the end of an activity
+	// only gets the time the activity has left. Zero leaves requests bound
+	// by the context alone.
+	RequestTimeout time.Duration
+
+	// Logger receives warnings about degraded behavior. Default slog.Default().
+	Logger *slog.Logger
+
+	// Transport sends requests. It defaults to NewBaseTransport wrapped
+	// with WithRetry(3) and, with RequestsPerSecond, WithRateLimit. Build
+	// custom chains with Chain and the With* middlewares; requests are
+	// already authenticated when they reach it. When set, the timeout
+	// fields above only apply if the chain is built on NewBaseTransport.
+	Transport http.RoundTripper
+
+	// Metrics receives request counts, retries and latencies, e.g. for
+	// Prometheus; see Metrics. Default no-op.
+	Metrics Metrics
+
+	// SlowRequestThreshold logs a warning with the URL and duration of
+	// every request taking longer, retries included, and reports it to
+	// Metrics when it implements SlowRequestObserver. Zero disables it.
+	SlowRequestThreshold time.Duration
+
+	// ShouldRetry overrides which failed attempts the default transport
+	// retries; see WithRetryFunc. Nil keeps the default classification. It
+	// has no effect with a custom Transport, whose chain should use
+	// WithRetryFunc instead.
+	ShouldRetry RetryFunc
+
+	// RequestsPerSecond, when positive, spaces the attempts of the default
+	// transport, retries included, so that at most this many are sent per
+	// second; see WithRateLimit. It has no effect with a custom Transport.
+	RequestsPerSecond float64
+
+	// ExtraHeaders are set on every request after authentication, e.g. a
+	// token for an API gateway in front of Jira. They are applied before
+	// the request reaches Transport, so middlewares can still change them.
+	// An Authorization entry is ignored unless AllowAuthOverride is set.
+	ExtraHeaders map[string]string
+
+	// AllowAuthOverride lets ExtraHeaders replace the basic auth
+	// Authorization header, e.g. with a bearer token. It cannot be combined
+	// with ImpersonateAccountID, which sets its own Authorization header;
+	// requests of such a client fail.
+	AllowAuthOverride bool
+
+	// ImpersonateAccountID makes an Atlassian Connect app act as this user,
+	// so e.g. comments are attributed to the user rather than the app. It
+	// replaces basic auth with an access token obtained from Atlassian's
+	// authorization server through the JWT bearer grant, signed with
+	// ConnectSharedSecret and cached until shortly before it expires. The
+	// app descriptor must declare the ACT_AS_USER scope along with
+	// ImpersonationScopes, and ConnectOAuthClientID and ConnectSharedSecret
+	// come from the app's installation payload (oauthClientId and
+	// sharedSecret). Only Jira Cloud supports it; Email and APIToken are
+	// then unused.
+	ImpersonateAccountID string
+	ConnectOAuthClientID string
+	ConnectSharedSecret  string
+
+	// ImpersonationScopes are the scopes requested for the impersonated
+	// user besides ACT_AS_USER. Default READ and WRITE.
+	ImpersonationScopes []string
+
+	// CursorSearch uses the enhanced /rest/api/3/search/jql endpoint, which
+	// pages with a token and does not report a total count.
+	CursorSearch bool
+
+	// UpdateHistory adds issues read with GetIssue to the authenticated
+	// user's "recently viewed" history, as opening them in the browser
+	// would. It is off by default so service accounts indexing issues do
+	// not fill their history.
+	UpdateHistory bool
 }
 
 // NewClient creates a new Jira client.
 func NewClient(cfg ClientConfig) *Client {
-	timeout := cfg.Timeout
-	if timeout == 0 {
-		timeout = 30 * time.Second
+	transport := cfg.Transport
+	if transport == nil {
+		transport = defaultTransport(cfg)
+	}
+
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	metrics := cfg.Metrics
+	if metrics == nil {
+		metrics = nopMetrics{}
+	}
+
+	extraHeaders := make(http.Header, len(cfg.ExtraHeaders))
+	for name, value := range cfg.ExtraHeaders {
+		extraHeaders.Set(name, value)
+	}
+
+	var configErr error
+	if cfg.ImpersonateAccountID != "" && cfg.AllowAuthOverride && extraHeaders.Get("Authorization") != "" {
+		configErr = errors.New("invalid client config: an Authorization header in ExtraHeaders cannot be combined with ImpersonateAccountID")
 	}
 
 	return &Client{
@@ -38,8 +207,18 @@ func NewClient(cfg ClientConfig) *Client {
 		email:    cfg.Email,
 		apiToken: cfg.APIToken,
 		httpClient: &http.Client{
-			Timeout: timeout,
+			Transport: transport,
 		},
+		logger:               logger,
+		metrics:              metrics,
+		slowRequestThreshold: cfg.SlowRequestThreshold,
+		requestTimeout:       cfg.RequestTimeout,
+		extraHeaders:         extraHeaders,
+		allowAuthOverride:    cfg.AllowAuthOverride,
+		impersonation:        newImpersonation(cfg),
+		configErr:            configErr,
+		cursorSearch:         cfg.CursorSearch,
+		updateHistory:        cfg.UpdateHistory,
 	}
 }
 
@@ -49,34 +228,177 @@ type Issue struct {
 	Key    string      `json:"key"`
 	Self   string      `json:"self"`
 	Fields IssueFields `json:"fields"`
+
+	// Names maps field IDs to their display names, e.g.
+	// "customfield_10016" to "Story Points", when the "names" expansion
+	// was requested. In search results the map is shared by all issues of
+	// a page and must not be modified.
+	Names map[string]string `json:"names,omitempty"`
+
+	// Properties holds the issue entity properties requested with
+	// SearchJQLParams.RequestProperties, keyed by property key.
+	Properties map[string]json.RawMessage `json:"properties,omitempty"`
 }
 
 // IssueFields contains the fields of a Jira issue.
 type IssueFields struct {
-	Summary     string      `json:"summary"`
-	Description string      `json:"description"`
-	Status      Status      `json:"status"`
-	IssueType   IssueType   `json:"issuetype"`
-	Project     Project     `json:"project"`
-	Created     string      `json:"created"`
-	Updated     string      `json:"updated"`
-	Labels      []string    `json:"labels"`
-	Priority    *Priority   `json:"priority"`
-	Assignee    *User       `json:"assignee"`
-	Reporter    *User       `json:"reporter"`
-	Comments    *Comments   `json:"comment"`
+	Summary     string       `json:"summary"`
+	Description string       `json:"description"`
+	Environment string       `json:"environment"`
+	Status      Status       `json:"status"`
+	IssueType   IssueType    `json:"issuetype"`
+	Project     Project      `json:"project"`
+	Created     string       `json:"created"`
+	Updated     string       `json:"updated"`
+	DueDate     string       `json:"duedate"`
+	Labels      []string     `json:"labels"`
+	FixVersions []Version    `json:"fixVersions"`
+	Components  []Component  `json:"components"`
+	Priority    *Priority    `json:"priority"`
+	Assignee    *User        `json:"assignee"`
+	Reporter    *User        `json:"reporter"`
+	Comments    *Comments    `json:"comment"`
+	IssueLinks  []IssueLink  `json:"issuelinks"`
+	Attachments []Attachment `json:"attachment"`
+
+	// Parent is the parent issue of a sub-task, or of an issue under an
+	// epic in team-managed projects.
+	Parent *LinkedIssue `json:"parent"`
+
+	// TimeTracking is nil when time tracking is disabled or the field was
+	// not requested.
+	TimeTracking *TimeTracking `json:"timetracking"`
+
+	// The aggregate fields roll up the issue's own time tracking with that
+	// of its sub-tasks or children, in seconds. They are nil when unset.
+	AggregateOriginalEstimate  *int64    `json:"aggregatetimeoriginalestimate"`
+	AggregateRemainingEstimate *int64    `json:"aggregatetimeestimate"`
+	AggregateTimeSpent         *int64    `json:"aggregatetimespent"`
+	AggregateProgress          *Progress `json:"aggregateprogress"`
+
+	// DescriptionADF holds the description's original ADF document when
+	// Jira returned one; Description holds its plain-text rendering.
+	DescriptionADF json.RawMessage `json:"descriptionADF,omitempty"`
+
+	// EnvironmentADF is to Environment what DescriptionADF is to
+	// Description.
+	EnvironmentADF json.RawMessage `json:"environmentADF,omitempty"`
+
+	// CustomFields holds the raw values of customfield_* fields keyed by
+	// field ID.
+	CustomFields map[string]json.RawMessage `json:"customFields,omitempty"`
+}
+
+// Progress is the work logged against the total of time spent and
+// remaining, in seconds. Percent is only reported when Total is positive.
+type Progress struct {
+	Progress int64 `json:"progress"`
+	Total    int64 `json:"total"`
+	Percent  *int  `json:"percent,omitempty"`
+}
+
+// TimeTracking holds an issue's aggregate estimates and time spent. Each
+// duration is present only when set, as a Jira duration string ("1w 2d")
+// and in seconds.
+type TimeTracking struct {
+	OriginalEstimate         string `json:"originalEstimate,omitempty"`
+	RemainingEstimate        string `json:"remainingEstimate,omitempty"`
+	TimeSpent                string `json:"timeSpent,omitempty"`
+	OriginalEstimateSeconds  int64  `json:"originalEstimateSeconds,omitempty"`
+	RemainingEstimateSeconds int64  `json:"remainingEstimateSeconds,omitempty"`
+	TimeSpentSeconds         int64  `json:"timeSpentSeconds,omitempty"`
+}
+
+// UnmarshalJSON decodes issue fields, converting an ADF description to
+// plain text and collecting customfield_* values into CustomFields.
+func (f *IssueFields) UnmarshalJSON(data []byte) error {
+	type plain IssueFields
+	aux := struct {
+		*plain
+		Description json.RawMessage `json:"description"`
+		Environment json.RawMessage `json:"environment"`
+	}{plain: (*plain)(f)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if aux.Description != nil {
+		// A malformed description is kept in DescriptionADF rather than
+		// failing the decode, so one bad issue does not fail a whole
+		// search page; document conversion reports it.
+		description, err := adf.PlainText(aux.Description)
+		f.Description = description
+		if err != nil || adf.IsDocument(aux.Description) {
+			f.DescriptionADF = aux.Description
+		}
+	}
+
+	if aux.Environment != nil {
+		environment, err := adf.PlainText(aux.Environment)
+		f.Environment = environment
+		if err != nil || adf.IsDocument(aux.Environment) {
+			f.EnvironmentADF = aux.Environment
+		}
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	for key, value := range raw {
+		if !strings.HasPrefix(key, "customfield_") {
+			continue
+		}
+		if f.CustomFields == nil {
+			f.CustomFields = make(map[string]json.RawMessage)
+		}
+		f.CustomFields[key] = value
+	}
+
+	return nil
+}
+
+// timeLayouts are the timestamp formats Jira uses, most common first.
+var timeLayouts = []string{
+	"2006-01-02T15:04:05.000-0700",
+	"2006-01-02T15:04:05-0700",
+	time.RFC3339Nano,
+	"2006-01-02",
+}
+
+// parseTime parses a Jira timestamp.
+func parseTime(s string) (time.Time, error) {
+	var err error
+	for _, layout := range timeLayouts {
+		var t time.Time
+		if t, err = time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("parse time %q: %w", s, err)
 }
 
 // Status represents an issue status.
 type Status struct {
+	Name           string         `json:"name"`
+	ID             string         `json:"id"`
+	StatusCategory StatusCategory `json:"statusCategory"`
+}
+
+// StatusCategory groups statuses across workflows. Key is one of "new",
+// "indeterminate" and "done"; Name is "To Do", "In Progress" or "Done".
+type StatusCategory struct {
+	ID   int    `json:"id"`
+	Key  string `json:"key"`
 	Name string `json:"name"`
-	ID   string `json:"id"`
 }
 
 // IssueType represents an issue type.
 type IssueType struct {
-	Name string `json:"name"`
-	ID   string `json:"id"`
+	Name    string `json:"name"`
+	ID      string `json:"id"`
+	Subtask bool   `json:"subtask"`
 }
 
 // Project represents a Jira project.
@@ -84,6 +406,9 @@ type Project struct {
 	Key  string `json:"key"`
 	Name string `json:"name"`
 	ID   string `json:"id"`
+
+	// ProjectCategory is nil when the project has no category.
+	ProjectCategory *ProjectCategory `json:"projectCategory,omitempty"`
 }
 
 // Priority represents issue priority.
@@ -97,6 +422,77 @@ type User struct {
 	DisplayName  string `json:"displayName"`
 	EmailAddress string `json:"emailAddress"`
 	AccountID    string `json:"accountId"`
+
+	// Name is the username, only reported by Server and Data Center.
+	Name string `json:"name,omitempty"`
+
+	// TimeZone is the IANA time zone of the user's profile, e.g.
+	// "Europe/Berlin", in which Jira interprets JQL dates. Only reported
+	// for the authenticated user and users sharing their time zone.
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// IssueLink represents a link between two issues. Exactly one of
+// InwardIssue and OutwardIssue is set, relative to the issue holding the link.
+type IssueLink struct {
+	ID           string        `json:"id"`
+	Type         IssueLinkType `json:"type"`
+	InwardIssue  *LinkedIssue  `json:"inwardIssue,omitempty"`
+	OutwardIssue *LinkedIssue  `json:"outwardIssue,omitempty"`
+}
+
+// IssueLinkType describes a link type and its directional phrases,
+// e.g. Name "Blocks", Inward "is blocked by", Outward "blocks".
+type IssueLinkType struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Inward  string `json:"inward"`
+	Outward string `json:"outward"`
+}
+
+// LinkedIssue is the abbreviated issue embedded in an IssueLink or as an
+// issue's parent.
+type LinkedIssue struct {
+	ID     string `json:"id"`
+	Key    string `json:"key"`
+	Self   string `json:"self"`
+	Fields struct {
+		Summary string `json:"summary"`
+		Status  Status `json:"status"`
+	} `json:"fields"`
+}
+
+// Describe renders the link from the holding issue's point of view,
+// e.g. "blocks PROJ-2" or "is blocked by PROJ-2".
+func (l IssueLink) Describe() string {
+	switch {
+	case l.OutwardIssue != nil:
+		return linkPhrase(l.Type.Outward, l.Type.Name) + " " + l.OutwardIssue.Key
+	case l.InwardIssue != nil:
+		return linkPhrase(l.Type.Inward, l.Type.Name) + " " + l.InwardIssue.Key
+	default:
+		return ""
+	}
+}
+
+func linkPhrase(phrase, name string) string {
+	if phrase != "" {
+		return phrase
+	}
+	return strings.ToLower(name)
+}
+
+// Attachment represents a file attached to an issue.
+type Attachment struct {
+	ID        string `json:"id"`
+	Self      string `json:"self"`
+	Filename  string `json:"filename"`
+	Author    User   `json:"author"`
+	Created   string `json:"created"`
+	Size      int64  `json:"size"`
+	MimeType  string `json:"mimeType"`
+	Content   string `json:"content"`
+	Thumbnail string `json:"thumbnail,omitempty"`
 }
 
 // Comments represents issue comments.
@@ -112,14 +508,67 @@ type Comment struct {
 	Author  User   `json:"author"`
 	Created string `json:"created"`
 	Updated string `json:"updated"`
+
+	// BodyADF holds the body's original ADF document when Jira returned
+	// one; Body holds its plain-text rendering.
+	BodyADF json.RawMessage `json:"bodyADF,omitempty"`
+}
+
+// UnmarshalJSON decodes a comment, converting an ADF body to plain text.
+func (c *Comment) UnmarshalJSON(data []byte) error {
+	type plain Comment
+	aux := struct {
+		*plain
+		Body json.RawMessage `json:"body"`
+	}{plain: (*plain)(c)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if aux.Body != nil {
+		// As with descriptions, a malformed body is kept for document
+		// conversion to report.
+		body, err := adf.PlainText(aux.Body)
+		c.Body = body
+		if err != nil || adf.IsDocument(aux.Body) {
+			c.BodyADF = aux.Body
+		}
+	}
+
+	return nil
 }
 
 // SearchResult represents a JQL search result.
+//
+// In cursor-search mode Total is not reported; page with NextPageToken
+// until IsLast instead.
 type SearchResult struct {
-	StartAt    int     `json:"startAt"`
-	MaxResults int     `json:"maxResults"`
-	Total      int     `json:"total"`
-	Issues     []Issue `json:"issues"`
+	StartAt       int     `json:"startAt"`
+	MaxResults    int     `json:"maxResults"`
+	Total         int     `json:"total"`
+	Issues        []Issue `json:"issues"`
+	NextPageToken string  `json:"nextPageToken,omitempty"`
+	IsLast        bool    `json:"isLast,omitempty"`
+
+	// Names is the page's field ID to display name map when the "names"
+	// expansion was requested.
+	Names map[string]string `json:"names,omitempty"`
+
+	// ResponseBytes is the size of the decoded response body.
+	ResponseBytes int64 `json:"-"`
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
 }
 
 // SearchJQLInput contains parameters for JQL search.
@@ -127,6 +576,39 @@ type SearchJQLParams struct {
 	JQL        string
 	StartAt    int
 	MaxResults int
+
+	// NextPageToken continues a cursor-mode search; StartAt is ignored in
+	// that mode.
+	NextPageToken string
+
+	// Fields selects the fields returned per issue and is passed to Jira
+	// verbatim. Entries are field IDs, "*all", "*navigable", or a field ID
+	// prefixed with "-" to exclude it, e.g. {"*all", "-comment"}. Empty
+	// uses Jira's default (navigable fields; plus comments in cursor-search
+	// mode).
+	Fields []string
+
+	// Expand requests extra per-issue data, such as "names" (field display
+	// names, see Issue.Names), "renderedFields" or "changelog".
+	Expand []string
+
+	// RequestProperties lists the issue entity property keys returned
+	// inline in Issue.Properties, saving a request per issue. Use "*all"
+	// for every property.
+	RequestProperties []string
+}
+
+// validateFields loosely checks a fields selection: each entry must be a
+// non-empty name, optionally prefixed with "*" or "-", without commas or
+// whitespace.
+func validateFields(fields []string) error {
+	for _, field := range fields {
+		name := strings.TrimPrefix(strings.TrimPrefix(field, "-"), "*")
+		if name == "" || strings.ContainsAny(name, ", \t\n") {
+			return fmt.Errorf("invalid field %q", field)
+		}
+	}
+	return nil
 }
 
 // SearchJQL searches for issues using JQL.
@@ -139,73 +621,453 @@ func (c *Client) SearchJQL(ctx context.Context, jql string, maxResults int) (*Se
 }
 
 // SearchJQLWithParams searches for issues using JQL with full pagination control.
+// A query without any clause before ORDER BY is refused with
+// ErrUnboundedJQL without contacting Jira.
 func (c *Client) SearchJQLWithParams(ctx context.Context, params SearchJQLParams) (*SearchResult, error) {
+	var issues []Issue
+	result, err := c.SearchJQLEach(ctx, params, func(issue Issue) error {
+		issues = append(issues, issue)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range issues {
+		if issues[i].Names == nil {
+			issues[i].Names = result.Names
+		}
+	}
+
+	result.Issues = issues
+	return result, nil
+}
+
+// SearchJQLEach is SearchJQLWithParams decoding the page's issues one at a
+// time and handing each to visit instead of collecting them, so a page of
+// issues with large descriptions is never held in memory at once. The
+// returned result carries the paging fields and Names only; its Issues is
+// nil, and visited issues do not get the page's Names since Jira sends them
+// after the issues. An error from visit stops decoding and is returned as
+// is.
+func (c *Client) SearchJQLEach(ctx context.Context, params SearchJQLParams, visit func(Issue) error) (*SearchResult, error) {
+	if jql.IsUnbounded(params.JQL) {
+		return nil, ErrUnboundedJQL
+	}
+	if err := validateFields(params.Fields); err != nil {
+		return nil, err
+	}
+	if err := validateFields(params.Expand); err != nil {
+		return nil, err
+	}
+	if err := validateFields(params.RequestProperties); err != nil {
+		return nil, err
+	}
+
 	maxResults := params.MaxResults
 	if maxResults <= 0 {
 		maxResults = 50
 	}
 
-	endpoint := fmt.Sprintf("%s/rest/api/3/search?jql=%s&startAt=%d&maxResults=%d",
-		c.baseURL, url.QueryEscape(params.JQL), params.StartAt, maxResults)
+	query := url.Values{}
+	query.Set("jql", params.JQL)
+	query.Set("maxResults", strconv.Itoa(maxResults))
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	path := "/rest/api/3/search"
+	if c.cursorSearch {
+		path = "/rest/api/3/search/jql"
+		query.Set("fields", "*navigable,comment")
+		if params.NextPageToken != "" {
+			query.Set("nextPageToken", params.NextPageToken)
+		}
+	} else {
+		query.Set("startAt", strconv.Itoa(params.StartAt))
+	}
+	if len(params.Fields) > 0 {
+		query.Set("fields", strings.Join(params.Fields, ","))
+	}
+	if len(params.Expand) > 0 {
+		query.Set("expand", strings.Join(params.Expand, ","))
+	}
+	if len(params.RequestProperties) > 0 {
+		query.Set("properties", strings.Join(params.RequestProperties, ","))
+	}
+
+	var result SearchResult
+	err := c.doDecode(ctx, http.MethodGet, path, query, nil, func(r io.Reader) error {
+		counter := &countingReader{r: r}
+		err := decodeSearchResult(counter, &result, visit)
+		result.ResponseBytes = counter.n
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+		if errors.Is(err, ErrUnboundedJQL) {
+			return nil, fmt.Errorf("%w (%v)", ErrUnboundedJQL, err)
+		}
+		if !c.cursorSearch && isDeepPagination(err, params.StartAt) {
+			return nil, fmt.Errorf("%w (startAt=%d: %v)", ErrDeepPagination, params.StartAt, err)
+		}
+		return nil, err
 	}
 
-	c.setAuth(req)
+	if c.cursorSearch && result.NextPageToken == "" {
+		result.IsLast = true
+	}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("execute request: %w", err)
+	return &result, nil
+}
+
+// deepPaginationOffset is the startAt from which Jira may refuse
+// offset-paginated searches.
+const deepPaginationOffset = 10000
+
+// isDeepPagination reports whether err is Jira refusing a search at startAt
+// because the offset is too deep. Jira's message for this varies, so a 400
+// past the known window counts too.
+func isDeepPagination(err error, startAt int) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest || startAt == 0 {
+		return false
 	}
-	defer resp.Body.Close()
+	return startAt >= deepPaginationOffset || apiErr.hasMessage("startat")
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("jira API error: status=%d body=%s", resp.StatusCode, string(body))
+// decodeSearchResult decodes a search response from r into result, walking
+// the issues array element by element and passing each issue to visit.
+func decodeSearchResult(r io.Reader, result *SearchResult, visit func(Issue) error) error {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
 	}
 
-	var result SearchResult
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("decode response: %w", err)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+
+		var target any
+		switch key {
+		case "issues":
+			if err := decodeIssues(dec, visit); err != nil {
+				return err
+			}
+			continue
+		case "startAt":
+			target = &result.StartAt
+		case "maxResults":
+			target = &result.MaxResults
+		case "total":
+			target = &result.Total
+		case "nextPageToken":
+			target = &result.NextPageToken
+		case "isLast":
+			target = &result.IsLast
+		case "names":
+			target = &result.Names
+		default:
+			target = new(json.RawMessage)
+		}
+		if err := dec.Decode(target); err != nil {
+			return fmt.Errorf("decode %s: %w", key, err)
+		}
 	}
 
-	return &result, nil
+	return expectDelim(dec, '}')
+}
+
+// decodeIssues decodes an issues array one element at a time.
+func decodeIssues(dec *json.Decoder, visit func(Issue) error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("decode issues: unexpected %v", tok)
+	}
+
+	for dec.More() {
+		var issue Issue
+		if err := dec.Decode(&issue); err != nil {
+			return fmt.Errorf("decode issue: %w", err)
+		}
+		if err := visit(issue); err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(dec, ']')
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != want {
+		return fmt.Errorf("expected %v, got %v", want, tok)
+	}
+	return nil
 }
 
-// GetIssue fetches a single issue by key.
+// GetIssue fetches a single issue by key. It only records the read in the
+// user's issue history when the client was configured with UpdateHistory.
 func (c *Client) GetIssue(ctx context.Context, issueKey string) (*Issue, error) {
-	endpoint := fmt.Sprintf("%s/rest/api/3/issue/%s", c.baseURL, issueKey)
+	return c.readIssue(ctx, issueKey, url.Values{})
+}
+
+// readIssue reads an issue with the given query parameters, such as a
+// fields selection. Every issue read goes through it so none records the
+// read in the user's issue history unless UpdateHistory is set.
+func (c *Client) readIssue(ctx context.Context, issueKey string, query url.Values) (*Issue, error) {
+	query.Set("updateHistory", strconv.FormatBool(c.updateHistory))
+
+	var issue Issue
+	if err := c.do(ctx, http.MethodGet, "/rest/api/3/issue/"+url.PathEscape(issueKey), query, nil, &issue); err != nil {
+		return nil, err
+	}
+
+	return &issue, nil
+}
+
+// SearchUsers finds users whose display name or email matches query.
+func (c *Client) SearchUsers(ctx context.Context, query string) ([]User, error) {
+	params := url.Values{}
+	params.Set("query", query)
+
+	var users []User
+	if err := c.do(ctx, http.MethodGet, "/rest/api/3/user/search", params, nil, &users); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// GetCurrentUser returns the user the client authenticates as.
+func (c *Client) GetCurrentUser(ctx context.Context) (*User, error) {
+	var user User
+	if err := c.do(ctx, http.MethodGet, "/rest/api/3/myself", nil, nil, &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// UserLocation returns the time zone of the authenticated user's profile,
+// in which Jira interprets the dates of JQL queries. It is fetched once
+// per client. When the profile has no time zone, or one unknown to the
+// local time zone database, it returns UTC and logs a warning.
+func (c *Client) UserLocation(ctx context.Context) (*time.Location, error) {
+	c.userLocationMu.Lock()
+	defer c.userLocationMu.Unlock()
+
+	if c.userLocation != nil {
+		return c.userLocation, nil
+	}
+
+	user, err := c.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := time.LoadLocation(user.TimeZone)
+	if err != nil || user.TimeZone == "" {
+		c.logger.WarnContext(ctx, "jira: unknown user time zone, using UTC for JQL dates",
+			"timeZone", user.TimeZone)
+		loc = time.UTC
+	}
+
+	c.userLocation = loc
+	return loc, nil
+}
+
+// FindAccountID resolves an email address to a Jira account ID.
+// Values that do not look like an email are returned unchanged, so callers
+// may pass either an email or an account ID.
+func (c *Client) FindAccountID(ctx context.Context, email string) (string, error) {
+	if !strings.Contains(email, "@") {
+		return email, nil
+	}
+
+	users, err := c.SearchUsers(ctx, email)
+	if err != nil {
+		return "", fmt.Errorf("search users: %w", err)
+	}
+
+	var matches []User
+	for _, u := range users {
+		if strings.EqualFold(u.EmailAddress, email) {
+			matches = append(matches, u)
+		}
+	}
+	if len(matches) == 0 && len(users) == 1 {
+		// Email visibility settings often hide emailAddress; a single
+		// result for an email query is the user we asked for.
+		matches = users
+	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no user found for %s", email)
+	case 1:
+		return matches[0].AccountID, nil
+	default:
+		return "", fmt.Errorf("multiple users found for %s", email)
+	}
+}
+
+// Close releases the client's idle connections and drops its caches, such
+// as the server info, JQL metadata, user timezone and the sprint, parent,
+// project, version and component lookups. The client must not be used
+// after Close; further requests fail with ErrClientClosed, cached lookups
+// included. Activities close the clients they create before returning, as
+// core.Provider has no shutdown hook.
+func (c *Client) Close() error {
+	c.closed.Store(true)
+	c.httpClient.CloseIdleConnections()
+
+	c.serverInfoMu.Lock()
+	c.serverInfo = nil
+	c.serverInfoMu.Unlock()
+	c.jqlMetadataMu.Lock()
+	c.jqlMetadata = nil
+	c.jqlMetadataMu.Unlock()
+	c.sprintCacheMu.Lock()
+	c.sprintCache = nil
+	c.sprintCacheMu.Unlock()
+	c.parentCacheMu.Lock()
+	c.parentCache = nil
+	c.parentCacheMu.Unlock()
+	c.linkTypesMu.Lock()
+	c.linkTypes = nil
+	c.linkTypesMu.Unlock()
+	c.userLocationMu.Lock()
+	c.userLocation = nil
+	c.userLocationMu.Unlock()
+	c.projectCacheMu.Lock()
+	c.projectCache = nil
+	c.projectCacheMu.Unlock()
+	c.versionCacheMu.Lock()
+	c.versionCache = nil
+	c.versionCacheMu.Unlock()
+	c.componentCacheMu.Lock()
+	c.componentCache = nil
+	c.componentCacheMu.Unlock()
+	return nil
+}
+
+// do executes a request against the Jira API and decodes a JSON response
+// into out when out is non-nil.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	var decode func(io.Reader) error
+	if out != nil {
+		decode = func(r io.Reader) error {
+			return json.NewDecoder(r).Decode(out)
+		}
+	}
+	return c.doDecode(ctx, method, path, query, body, decode)
+}
+
+// doDecode executes a request against the Jira API and hands a successful
+// response body to decode when decode is non-nil, so callers can stream
+// large responses instead of decoding them in one piece.
+func (c *Client) doDecode(ctx context.Context, method, path string, query url.Values, body any, decode func(io.Reader) error) error {
+	if c.closed.Load() {
+		return ErrClientClosed
+	}
+	if c.configErr != nil {
+		return c.configErr
+	}
+
+	endpoint := c.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("marshal request: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	reqCtx := ctx
+	if c.requestTimeout > 0 {
+		// WithTimeout keeps the parent's deadline when it is earlier.
+		var cancel context.CancelFunc
+		reqCtx, cancel = context.WithTimeout(ctx, c.requestTimeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(withMetrics(reqCtx, c.metrics), method, endpoint, reqBody)
 	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+		return fmt.Errorf("create request: %w", err)
 	}
 
 	c.setAuth(req)
+	if c.impersonation != nil {
+		token, err := c.accessToken(reqCtx)
+		if err != nil {
+			return fmt.Errorf("impersonate user: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
 
+	start := time.Now()
 	resp, err := c.httpClient.Do(req)
+	elapsed := time.Since(start)
+	c.metrics.ObserveLatency(method, elapsed)
+	if c.slowRequestThreshold > 0 && elapsed > c.slowRequestThreshold {
+		c.logger.WarnContext(ctx, "jira: slow request",
+			"method", method, "url", endpoint, "duration", elapsed)
+		if observer, ok := c.metrics.(SlowRequestObserver); ok {
+			observer.IncSlowRequest(method)
+		}
+	}
 	if err != nil {
-		return nil, fmt.Errorf("execute request: %w", err)
+		c.metrics.IncRequest(method, 0)
+		if ctx.Err() == nil && reqCtx.Err() != nil {
+			return fmt.Errorf("execute request: request timeout %s exceeded: %w", c.requestTimeout, err)
+		}
+		return fmt.Errorf("execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("jira API error: status=%d body=%s", resp.StatusCode, string(body))
+	c.metrics.IncRequest(method, resp.StatusCode)
+
+	c.recordRateLimit(resp.Header)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return newAPIError(resp.StatusCode, respBody)
 	}
 
-	var issue Issue
-	if err := json.NewDecoder(resp.Body).Decode(&issue); err != nil {
-		return nil, fmt.Errorf("decode response: %w", err)
+	if decode == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
 	}
 
-	return &issue, nil
+	if err := decode(resp.Body); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+
+	return nil
 }
 
 func (c *Client) setAuth(req *http.Request) {
 	req.SetBasicAuth(c.email, c.apiToken)
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Content-Type", "application/json")
+
+	for name, values := range c.extraHeaders {
+		if name == "Authorization" && !c.allowAuthOverride {
+			continue
+		}
+		req.Header[name] = values
+	}
 }
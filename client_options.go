@@ -0,0 +1,83 @@
+package jira
+
+import (
+	"time"
+)
+
+// ClientOptions are the client settings an activity input carries besides
+// its credentials. Inputs cross activity boundaries as data, so Metrics
+// and RetryPolicy name implementations registered in the worker process
+// with RegisterMetrics and RegisterRetryPolicy. The fields mean the same
+// as in ClientConfig.
+type ClientOptions struct {
+	RequestTimeout       time.Duration
+	SlowRequestThreshold time.Duration
+
+	ExtraHeaders      map[string]string
+	AllowAuthOverride bool
+
+	ImpersonateAccountID string
+	ConnectOAuthClientID string
+	ConnectSharedSecret  string
+	ImpersonationScopes  []string
+
+	CursorSearch bool
+
+	// Metrics names a Metrics registered with RegisterMetrics.
+	Metrics string
+
+	// RetryPolicy names a RetryFunc registered with RegisterRetryPolicy,
+	// used as ClientConfig.ShouldRetry.
+	RetryPolicy string
+
+	// RequestsPerSecond limits the requests of the activity; see
+	// ClientConfig.RequestsPerSecond. Each activity run has its own limit,
+	// so divide a shared budget among activities running at once.
+	RequestsPerSecond float64
+}
+
+// newClient creates a client for cfg, which holds the credentials and any
+// activity-specific settings, with the options that are set applied on
+// top; unset options leave cfg as is.
+func (o ClientOptions) newClient(cfg ClientConfig) (*Client, error) {
+	metrics, err := metricsRegistry.lookup(o.Metrics)
+	if err != nil {
+		return nil, err
+	}
+	retry, err := retryPolicies.lookup(o.RetryPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	if o.RequestTimeout != 0 {
+		cfg.RequestTimeout = o.RequestTimeout
+	}
+	if o.SlowRequestThreshold != 0 {
+		cfg.SlowRequestThreshold = o.SlowRequestThreshold
+	}
+	if o.ExtraHeaders != nil {
+		cfg.ExtraHeaders = o.ExtraHeaders
+	}
+	if o.AllowAuthOverride {
+		cfg.AllowAuthOverride = true
+	}
+	if o.ImpersonateAccountID != "" {
+		cfg.ImpersonateAccountID = o.ImpersonateAccountID
+		cfg.ConnectOAuthClientID = o.ConnectOAuthClientID
+		cfg.ConnectSharedSecret = o.ConnectSharedSecret
+		cfg.ImpersonationScopes = o.ImpersonationScopes
+	}
+	if o.CursorSearch {
+		cfg.CursorSearch = true
+	}
+	if metrics != nil {
+		cfg.Metrics = metrics
+	}
+	if retry != nil {
+		cfg.ShouldRetry = retry
+	}
+	if o.RequestsPerSecond != 0 {
+		cfg.RequestsPerSecond = o.RequestsPerSecond
+	}
+	return NewClient(cfg), nil
+}
@@ -0,0 +1,132 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientOptions(t *testing.T) {
+	metrics := &recordingMetrics{}
+	RegisterMetrics("test-options", metrics)
+	RegisterRetryPolicy("test-cloudflare", retryCloudflare)
+
+	var attempts atomic.Int32
+	var gateway atomic.Value
+	fake := &fakeJira{routes: map[string]http.HandlerFunc{
+		"/rest/api/3/search/approximate-count": func(w http.ResponseWriter, r *http.Request) {
+			gateway.Store(r.Header.Get("X-Gateway-Token"))
+			if attempts.Add(1) == 1 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(522)
+				return
+			}
+			writeJSON(w, map[string]any{"count": 7})
+		},
+	}}
+	baseURL := fake.start(t)
+
+	out, err := runActivity(t, CountJQLActivity, CountJQLInput{
+		BaseURL: baseURL,
+		JQL:     "project = PROJ",
+		ClientOptions: ClientOptions{
+			CursorSearch:   true,
+			RequestTimeout: 5 * time.Second,
+			ExtraHeaders:   map[string]string{"X-Gateway-Token": "gw"},
+			Metrics:        "test-options",
+			RetryPolicy:    "test-cloudflare",
+		},
+	})
+	if err != nil {
+		t.Fatalf("CountJQLActivity: %v", err)
+	}
+	if out.Count != 7 {
+		t.Errorf("count = %d, want 7", out.Count)
+	}
+	if got := gateway.Load(); got != "gw" {
+		t.Errorf("X-Gateway-Token = %v, want gw", got)
+	}
+	if strings.Join(metrics.retries, ",") != "522" {
+		t.Errorf("retries = %v, want [522]", metrics.retries)
+	}
+}
+
+func TestClientOptionsUnknownNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    ClientOptions
+		wantErr string
+	}{
+		{name: "metrics", opts: ClientOptions{Metrics: "missing"}, wantErr: `unknown metrics "missing"`},
+		{name: "retry policy", opts: ClientOptions{RetryPolicy: "missing"}, wantErr: `unknown retry policy "missing"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.opts.newClient(ClientConfig{BaseURL: "http://jira.invalid"})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClientOptionsNewClient(t *testing.T) {
+	client, err := ClientOptions{
+		RequestTimeout:       3 * time.Second,
+		SlowRequestThreshold: time.Second,
+		ExtraHeaders:         map[string]string{"X-Gateway-Token": "gw"},
+		ImpersonateAccountID: "user-1",
+		CursorSearch:         true,
+	}.newClient(ClientConfig{BaseURL: "http://jira.invalid", UpdateHistory: true})
+	if err != nil {
+		t.Fatalf("newClient: %v", err)
+	}
+	defer client.Close()
+
+	if client.requestTimeout != 3*time.Second || client.slowRequestThreshold != time.Second {
+		t.Errorf("timeouts = %s, %s, want 3s, 1s", client.requestTimeout, client.slowRequestThreshold)
+	}
+	if client.extraHeaders.Get("X-Gateway-Token") != "gw" {
+		t.Errorf("extra headers = %v, want the gateway token", client.extraHeaders)
+	}
+	if !client.cursorSearch {
+		t.Errorf("cursor search not enabled")
+	}
+	if client.impersonation == nil || client.impersonation.accountID != "user-1" {
+		t.Errorf("impersonation not configured")
+	}
+	if !client.updateHistory {
+		t.Errorf("activity-specific settings of the config were dropped")
+	}
+	if _, err := client.GetIssue(context.Background(), "PROJ-1"); err == nil {
+		t.Errorf("GetIssue without Connect credentials succeeded")
+	}
+}
+
+func TestClientOptionsNewClientUnset(t *testing.T) {
+	client, err := ClientOptions{}.newClient(ClientConfig{
+		BaseURL:              "http://jira.invalid",
+		RequestTimeout:       3 * time.Second,
+		SlowRequestThreshold: time.Second,
+		ExtraHeaders:         map[string]string{"X-Gateway-Token": "gw"},
+		CursorSearch:         true,
+	})
+	if err != nil {
+		t.Fatalf("newClient: %v", err)
+	}
+	defer client.Close()
+
+	if client.requestTimeout != 3*time.Second || client.slowRequestThreshold != time.Second {
+		t.Errorf("timeouts = %s, %s, want the config's 3s, 1s", client.requestTimeout, client.slowRequestThreshold)
+	}
+	if client.extraHeaders.Get("X-Gateway-Token") != "gw" {
+		t.Errorf("extra headers = %v, want the config's gateway token", client.extraHeaders)
+	}
+	if !client.cursorSearch {
+		t.Errorf("cursor search of the config was dropped")
+	}
+}
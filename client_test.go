@@ -0,0 +1,482 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientSlowBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		headerDelay time.Duration
+		chunkDelay  time.Duration
+		wantErr     bool
+	}{
+		{name: "body trickles past the header timeout", chunkDelay: 60 * time.Millisecond},
+		{name: "headers exceed the header timeout", headerDelay: 500 * time.Millisecond, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `{"id":"1","key":"PROJ-1","fields":{"summary":"` + strings.Repeat("x", 64) + `"}}`
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(tt.headerDelay)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				// Ten chunks take several times the header timeout.
+				for chunk := range 10 {
+					size := len(body) / 10
+					end := (chunk + 1) * size
+					if chunk == 9 {
+						end = len(body)
+					}
+					io.WriteString(w, body[chunk*size:end])
+					w.(http.Flusher).Flush()
+					time.Sleep(tt.chunkDelay)
+				}
+			}), ClientConfig{
+				ResponseHeaderTimeout: 150 * time.Millisecond,
+				Transport:             NewBaseTransport(ClientConfig{ResponseHeaderTimeout: 150 * time.Millisecond}),
+			})
+
+			issue, err := client.GetIssue(context.Background(), "PROJ-1")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("GetIssue succeeded, want a header timeout")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetIssue: %v", err)
+			}
+			if issue.Key != "PROJ-1" {
+				t.Errorf("key = %q, want PROJ-1", issue.Key)
+			}
+		})
+	}
+}
+
+func TestClientRequestDeadline(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestTimeout time.Duration
+		ctxTimeout     time.Duration
+		wantMessage    string
+	}{
+		{
+			name:           "context deadline before request timeout",
+			requestTimeout: 10 * time.Second,
+			ctxTimeout:     100 * time.Millisecond,
+			wantMessage:    "context deadline exceeded",
+		},
+		{
+			name:           "request timeout before context deadline",
+			requestTimeout: 100 * time.Millisecond,
+			ctxTimeout:     10 * time.Second,
+			wantMessage:    "request timeout 100ms exceeded",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-r.Context().Done():
+				case <-time.After(5 * time.Second):
+				}
+			}), ClientConfig{RequestTimeout: tt.requestTimeout, Transport: NewBaseTransport(ClientConfig{})})
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.ctxTimeout)
+			defer cancel()
+
+			start := time.Now()
+			_, err := client.GetIssue(ctx, "PROJ-1")
+			elapsed := time.Since(start)
+
+			if err == nil {
+				t.Fatal("GetIssue succeeded, want a timeout")
+			}
+			if !strings.Contains(err.Error(), tt.wantMessage) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.wantMessage)
+			}
+			if elapsed > time.Second {
+				t.Errorf("request took %s, want the shorter budget", elapsed)
+			}
+		})
+	}
+}
+
+func TestClientExtraHeaders(t *testing.T) {
+	tests := []struct {
+		name              string
+		headers           map[string]string
+		allowAuthOverride bool
+		wantAuth          string
+		wantGateway       string
+	}{
+		{
+			name:        "gateway header added",
+			headers:     map[string]string{"X-Gateway-Token": "gw"},
+			wantAuth:    "Basic ",
+			wantGateway: "gw",
+		},
+		{
+			name:     "authorization ignored without override",
+			headers:  map[string]string{"Authorization": "Bearer other"},
+			wantAuth: "Basic ",
+		},
+		{
+			name:              "authorization replaced with override",
+			headers:           map[string]string{"Authorization": "Bearer other"},
+			allowAuthOverride: true,
+			wantAuth:          "Bearer other",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got http.Header
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Clone()
+				writeJSON(w, testIssue("PROJ-1", nil))
+			}), ClientConfig{ExtraHeaders: tt.headers, AllowAuthOverride: tt.allowAuthOverride})
+
+			if _, err := client.GetIssue(context.Background(), "PROJ-1"); err != nil {
+				t.Fatalf("GetIssue: %v", err)
+			}
+			if auth := got.Get("Authorization"); !strings.HasPrefix(auth, tt.wantAuth) {
+				t.Errorf("Authorization = %q, want prefix %q", auth, tt.wantAuth)
+			}
+			if gateway := got.Get("X-Gateway-Token"); gateway != tt.wantGateway {
+				t.Errorf("X-Gateway-Token = %q, want %q", gateway, tt.wantGateway)
+			}
+		})
+	}
+}
+
+func TestClientImpersonation(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      ClientConfig
+		wantAuth string
+		wantErr  string
+	}{
+		{
+			name: "bearer token of the impersonated user",
+			cfg: ClientConfig{
+				ImpersonateAccountID: "user-1",
+				ConnectOAuthClientID: "client",
+				ConnectSharedSecret:  "secret",
+			},
+			wantAuth: "Bearer user-token",
+		},
+		{
+			name: "authorization override conflicts",
+			cfg: ClientConfig{
+				ImpersonateAccountID: "user-1",
+				ConnectOAuthClientID: "client",
+				ConnectSharedSecret:  "secret",
+				ExtraHeaders:         map[string]string{"Authorization": "Bearer other"},
+				AllowAuthOverride:    true,
+			},
+			wantErr: "cannot be combined with ImpersonateAccountID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tokenRequests, apiRequests atomic.Int32
+			var gotAuth atomic.Value
+			base := NewBaseTransport(ClientConfig{})
+			tt.cfg.Transport = RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				if strings.HasPrefix(connectAuthServer, req.URL.Scheme+"://"+req.URL.Host) {
+					tokenRequests.Add(1)
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Header:     http.Header{"Content-Type": {"application/json"}},
+						Body:       io.NopCloser(strings.NewReader(`{"access_token":"user-token","expires_in":900}`)),
+						Request:    req,
+					}, nil
+				}
+				return base.RoundTrip(req)
+			})
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				apiRequests.Add(1)
+				gotAuth.Store(r.Header.Get("Authorization"))
+				writeJSON(w, testIssue("PROJ-1", nil))
+			}), tt.cfg)
+
+			for range 2 {
+				_, err := client.GetIssue(context.Background(), "PROJ-1")
+				if tt.wantErr != "" {
+					if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+						t.Fatalf("GetIssue error = %v, want %q", err, tt.wantErr)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("GetIssue: %v", err)
+				}
+			}
+
+			if tt.wantErr != "" {
+				if n := apiRequests.Load(); n != 0 {
+					t.Errorf("sent %d requests with an invalid config", n)
+				}
+				return
+			}
+			if auth := gotAuth.Load(); auth != tt.wantAuth {
+				t.Errorf("Authorization = %v, want %q", auth, tt.wantAuth)
+			}
+			if n := tokenRequests.Load(); n != 1 {
+				t.Errorf("token requests = %d, want 1 (cached)", n)
+			}
+		})
+	}
+}
+
+func TestGetIssueUpdateHistory(t *testing.T) {
+	tests := []struct {
+		updateHistory bool
+		want          string
+	}{
+		{updateHistory: false, want: "false"},
+		{updateHistory: true, want: "true"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			var got url.Values
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.URL.Query()
+				writeJSON(w, testIssue("PROJ-1", nil))
+			}), ClientConfig{UpdateHistory: tt.updateHistory})
+
+			if _, err := client.GetIssue(context.Background(), "PROJ-1"); err != nil {
+				t.Fatalf("GetIssue: %v", err)
+			}
+			if got.Get("updateHistory") != tt.want {
+				t.Errorf("updateHistory = %q, want %q", got.Get("updateHistory"), tt.want)
+			}
+		})
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		want     error
+		wantBody string
+	}{
+		{
+			name:     "expired token login page",
+			status:   http.StatusUnauthorized,
+			body:     "<html>\n  <body>Log in</body>\n</html>",
+			want:     ErrUnauthorized,
+			wantBody: "<html> <body>Log in</body> </html>",
+		},
+		{
+			name:     "forbidden",
+			status:   http.StatusForbidden,
+			body:     `{"errorMessages":["no permission"]}`,
+			want:     ErrForbidden,
+			wantBody: `{"errorMessages":["no permission"]}`,
+		},
+		{
+			name:     "not found",
+			status:   http.StatusNotFound,
+			body:     `{"errorMessages":["Issue does not exist"]}`,
+			want:     ErrNotFound,
+			wantBody: `{"errorMessages":["Issue does not exist"]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				io.WriteString(w, tt.body)
+			}), ClientConfig{})
+
+			_, err := client.GetIssue(context.Background(), "PROJ-1")
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("error = %v, want %v", err, tt.want)
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("error = %T, want *APIError", err)
+			}
+			if apiErr.Body != tt.wantBody {
+				t.Errorf("body = %q, want %q", apiErr.Body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestClientClose(t *testing.T) {
+	var requests atomic.Int32
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/rest/api/2/serverInfo":
+			writeJSON(w, map[string]any{"deploymentType": "Cloud"})
+		case "/rest/api/3/myself":
+			writeJSON(w, map[string]any{"accountId": "self", "timeZone": "Europe/Paris"})
+		default:
+			writeJSON(w, testIssue("PROJ-1", nil))
+		}
+	}), ClientConfig{})
+
+	// Fill the caches, which must not answer after Close either.
+	ctx := context.Background()
+	if _, err := client.GetServerInfo(ctx); err != nil {
+		t.Fatalf("GetServerInfo: %v", err)
+	}
+	if _, err := client.UserLocation(ctx); err != nil {
+		t.Fatalf("UserLocation: %v", err)
+	}
+	before := requests.Load()
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := client.GetIssue(ctx, "PROJ-1"); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("GetIssue error = %v, want ErrClientClosed", err)
+	}
+	if _, err := client.GetServerInfo(ctx); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("GetServerInfo error = %v, want ErrClientClosed", err)
+	}
+	if _, err := client.UserLocation(ctx); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("UserLocation error = %v, want ErrClientClosed", err)
+	}
+	if n := requests.Load() - before; n != 0 {
+		t.Errorf("sent %d requests after Close", n)
+	}
+}
+
+func TestActivityClosesClient(t *testing.T) {
+	// Closing the client closes its idle connections, which the server
+	// sees once the activity has returned.
+	closed := make(chan struct{}, 1)
+	srv := httptest.NewUnstartedServer(&fakeJira{issues: testIssues(1, false)})
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateClosed {
+			select {
+			case closed <- struct{}{}:
+			default:
+			}
+		}
+	}
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	if _, err := runActivity(t, CountJQLActivity, CountJQLInput{BaseURL: srv.URL, JQL: "project = PROJ"}); err != nil {
+		t.Fatalf("CountJQLActivity: %v", err)
+	}
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Error("connection still open after the activity returned")
+	}
+}
+
+func TestLastRateLimit(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("headers") == "true" {
+			w.Header().Set("X-RateLimit-Limit", "100")
+			w.Header().Set("X-RateLimit-Remaining", "12")
+			w.Header().Set("X-RateLimit-Reset", "2026-01-02T03:04:05Z")
+			w.Header().Set("X-RateLimit-NearLimit", "true")
+		}
+		writeJSON(w, map[string]any{})
+	}), ClientConfig{})
+
+	ctx := context.Background()
+	if err := client.do(ctx, http.MethodGet, "/limited", url.Values{"headers": {"true"}}, nil, nil); err != nil {
+		t.Fatalf("do: %v", err)
+	}
+	// A response without the headers keeps the last reported state.
+	if err := client.do(ctx, http.MethodGet, "/plain", nil, nil, nil); err != nil {
+		t.Fatalf("do: %v", err)
+	}
+
+	info := client.LastRateLimit()
+	wantReset := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if info.Limit != 100 || info.Remaining != 12 || !info.NearLimit || !info.Reset.Equal(wantReset) {
+		t.Errorf("rate limit = %+v, want limit 100, remaining 12, near limit, reset %s", info, wantReset)
+	}
+}
+
+func TestUserLocation(t *testing.T) {
+	tests := []struct {
+		timeZone string
+		want     string
+	}{
+		{timeZone: "Asia/Kolkata", want: "Asia/Kolkata"},
+		{timeZone: "", want: "UTC"},
+		{timeZone: "Not/AZone", want: "UTC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want+"/"+tt.timeZone, func(t *testing.T) {
+			var requests atomic.Int32
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				writeJSON(w, map[string]any{"accountId": "self", "timeZone": tt.timeZone})
+			}), ClientConfig{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+
+			for range 2 {
+				loc, err := client.UserLocation(context.Background())
+				if err != nil {
+					t.Fatalf("UserLocation: %v", err)
+				}
+				if loc.String() != tt.want {
+					t.Errorf("location = %s, want %s", loc, tt.want)
+				}
+			}
+			if n := requests.Load(); n != 1 {
+				t.Errorf("requests = %d, want 1 (cached)", n)
+			}
+		})
+	}
+}
+
+func TestSlowRequestThreshold(t *testing.T) {
+	var logs bytes.Buffer
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(50 * time.Millisecond)
+		}
+		writeJSON(w, map[string]any{})
+	}), ClientConfig{
+		SlowRequestThreshold: 20 * time.Millisecond,
+		Logger:               slog.New(slog.NewTextHandler(&logs, nil)),
+	})
+
+	ctx := context.Background()
+	for _, path := range []string{"/fast", "/slow"} {
+		if err := client.do(ctx, http.MethodGet, path, nil, nil, nil); err != nil {
+			t.Fatalf("do %s: %v", path, err)
+		}
+	}
+
+	if got := strings.Count(logs.String(), "jira: slow request"); got != 1 {
+		t.Fatalf("slow request warnings = %d, want 1:\n%s", got, logs.String())
+	}
+	if !strings.Contains(logs.String(), "/slow") {
+		t.Errorf("warning does not name the slow URL:\n%s", logs.String())
+	}
+}
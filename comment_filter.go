@@ -0,0 +1,51 @@
+package jira
+
+import "strings"
+
+// CommentAuthorFilter reports whether comments by an author may be
+// included in documents.
+type CommentAuthorFilter func(User) bool
+
+var commentFilters = newRegistry[CommentAuthorFilter]("comment author filter")
+
+// RegisterCommentAuthorFilter makes a filter available under name for the
+// DocumentOptions.CommentAuthorFilterName option:
+//
+//	jira.RegisterCommentAuthorFilter("staff", jira.SameDomain("acme.com"))
+func RegisterCommentAuthorFilter(name string, filter CommentAuthorFilter) {
+	commentFilters.register(name, filter)
+}
+
+// commentAuthorFilter returns CommentAuthorFilter, or else the filter
+// registered under CommentAuthorFilterName.
+func (opts DocumentOptions) commentAuthorFilter() (CommentAuthorFilter, error) {
+	if opts.CommentAuthorFilter != nil {
+		return opts.CommentAuthorFilter, nil
+	}
+	return commentFilters.lookup(opts.CommentAuthorFilterName)
+}
+
+// SameDomain returns a filter accepting authors whose email address is in
+// domain, compared case-insensitively. Authors whose email is hidden by
+// their profile visibility settings are rejected.
+func SameDomain(domain string) CommentAuthorFilter {
+	suffix := "@" + strings.ToLower(strings.TrimPrefix(domain, "@"))
+	return func(u User) bool {
+		return strings.HasSuffix(strings.ToLower(u.EmailAddress), suffix)
+	}
+}
+
+// filterComments returns the comments whose author passes filter, all of
+// them for a nil filter.
+func filterComments(comments []Comment, filter CommentAuthorFilter) []Comment {
+	if filter == nil {
+		return comments
+	}
+	kept := make([]Comment, 0, len(comments))
+	for _, comment := range comments {
+		if filter(comment.Author) {
+			kept = append(kept, comment)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,45 @@
+package jira
+
+import "testing"
+
+func TestSameDomain(t *testing.T) {
+	tests := []struct {
+		domain string
+		email  string
+		want   bool
+	}{
+		{domain: "acme.com", email: "dev@acme.com", want: true},
+		{domain: "@ACME.com", email: "Dev@Acme.COM", want: true},
+		{domain: "acme.com", email: "dev@notacme.com"},
+		{domain: "acme.com", email: "dev@acme.com.evil.io"},
+		{domain: "acme.com", email: ""},
+	}
+
+	for _, tt := range tests {
+		if got := SameDomain(tt.domain)(User{EmailAddress: tt.email}); got != tt.want {
+			t.Errorf("SameDomain(%q)(%q) = %v, want %v", tt.domain, tt.email, got, tt.want)
+		}
+	}
+}
+
+func TestFilterComments(t *testing.T) {
+	comments := []Comment{
+		{ID: "1", Author: User{EmailAddress: "dev@acme.com"}},
+		{ID: "2", Author: User{EmailAddress: "customer@example.com"}},
+		{ID: "3", Author: User{}},
+	}
+
+	if got := filterComments(comments, nil); len(got) != 3 {
+		t.Errorf("nil filter kept %d comments, want 3", len(got))
+	}
+	if got := filterComments(comments, SameDomain("acme.com")); len(got) != 1 || got[0].ID != "1" {
+		t.Errorf("kept %+v, want comment 1", got)
+	}
+
+	if _, err := commentFilters.lookup("no-such-filter"); err == nil {
+		t.Errorf("lookup of an unknown filter succeeded")
+	}
+	if filter, err := commentFilters.lookup(""); filter != nil || err != nil {
+		t.Errorf("lookup of no filter = %v, %v, want nil, nil", filter, err)
+	}
+}
@@ -0,0 +1,186 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/resolute-sh/resolute-jira/adf"
+	"github.com/resolute-sh/resolute/core"
+)
+
+// commentPage is a page of an issue's comment listing.
+type commentPage struct {
+	StartAt    int       `json:"startAt"`
+	MaxResults int       `json:"maxResults"`
+	Total      int       `json:"total"`
+	Comments   []Comment `json:"comments"`
+}
+
+// GetComments returns all comments of an issue, oldest first.
+func (c *Client) GetComments(ctx context.Context, issueKey string) ([]Comment, error) {
+	path := "/rest/api/3/issue/" + url.PathEscape(issueKey) + "/comment"
+
+	var comments []Comment
+	startAt := 0
+	for {
+		query := url.Values{}
+		query.Set("startAt", strconv.Itoa(startAt))
+		query.Set("maxResults", "100")
+		query.Set("orderBy", "created")
+
+		var page commentPage
+		if err := c.do(ctx, http.MethodGet, path, query, nil, &page); err != nil {
+			return nil, err
+		}
+
+		comments = append(comments, page.Comments...)
+		startAt += len(page.Comments)
+		if len(page.Comments) == 0 || startAt >= page.Total {
+			return comments, nil
+		}
+	}
+}
+
+// Comment visibility types.
+const (
+	VisibilityRole  = "role"
+	VisibilityGroup = "group"
+)
+
+// CommentVisibility restricts a comment to members of a project role or a
+// group, e.g. {Type: VisibilityRole, Value: "Administrators"}.
+type CommentVisibility struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// AddComment posts a plain-text comment to an issue and returns it. When
+// visibility is non-nil, only members of that role or group can see it.
+func (c *Client) AddComment(ctx context.Context, issueKey, body string, visibility *CommentVisibility) (*Comment, error) {
+	if visibility != nil && visibility.Type != VisibilityRole && visibility.Type != VisibilityGroup {
+		return nil, fmt.Errorf("invalid visibility type %q", visibility.Type)
+	}
+
+	payload := struct {
+		Body       adf.Node           `json:"body"`
+		Visibility *CommentVisibility `json:"visibility,omitempty"`
+	}{
+		Body:       adf.FromText(body),
+		Visibility: visibility,
+	}
+
+	var comment Comment
+	path := "/rest/api/3/issue/" + url.PathEscape(issueKey) + "/comment"
+	if err := c.do(ctx, http.MethodPost, path, nil, payload, &comment); err != nil {
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+// UpdateComment replaces the body of a comment with plain text and returns
+// the updated comment. A deleted comment fails with an error matching
+// ErrNotFound, so callers can fall back to AddComment.
+func (c *Client) UpdateComment(ctx context.Context, issueKey, commentID, body string) (*Comment, error) {
+	payload := struct {
+		Body adf.Node `json:"body"`
+	}{
+		Body: adf.FromText(body),
+	}
+
+	var comment Comment
+	path := "/rest/api/3/issue/" + url.PathEscape(issueKey) + "/comment/" + url.PathEscape(commentID)
+	if err := c.do(ctx, http.MethodPut, path, nil, payload, &comment); err != nil {
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+// AddCommentInput is the input for AddCommentActivity.
+type AddCommentInput struct {
+	BaseURL    string
+	Email      string
+	APIToken   string
+	IssueKey   string
+	Body       string
+	Visibility *CommentVisibility // optional
+
+	ClientOptions
+}
+
+// AddCommentOutput is the output of AddCommentActivity.
+type AddCommentOutput struct {
+	CommentID string
+}
+
+// AddCommentActivity posts a comment to an issue.
+func AddCommentActivity(ctx context.Context, input AddCommentInput) (AddCommentOutput, error) {
+	client, err := input.ClientOptions.newClient(ClientConfig{
+		BaseURL:  input.BaseURL,
+		Email:    input.Email,
+		APIToken: input.APIToken,
+	})
+	if err != nil {
+		return AddCommentOutput{}, err
+	}
+	defer client.Close()
+
+	comment, err := client.AddComment(ctx, input.IssueKey, input.Body, input.Visibility)
+	if err != nil {
+		return AddCommentOutput{}, fmt.Errorf("add comment: %w", err)
+	}
+
+	return AddCommentOutput{CommentID: comment.ID}, nil
+}
+
+// AddComment creates a node for posting a comment to an issue.
+func AddComment(input AddCommentInput) *core.Node[AddCommentInput, AddCommentOutput] {
+	return core.NewNode("jira.AddComment", AddCommentActivity, input)
+}
+
+// UpdateCommentInput is the input for UpdateCommentActivity.
+type UpdateCommentInput struct {
+	BaseURL   string
+	Email     string
+	APIToken  string
+	IssueKey  string
+	CommentID string
+	Body      string
+
+	ClientOptions
+}
+
+// UpdateCommentOutput is the output of UpdateCommentActivity.
+type UpdateCommentOutput struct {
+	CommentID string
+}
+
+// UpdateCommentActivity replaces the body of an existing comment. The
+// error matches ErrNotFound when the comment was deleted.
+func UpdateCommentActivity(ctx context.Context, input UpdateCommentInput) (UpdateCommentOutput, error) {
+	client, err := input.ClientOptions.newClient(ClientConfig{
+		BaseURL:  input.BaseURL,
+		Email:    input.Email,
+		APIToken: input.APIToken,
+	})
+	if err != nil {
+		return UpdateCommentOutput{}, err
+	}
+	defer client.Close()
+
+	comment, err := client.UpdateComment(ctx, input.IssueKey, input.CommentID, input.Body)
+	if err != nil {
+		return UpdateCommentOutput{}, fmt.Errorf("update comment: %w", err)
+	}
+
+	return UpdateCommentOutput{CommentID: comment.ID}, nil
+}
+
+// UpdateComment creates a node for editing a comment.
+func UpdateComment(input UpdateCommentInput) *core.Node[UpdateCommentInput, UpdateCommentOutput] {
+	return core.NewNode("jira.UpdateComment", UpdateCommentActivity, input)
+}
@@ -0,0 +1,110 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestGetComments(t *testing.T) {
+	var pages []string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		pages = append(pages, query.Get("startAt")+"/"+query.Get("orderBy"))
+
+		startAt, _ := strconv.Atoi(query.Get("startAt"))
+		var comments []any
+		for i := startAt; i < min(startAt+2, 3); i++ {
+			comments = append(comments, map[string]any{"id": strconv.Itoa(i + 1), "body": "comment"})
+		}
+		writeJSON(w, map[string]any{"startAt": startAt, "maxResults": 2, "total": 3, "comments": comments})
+	}), ClientConfig{})
+
+	comments, err := client.GetComments(context.Background(), "PROJ-1")
+	if err != nil {
+		t.Fatalf("GetComments: %v", err)
+	}
+	if len(comments) != 3 || comments[2].ID != "3" {
+		t.Errorf("comments = %+v", comments)
+	}
+	if len(pages) != 2 || pages[0] != "0/created" || pages[1] != "2/created" {
+		t.Errorf("pages = %v, want [0/created 2/created]", pages)
+	}
+}
+
+func TestAddComment(t *testing.T) {
+	tests := []struct {
+		name       string
+		visibility *CommentVisibility
+		wantErr    string
+	}{
+		{name: "public"},
+		{name: "role", visibility: &CommentVisibility{Type: VisibilityRole, Value: "Administrators"}},
+		{name: "group", visibility: &CommentVisibility{Type: VisibilityGroup, Value: "jira-staff"}},
+		{name: "invalid", visibility: &CommentVisibility{Type: "user", Value: "dana"}, wantErr: `invalid visibility type "user"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body struct {
+				Body       map[string]any     `json:"body"`
+				Visibility *CommentVisibility `json:"visibility"`
+			}
+			var sent bool
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				sent = true
+				if err := decodeBody(r, &body); err != nil {
+					t.Errorf("decode body: %v", err)
+				}
+				writeJSON(w, map[string]any{"id": "10001", "body": body.Body})
+			}), ClientConfig{})
+
+			comment, err := client.AddComment(context.Background(), "PROJ-1", "Deployed.", tt.visibility)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr || sent {
+					t.Fatalf("error = %v (sent %v), want %q and no request", err, sent, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AddComment: %v", err)
+			}
+
+			if comment.ID != "10001" || comment.Body != "Deployed." {
+				t.Errorf("comment = %+v", comment)
+			}
+			if body.Body["type"] != "doc" {
+				t.Errorf("body = %v, want an ADF document", body.Body)
+			}
+			if (body.Visibility == nil) != (tt.visibility == nil) || (body.Visibility != nil && *body.Visibility != *tt.visibility) {
+				t.Errorf("visibility = %+v, want %+v", body.Visibility, tt.visibility)
+			}
+		})
+	}
+}
+
+func TestUpdateCommentActivity(t *testing.T) {
+	fake := &fakeJira{routes: map[string]http.HandlerFunc{
+		"/rest/api/3/issue/PROJ-1/comment/10001": func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPut {
+				t.Errorf("method = %s, want PUT", r.Method)
+			}
+			writeJSON(w, map[string]any{"id": "10001", "body": "edited"})
+		},
+	}}
+	baseURL := fake.start(t)
+
+	out, err := runActivity(t, UpdateCommentActivity, UpdateCommentInput{BaseURL: baseURL, IssueKey: "PROJ-1", CommentID: "10001", Body: "edited"})
+	if err != nil || out.CommentID != "10001" {
+		t.Fatalf("UpdateCommentActivity = %+v, %v", out, err)
+	}
+
+	// A deleted comment is reported as not found, so callers can re-add it.
+	client := NewClient(ClientConfig{BaseURL: baseURL})
+	defer client.Close()
+	if _, err := client.UpdateComment(context.Background(), "PROJ-1", "10002", "edited"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,138 @@
+package jira
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// connectAuthServer is the Atlassian authorization server issuing user
+// impersonation tokens to Connect apps.
+const connectAuthServer = "https://oauth-2-authorization-server.services.atlassian.com"
+
+// impersonation obtains and caches the access token letting a Connect app
+// act as a user.
+type impersonation struct {
+	oauthClientID string
+	sharedSecret  string
+	accountID     string
+	scopes        string
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// newImpersonation returns the impersonation configured by cfg, or nil
+// when ImpersonateAccountID is unset.
+func newImpersonation(cfg ClientConfig) *impersonation {
+	if cfg.ImpersonateAccountID == "" {
+		return nil
+	}
+
+	scopes := cfg.ImpersonationScopes
+	if len(scopes) == 0 {
+		scopes = []string{"READ", "WRITE"}
+	}
+	scopes = append([]string{"ACT_AS_USER"}, scopes...)
+
+	return &impersonation{
+		oauthClientID: cfg.ConnectOAuthClientID,
+		sharedSecret:  cfg.ConnectSharedSecret,
+		accountID:     cfg.ImpersonateAccountID,
+		scopes:        strings.ToUpper(strings.Join(scopes, " ")),
+	}
+}
+
+// accessToken returns a valid access token for the impersonated user,
+// exchanging a fresh JWT assertion when the cached one is about to expire.
+func (c *Client) accessToken(ctx context.Context) (string, error) {
+	imp := c.impersonation
+	imp.mu.Lock()
+	defer imp.mu.Unlock()
+
+	if imp.token != "" && time.Until(imp.expires) > time.Minute {
+		return imp.token, nil
+	}
+
+	if imp.oauthClientID == "" || imp.sharedSecret == "" {
+		return "", fmt.Errorf("impersonation requires the Connect OAuth client ID and shared secret")
+	}
+
+	assertion, err := imp.assertion(c.baseURL, time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+	form.Set("assertion", assertion)
+	form.Set("scope", imp.scopes)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, connectAuthServer+"/oauth2/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("request access token: %w", newAPIError(resp.StatusCode, respBody))
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("decode access token: %w", err)
+	}
+
+	imp.token = result.AccessToken
+	imp.expires = time.Now().Add(time.Duration(result.ExpiresIn) * time.Second)
+	return imp.token, nil
+}
+
+// assertion builds the HS256-signed JWT asserting that the app acts as the
+// user on the instance at baseURL.
+func (imp *impersonation) assertion(baseURL string, now time.Time) (string, error) {
+	header := map[string]string{"alg": "HS256", "typ": "JWT"}
+	claims := map[string]any{
+		"iss": "urn:atlassian:connect:clientid:" + imp.oauthClientID,
+		"sub": "urn:atlassian:connect:useraccountid:" + imp.accountID,
+		"tnt": strings.TrimRight(baseURL, "/"),
+		"aud": connectAuthServer,
+		"iat": now.Unix(),
+		"exp": now.Add(time.Minute).Unix(),
+	}
+
+	var parts []string
+	for _, part := range []any{header, claims} {
+		data, err := json.Marshal(part)
+		if err != nil {
+			return "", fmt.Errorf("encode assertion: %w", err)
+		}
+		parts = append(parts, base64.RawURLEncoding.EncodeToString(data))
+	}
+
+	signingInput := strings.Join(parts, ".")
+	mac := hmac.New(sha256.New, []byte(imp.sharedSecret))
+	mac.Write([]byte(signingInput))
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
@@ -0,0 +1,231 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/resolute-sh/resolute-jira/adf"
+	"github.com/resolute-sh/resolute-jira/jql"
+	"github.com/resolute-sh/resolute/core"
+)
+
+// CreateIssueRequest describes an issue to create.
+type CreateIssueRequest struct {
+	Project     string // project key
+	IssueType   string // issue type name, e.g. "Task"
+	Summary     string
+	Description string // plain text, converted to ADF
+	Labels      []string
+
+	// Fields holds additional fields in the shapes of the Jira create API,
+	// e.g. {"priority": {"name": "High"}}.
+	Fields map[string]any
+
+	// IdempotencyKey makes creation replay-safe: an issue of the project
+	// already carrying the key is returned instead of creating another.
+	// Derive it from something stable across retries, such as the
+	// workflow ID plus a step name.
+	IdempotencyKey string
+
+	// IdempotencyField is the ID of a text custom field, e.g.
+	// "customfield_10050", that stores IdempotencyKey. When empty the key
+	// is stored as an "idempotency-<key>" label instead, with whitespace
+	// replaced by underscores. The label is visible to users, but unlike an
+	// issue entity property, which JQL can only search once an app indexes
+	// it, it works on every instance without setup. Labels are limited to
+	// 255 characters, so keys stored as labels may be at most 243.
+	IdempotencyField string
+}
+
+// CreatedIssue identifies a created issue.
+type CreatedIssue struct {
+	ID   string `json:"id"`
+	Key  string `json:"key"`
+	Self string `json:"self"`
+
+	// Existing is set when IdempotencyKey matched an issue created by an
+	// earlier attempt and nothing new was created.
+	Existing bool `json:"-"`
+}
+
+// CreateIssue creates an issue.
+//
+// With an IdempotencyKey it first searches the project for an issue
+// carrying the key and returns that one if found. The check is not atomic:
+// two attempts running at the same time can both miss each other, and
+// Jira's search index can lag a just-created issue by a few seconds. Keep
+// retries sequential (the default for a Temporal activity) and give them
+// a few seconds of backoff to keep the window small.
+func (c *Client) CreateIssue(ctx context.Context, req CreateIssueRequest) (*CreatedIssue, error) {
+	fields := make(map[string]any, len(req.Fields)+5)
+	for key, value := range req.Fields {
+		fields[key] = value
+	}
+	fields["project"] = map[string]string{"key": req.Project}
+	fields["issuetype"] = map[string]string{"name": req.IssueType}
+	fields["summary"] = req.Summary
+	if req.Description != "" {
+		fields["description"] = adf.FromText(req.Description)
+	}
+	labels := append([]string(nil), req.Labels...)
+
+	if req.IdempotencyKey != "" && req.IdempotencyField == "" &&
+		utf8.RuneCountInString(idempotencyLabel(req.IdempotencyKey)) > maxLabelLength {
+		return nil, fmt.Errorf("idempotency key too long for a label: %d characters, at most %d",
+			utf8.RuneCountInString(req.IdempotencyKey), maxLabelLength-len(idempotencyLabelPrefix))
+	}
+
+	if req.IdempotencyKey != "" {
+		existing, err := c.findByIdempotencyKey(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("check idempotency key: %w", err)
+		}
+		if existing != nil {
+			return existing, nil
+		}
+
+		if req.IdempotencyField != "" {
+			fields[req.IdempotencyField] = req.IdempotencyKey
+		} else {
+			labels = append(labels, idempotencyLabel(req.IdempotencyKey))
+		}
+	}
+	if len(labels) > 0 {
+		fields["labels"] = labels
+	}
+
+	var created CreatedIssue
+	body := map[string]any{"fields": fields}
+	if err := c.do(ctx, http.MethodPost, "/rest/api/3/issue", nil, body, &created); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// createdIssueReadAttempts bounds the reads of GetCreatedIssue.
+const createdIssueReadAttempts = 4
+
+// GetCreatedIssue is GetIssue for an issue created moments ago. Jira Cloud
+// can answer 404 for a few seconds after creating an issue, so not-found
+// reads are retried up to 4 times with backoff, about 4 seconds in total,
+// before the error is returned.
+func (c *Client) GetCreatedIssue(ctx context.Context, issueKey string) (*Issue, error) {
+	for attempt := 1; ; attempt++ {
+		issue, err := c.GetIssue(ctx, issueKey)
+		if err == nil || !errors.Is(err, ErrNotFound) || attempt == createdIssueReadAttempts {
+			return issue, err
+		}
+		if err := sleep(ctx, backoff(attempt, nil)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// findByIdempotencyKey returns the issue of req's project carrying its
+// idempotency key, or nil when there is none.
+func (c *Client) findByIdempotencyKey(ctx context.Context, req CreateIssueRequest) (*CreatedIssue, error) {
+	var query jql.Query
+	query.And(jql.Equals("project", req.Project))
+	fields := []string{"key"}
+	if req.IdempotencyField != "" {
+		// Text fields only support the fuzzy ~ operator, so matches are
+		// confirmed against the exact value below.
+		query.And(jqlFieldRef(req.IdempotencyField) + " ~ " + jql.Quote(req.IdempotencyKey))
+		fields = append(fields, req.IdempotencyField)
+	} else {
+		query.And(jql.Equals("labels", idempotencyLabel(req.IdempotencyKey)))
+	}
+
+	result, err := c.SearchJQLWithParams(ctx, SearchJQLParams{
+		JQL:        query.OrderBy("created ASC").String(),
+		MaxResults: 50,
+		Fields:     fields,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, issue := range result.Issues {
+		if req.IdempotencyField != "" {
+			value, _ := decodeCustomFieldValue(issue.Fields.CustomFields[req.IdempotencyField])
+			if value != req.IdempotencyKey {
+				continue
+			}
+		}
+		return &CreatedIssue{ID: issue.ID, Key: issue.Key, Self: issue.Self, Existing: true}, nil
+	}
+	return nil, nil
+}
+
+// idempotencyLabel returns the label storing an idempotency key. Labels
+// cannot contain spaces.
+func idempotencyLabel(key string) string {
+	return idempotencyLabelPrefix + strings.Join(strings.Fields(key), "_")
+}
+
+// idempotencyLabelPrefix prefixes idempotency keys stored as labels.
+const idempotencyLabelPrefix = "idempotency-"
+
+// maxLabelLength is the maximum length of a Jira label.
+const maxLabelLength = 255
+
+// jqlFieldRef returns the JQL reference of a field ID: cf[10050] for
+// customfield_10050, the ID itself otherwise.
+func jqlFieldRef(fieldID string) string {
+	if id, ok := strings.CutPrefix(fieldID, "customfield_"); ok {
+		return "cf[" + id + "]"
+	}
+	return fieldID
+}
+
+// CreateIssueInput is the input for CreateIssueActivity.
+type CreateIssueInput struct {
+	BaseURL  string
+	Email    string
+	APIToken string
+	Request  CreateIssueRequest
+
+	ClientOptions
+}
+
+// CreateIssueOutput is the output of CreateIssueActivity.
+type CreateIssueOutput struct {
+	ID       string
+	Key      string
+	Existing bool
+}
+
+// CreateIssueActivity creates an issue. Set Request.IdempotencyKey so that
+// a retried activity returns the issue created by an earlier attempt.
+func CreateIssueActivity(ctx context.Context, input CreateIssueInput) (CreateIssueOutput, error) {
+	client, err := input.ClientOptions.newClient(ClientConfig{
+		BaseURL:  input.BaseURL,
+		Email:    input.Email,
+		APIToken: input.APIToken,
+	})
+	if err != nil {
+		return CreateIssueOutput{}, err
+	}
+	defer client.Close()
+
+	created, err := client.CreateIssue(ctx, input.Request)
+	if err != nil {
+		return CreateIssueOutput{}, fmt.Errorf("create issue: %w", err)
+	}
+
+	return CreateIssueOutput{
+		ID:       created.ID,
+		Key:      created.Key,
+		Existing: created.Existing,
+	}, nil
+}
+
+// CreateIssue creates a node for creating an issue.
+func CreateIssue(input CreateIssueInput) *core.Node[CreateIssueInput, CreateIssueOutput] {
+	return core.NewNode("jira.CreateIssue", CreateIssueActivity, input)
+}
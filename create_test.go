@@ -0,0 +1,179 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCreateIssueIdempotency(t *testing.T) {
+	existing := func(fields map[string]any) []map[string]any {
+		return []map[string]any{testIssue("PROJ-7", fields)}
+	}
+
+	tests := []struct {
+		name         string
+		request      CreateIssueRequest
+		found        []map[string]any
+		wantKey      string
+		wantExisting bool
+		wantJQL      string
+		wantLabels   string
+		wantField    string
+		wantErr      string
+	}{
+		{
+			name:       "without a key",
+			request:    CreateIssueRequest{Labels: []string{"ops"}},
+			wantKey:    "PROJ-8",
+			wantLabels: "ops",
+		},
+		{
+			name:       "label key, new",
+			request:    CreateIssueRequest{Labels: []string{"ops"}, IdempotencyKey: "run 1/step"},
+			wantKey:    "PROJ-8",
+			wantJQL:    `project = "PROJ" AND labels = "idempotency-run_1/step" ORDER BY created ASC`,
+			wantLabels: "ops,idempotency-run_1/step",
+		},
+		{
+			name:         "label key, existing",
+			request:      CreateIssueRequest{IdempotencyKey: "run 1/step"},
+			found:        existing(nil),
+			wantKey:      "PROJ-7",
+			wantExisting: true,
+			wantJQL:      `project = "PROJ" AND labels = "idempotency-run_1/step" ORDER BY created ASC`,
+		},
+		{
+			name:      "field key, fuzzy match only",
+			request:   CreateIssueRequest{IdempotencyKey: "run-1", IdempotencyField: "customfield_10050"},
+			found:     existing(map[string]any{"customfield_10050": "run-10"}),
+			wantKey:   "PROJ-8",
+			wantJQL:   `project = "PROJ" AND cf[10050] ~ "run-1" ORDER BY created ASC`,
+			wantField: "run-1",
+		},
+		{
+			name:         "field key, existing",
+			request:      CreateIssueRequest{IdempotencyKey: "run-1", IdempotencyField: "customfield_10050"},
+			found:        existing(map[string]any{"customfield_10050": "run-1"}),
+			wantKey:      "PROJ-7",
+			wantExisting: true,
+			wantJQL:      `project = "PROJ" AND cf[10050] ~ "run-1" ORDER BY created ASC`,
+		},
+		{
+			name:    "label key too long",
+			request: CreateIssueRequest{IdempotencyKey: strings.Repeat("k", 244)},
+			wantErr: "idempotency key too long for a label: 244 characters, at most 243",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var searchedJQL string
+			var created map[string]any
+			var requests atomic.Int32
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				switch r.URL.Path {
+				case "/rest/api/3/search":
+					searchedJQL = r.URL.Query().Get("jql")
+					writeJSON(w, map[string]any{"total": len(tt.found), "issues": tt.found})
+				case "/rest/api/3/issue":
+					var body struct {
+						Fields map[string]any `json:"fields"`
+					}
+					if err := decodeBody(r, &body); err != nil {
+						t.Errorf("decode body: %v", err)
+					}
+					created = body.Fields
+					writeJSON(w, map[string]any{"id": "10008", "key": "PROJ-8"})
+				default:
+					http.NotFound(w, r)
+				}
+			}), ClientConfig{})
+
+			tt.request.Project = "PROJ"
+			tt.request.IssueType = "Task"
+			tt.request.Summary = "Rotate keys"
+			issue, err := client.CreateIssue(context.Background(), tt.request)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				if n := requests.Load(); n != 0 {
+					t.Errorf("%d requests sent, want none", n)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateIssue: %v", err)
+			}
+
+			if issue.Key != tt.wantKey || issue.Existing != tt.wantExisting {
+				t.Errorf("issue = %s (existing %v), want %s (%v)", issue.Key, issue.Existing, tt.wantKey, tt.wantExisting)
+			}
+			if searchedJQL != tt.wantJQL {
+				t.Errorf("searched %q, want %q", searchedJQL, tt.wantJQL)
+			}
+			if tt.wantExisting {
+				if created != nil {
+					t.Errorf("created %v, want nothing created", created)
+				}
+				return
+			}
+
+			var labels []string
+			if raw, ok := created["labels"].([]any); ok {
+				for _, label := range raw {
+					labels = append(labels, label.(string))
+				}
+			}
+			if got := strings.Join(labels, ","); got != tt.wantLabels {
+				t.Errorf("labels = %s, want %s", got, tt.wantLabels)
+			}
+			if got, _ := created["customfield_10050"].(string); got != tt.wantField {
+				t.Errorf("idempotency field = %q, want %q", got, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestGetCreatedIssue(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantAttempts int32
+		wantErr      error
+	}{
+		{name: "found at once", statuses: []int{http.StatusOK}, wantAttempts: 1},
+		{name: "found after a not found", statuses: []int{http.StatusNotFound, http.StatusOK}, wantAttempts: 2},
+		{name: "forbidden", statuses: []int{http.StatusForbidden}, wantAttempts: 1, wantErr: ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := tt.statuses[min(int(attempts.Add(1)), len(tt.statuses))-1]
+				if status != http.StatusOK {
+					http.Error(w, `{"errorMessages":["no"]}`, status)
+					return
+				}
+				writeJSON(w, testIssue("PROJ-8", nil))
+			}), ClientConfig{})
+
+			issue, err := client.GetCreatedIssue(context.Background(), "PROJ-8")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && issue.Key != "PROJ-8" {
+				t.Errorf("issue = %s, want PROJ-8", issue.Key)
+			}
+			if n := attempts.Load(); n != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", n, tt.wantAttempts)
+			}
+		})
+	}
+}
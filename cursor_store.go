@@ -0,0 +1,95 @@
+package jira
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CursorStore persists fetch checkpoints outside the workflow, so a
+// backfill too large for one activity run can resume where the previous
+// run stopped. Load returns "" when nothing is saved for key; saving ""
+// clears it.
+type CursorStore interface {
+	Load(key string) (string, error)
+	Save(key, cursor string) error
+}
+
+var cursorStores = newRegistry[CursorStore]("cursor store")
+
+// RegisterCursorStore makes a store available under name for the
+// FetchAllIssuesConfig.CursorStoreName option.
+func RegisterCursorStore(name string, store CursorStore) {
+	cursorStores.register(name, store)
+}
+
+// cursorStore returns CursorStore, or else the store registered under
+// CursorStoreName.
+func (cfg FetchAllIssuesConfig) cursorStore() (CursorStore, error) {
+	if cfg.CursorStore != nil {
+		return cfg.CursorStore, nil
+	}
+	return cursorStores.lookup(cfg.CursorStoreName)
+}
+
+// cursorKey returns the CursorStore key of a fetch: "jira:" followed by
+// the hex SHA-256 of the Jira base URL and the effective JQL, which holds
+// the project and every other filter. Runs resume each other only when
+// they search the same instance with the same query.
+func cursorKey(baseURL, query string) string {
+	sum := sha256.Sum256([]byte(strings.TrimRight(baseURL, "/") + "\n" + query))
+	return "jira:" + hex.EncodeToString(sum[:])
+}
+
+// Checkpoint prefixes, distinguishing offset and cursor-search positions.
+const (
+	checkpointStartAt = "startAt:"
+	checkpointToken   = "token:"
+)
+
+// applyCheckpoint positions params at a checkpoint saved by paginateSearch.
+func applyCheckpoint(params *SearchJQLParams, checkpoint string, cursorSearch bool) error {
+	switch {
+	case checkpoint == "":
+		return nil
+	case strings.HasPrefix(checkpoint, checkpointToken) && cursorSearch:
+		params.NextPageToken = strings.TrimPrefix(checkpoint, checkpointToken)
+		return nil
+	case strings.HasPrefix(checkpoint, checkpointStartAt) && !cursorSearch:
+		startAt, err := strconv.Atoi(strings.TrimPrefix(checkpoint, checkpointStartAt))
+		if err != nil || startAt < 0 {
+			return fmt.Errorf("invalid checkpoint %q", checkpoint)
+		}
+		params.StartAt = startAt
+		return nil
+	default:
+		return fmt.Errorf("checkpoint %q does not match the search mode", checkpoint)
+	}
+}
+
+// checkpointOrderBy is the default order of a checkpointed fetch. Saved
+// offsets stay valid across runs only when issues keep their positions:
+// edits must not move them and new issues must sort last.
+const checkpointOrderBy = "created ASC, key ASC"
+
+// stableOrder reports whether an ORDER BY clause keeps checkpoints valid:
+// every term sorts ascending on a field fixed at creation.
+func stableOrder(orderBy string) bool {
+	for _, term := range strings.Split(orderBy, ",") {
+		fields := strings.Fields(strings.ToLower(term))
+		if len(fields) == 0 || len(fields) > 2 {
+			return false
+		}
+		switch fields[0] {
+		case "created", "key", "issuekey", "id":
+		default:
+			return false
+		}
+		if len(fields) == 2 && fields[1] != "asc" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,105 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/resolute-sh/resolute-jira/adf"
+)
+
+// MetadataField maps a custom field to a document metadata key.
+type MetadataField struct {
+	FieldID string // e.g. "customfield_10020"
+	Key     string // e.g. "team"
+}
+
+// decodeCustomFieldValue renders a custom field value as a string. It
+// understands the common shapes Jira returns: scalars, select options
+// ({"value": ...}, with cascading children as "parent / child"), users
+// ({"displayName": ...}), named objects such as versions and components
+// ({"name": ...}), ADF documents and arrays of any of these, which are
+// comma-joined. It reports false for null, empty or unrecognized values.
+func decodeCustomFieldValue(raw json.RawMessage) (string, bool) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return "", false
+	}
+
+	switch raw[0] {
+	case '"':
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil || s == "" {
+			return "", false
+		}
+		return s, true
+	case '[':
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return "", false
+		}
+		values := make([]string, 0, len(items))
+		for _, item := range items {
+			if value, ok := decodeCustomFieldValue(item); ok {
+				values = append(values, value)
+			}
+		}
+		if len(values) == 0 {
+			return "", false
+		}
+		return strings.Join(values, ", "), true
+	case '{':
+		return decodeCustomFieldObject(raw)
+	default:
+		// Numbers and booleans are rendered as Jira sent them.
+		return string(raw), true
+	}
+}
+
+// decodeCustomFieldObject renders an object-valued custom field.
+func decodeCustomFieldObject(raw json.RawMessage) (string, bool) {
+	if adf.IsDocument(raw) {
+		var probe struct {
+			Type string `json:"type"`
+		}
+		if json.Unmarshal(raw, &probe) == nil && probe.Type == "doc" {
+			text, err := adf.PlainText(raw)
+			if err != nil || strings.TrimSpace(text) == "" {
+				return "", false
+			}
+			return text, true
+		}
+	}
+
+	var obj struct {
+		Value        string          `json:"value"`
+		Child        json.RawMessage `json:"child"`
+		DisplayName  string          `json:"displayName"`
+		Name         string          `json:"name"`
+		EmailAddress string          `json:"emailAddress"`
+		AccountID    string          `json:"accountId"`
+		Key          string          `json:"key"`
+	}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return "", false
+	}
+
+	switch {
+	case obj.Value != "":
+		if child, ok := decodeCustomFieldValue(obj.Child); ok {
+			return obj.Value + " / " + child, true
+		}
+		return obj.Value, true
+	case obj.DisplayName != "":
+		return obj.DisplayName, true
+	case obj.Name != "":
+		return obj.Name, true
+	case obj.EmailAddress != "":
+		return obj.EmailAddress, true
+	case obj.AccountID != "":
+		return obj.AccountID, true
+	case obj.Key != "":
+		return obj.Key, true
+	}
+	return "", false
+}
@@ -0,0 +1,36 @@
+package jira
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDecodeCustomFieldValue(t *testing.T) {
+	tests := []struct {
+		raw    string
+		want   string
+		wantOK bool
+	}{
+		{raw: `null`},
+		{raw: `""`},
+		{raw: `"Platform"`, want: "Platform", wantOK: true},
+		{raw: `8.5`, want: "8.5", wantOK: true},
+		{raw: `true`, want: "true", wantOK: true},
+		{raw: `{"value":"Platform"}`, want: "Platform", wantOK: true},
+		{raw: `{"value":"EMEA","child":{"value":"Berlin"}}`, want: "EMEA / Berlin", wantOK: true},
+		{raw: `{"displayName":"Dana Dev","emailAddress":"dana@acme.com"}`, want: "Dana Dev", wantOK: true},
+		{raw: `{"accountId":"5b10a2844c20165700ede21g"}`, want: "5b10a2844c20165700ede21g", wantOK: true},
+		{raw: `[{"name":"2.3"},{"name":"2.4"},null]`, want: "2.3, 2.4", wantOK: true},
+		{raw: `[]`},
+		{raw: `{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"Rollout notes"}]}]}`, want: "Rollout notes", wantOK: true},
+		{raw: `{"type":"doc","version":1,"content":[]}`},
+		{raw: `{"self":"https://acme.atlassian.net"}`},
+	}
+
+	for _, tt := range tests {
+		got, ok := decodeCustomFieldValue(json.RawMessage(tt.raw))
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("decodeCustomFieldValue(%s) = %q, %v, want %q, %v", tt.raw, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package jira
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"sort"
+
+	transform "github.com/resolute-sh/resolute-transform"
+)
+
+// DiffIssues reports whether any of fields differs between two snapshots
+// of an issue. Fields are named by their Jira IDs, e.g. "summary",
+// "description", "status", "labels" or "customfield_10050". An empty
+// fields compares every field except updated, which changes on every edit.
+func DiffIssues(old, new Issue, fields []string) bool {
+	oldFields := issueFieldValues(old)
+	newFields := issueFieldValues(new)
+
+	if len(fields) == 0 {
+		for field := range oldFields {
+			fields = append(fields, field)
+		}
+		for field := range newFields {
+			if _, ok := oldFields[field]; !ok {
+				fields = append(fields, field)
+			}
+		}
+		fields = slices.DeleteFunc(fields, func(field string) bool { return field == "updated" })
+	}
+
+	for _, field := range fields {
+		if !bytes.Equal(oldFields[field], newFields[field]) {
+			return true
+		}
+	}
+	return false
+}
+
+// issueFieldValues returns the compact JSON value of each field of issue,
+// keyed by Jira field ID. Null and absent fields are left out.
+func issueFieldValues(issue Issue) map[string][]byte {
+	data, _ := json.Marshal(issue.Fields)
+	var raw map[string]json.RawMessage
+	_ = json.Unmarshal(data, &raw)
+
+	values := make(map[string][]byte, len(raw)+len(issue.Fields.CustomFields))
+	add := func(field string, value json.RawMessage) {
+		var b bytes.Buffer
+		if json.Compact(&b, value) != nil || b.String() == "null" {
+			return
+		}
+		values[field] = b.Bytes()
+	}
+
+	for field, value := range raw {
+		switch field {
+		case "customFields":
+			continue
+		case "description":
+			// Compare the ADF source when present; the rendered text
+			// is only a fallback.
+			if len(issue.Fields.DescriptionADF) > 0 {
+				continue
+			}
+		}
+		if field == "descriptionADF" {
+			field = "description"
+		}
+		add(field, value)
+	}
+	for field, value := range issue.Fields.CustomFields {
+		add(field, value)
+	}
+	return values
+}
+
+// DocumentFingerprint returns a stable hash of a document's title, content
+// and the given metadata keys, or all metadata when keys is empty. Store it
+// alongside an indexed document and skip re-embedding when it is unchanged.
+func DocumentFingerprint(doc transform.Document, keys ...string) string {
+	if len(keys) == 0 {
+		for key := range doc.Metadata {
+			keys = append(keys, key)
+		}
+	}
+	keys = append([]string(nil), keys...)
+	sort.Strings(keys)
+
+	h := sha256.New()
+	write := func(s string) {
+		// Length-prefix each part so boundaries cannot shift.
+		h.Write(binary.LittleEndian.AppendUint64(nil, uint64(len(s))))
+		h.Write([]byte(s))
+	}
+
+	write(doc.Title)
+	write(doc.Content)
+	for _, key := range keys {
+		value, ok := doc.Metadata[key]
+		if !ok {
+			continue
+		}
+		write(key)
+		write(value)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,85 @@
+package jira
+
+import (
+	"testing"
+
+	transform "github.com/resolute-sh/resolute-transform"
+)
+
+func TestDiffIssues(t *testing.T) {
+	adfDescription := func(s string) map[string]any {
+		return map[string]any{"type": "doc", "version": 1, "content": []any{
+			map[string]any{"type": "paragraph", "content": []any{map[string]any{"type": "text", "text": s}}},
+		}}
+	}
+	base := map[string]any{
+		"summary":           "Rotate keys",
+		"labels":            []any{"ops"},
+		"updated":           "2024-03-01T10:00:00.000+0000",
+		"customfield_10050": map[string]any{"value": "Platform"},
+		"description":       adfDescription("Before"),
+	}
+	with := func(field string, value any) map[string]any {
+		fields := make(map[string]any, len(base))
+		for k, v := range base {
+			fields[k] = v
+		}
+		if value == nil {
+			delete(fields, field)
+		} else {
+			fields[field] = value
+		}
+		return fields
+	}
+
+	tests := []struct {
+		name   string
+		new    map[string]any
+		fields []string
+		want   bool
+	}{
+		{name: "unchanged", new: with("summary", "Rotate keys")},
+		{name: "only updated", new: with("updated", "2024-03-02T10:00:00.000+0000")},
+		{name: "only updated, compared", new: with("updated", "2024-03-02T10:00:00.000+0000"), fields: []string{"updated"}, want: true},
+		{name: "summary", new: with("summary", "Rotate all keys"), want: true},
+		{name: "summary, not compared", new: with("summary", "Rotate all keys"), fields: []string{"labels"}},
+		{name: "labels", new: with("labels", []any{"ops", "security"}), fields: []string{"labels"}, want: true},
+		{name: "custom field", new: with("customfield_10050", map[string]any{"value": "Payments"}), fields: []string{"customfield_10050"}, want: true},
+		{name: "custom field cleared", new: with("customfield_10050", nil), want: true},
+		{name: "description", new: with("description", adfDescription("After")), fields: []string{"description"}, want: true},
+		{name: "field added", new: with("priority", map[string]any{"name": "High"}), want: true},
+	}
+
+	old := decodeIssue(t, "PROJ-1", base)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DiffIssues(old, decodeIssue(t, "PROJ-1", tt.new), tt.fields); got != tt.want {
+				t.Errorf("DiffIssues = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocumentFingerprint(t *testing.T) {
+	doc := transform.Document{
+		Title:    "PROJ-1",
+		Content:  "Rotate keys",
+		Metadata: map[string]string{"status": "Open", "updated": "2024-03-01"},
+	}
+	fingerprint := DocumentFingerprint(doc)
+
+	changed := doc
+	changed.Metadata = map[string]string{"status": "Open", "updated": "2024-03-02"}
+	if DocumentFingerprint(changed) == fingerprint {
+		t.Errorf("fingerprint ignores a metadata change")
+	}
+	if DocumentFingerprint(changed, "status") != DocumentFingerprint(doc, "status") {
+		t.Errorf("fingerprint depends on metadata that was not selected")
+	}
+
+	// Moving text between title and content must change the hash.
+	shifted := transform.Document{Title: "PROJ-1Rotate", Content: " keys", Metadata: doc.Metadata}
+	if DocumentFingerprint(shifted) == fingerprint {
+		t.Errorf("fingerprint ignores part boundaries")
+	}
+}
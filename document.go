@@ -0,0 +1,1003 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/resolute-sh/resolute-jira/adf"
+	"github.com/resolute-sh/resolute-jira/jql"
+	transform "github.com/resolute-sh/resolute-transform"
+	"github.com/resolute-sh/resolute/core"
+)
+
+// ThinContentMode selects what happens to issues with too little content.
+type ThinContentMode string
+
+const (
+	// ThinContentFlag keeps thin issues and marks them with
+	// thin_content=true metadata.
+	ThinContentFlag ThinContentMode = "flag"
+
+	// ThinContentSkip drops thin issues; their keys are reported as
+	// skipped.
+	ThinContentSkip ThinContentMode = "skip"
+)
+
+// Document orders accepted by DocumentOptions.SortDocumentsBy.
+const (
+	SortByKey     = "key"
+	SortByUpdated = "updated"
+	SortByCreated = "created"
+	SortByRank    = "rank"
+)
+
+// DocumentOptions controls how issues are converted to documents.
+type DocumentOptions struct {
+	// ExtraContentFields promotes custom fields into the document content
+	// as labeled sections, in order. Empty or absent fields are skipped.
+	ExtraContentFields []ContentField
+
+	// IncludeEnvironment appends the environment field, where bug reports
+	// usually describe their setup, as an "Environment:" section after the
+	// description. Empty environments are skipped.
+	IncludeEnvironment bool
+
+	// MetadataFields copies custom fields into the document metadata.
+	// Option, user, version and array values are rendered as readable
+	// strings; empty or absent fields are skipped.
+	MetadataFields []MetadataField
+
+	// IncludeAllComments fetches every comment of each issue instead of
+	// relying on the comments embedded in the search response, at the cost
+	// of one extra request per issue. When the comment endpoint is forbidden
+	// or not found, the embedded comments are used and the document gets
+	// comments_accessible=false.
+	IncludeAllComments bool
+
+	// IncludeAttachmentMetadata writes attachment_count and an attachments
+	// JSON array (filename, size, mime type, author, created) into the
+	// document metadata. Attachment content is not downloaded.
+	IncludeAttachmentMetadata bool
+
+	// ExtraMetadata is merged into every document's metadata, e.g. a tenant
+	// or sync run ID. Keys derived from the issue take precedence over
+	// colliding extra keys.
+	ExtraMetadata map[string]string
+
+	// ExplodeComments emits each comment as its own document, following
+	// its issue's document, instead of appending comments to the issue
+	// content. Comment documents have IDs like "PROJ-1#comment-10001" and
+	// parent_issue, comment_id, author and created metadata.
+	ExplodeComments bool
+
+	// StoreDescriptionFormats also stores the description as its ADF JSON
+	// under the "description_adf" metadata key and as HTML under
+	// "description_html", so consumers needing another representation do
+	// not re-fetch the issue. Content stays plain text. A representation
+	// larger than 32 KiB is left out rather than truncated, which would
+	// leave invalid JSON or HTML.
+	StoreDescriptionFormats bool
+
+	// ExcludeKeys lists issues that must never be stored. They are dropped
+	// before conversion and counted as Excluded; FetchAllIssueDocuments
+	// and SearchAllJQLDocuments also exclude up to 100 of them in the JQL,
+	// so those are not fetched at all.
+	ExcludeKeys []string
+
+	// MaxComments keeps only the N most recent comments in the issue
+	// content, whole, after a note counting the earlier ones left out.
+	// Zero keeps all. It does not apply to ExplodeComments. Comments are
+	// never cut by size, as there is no byte limit on them, so with long
+	// comments a small N is what bounds the content.
+	MaxComments int
+
+	// CommentAuthorFilter, such as one built with SameDomain, leaves
+	// comments by the authors it rejects out of the content and comment
+	// documents; they are not counted by MaxComments either. Activity
+	// inputs name a filter registered with RegisterCommentAuthorFilter in
+	// CommentAuthorFilterName instead. Neither includes all comments.
+	CommentAuthorFilter     CommentAuthorFilter `json:"-"`
+	CommentAuthorFilterName string
+
+	// LabelsAsKeys writes the issue's labels as the comma-joined labels
+	// metadata key and, for exact presence filters, as one
+	// label_<name>=true key per label. Names are lowercased, with
+	// characters other than ASCII letters, digits and underscores replaced
+	// by underscores. Only the first 50 labels get a key of their own.
+	LabelsAsKeys bool
+
+	// IncludeAggregateTime writes the rolled-up estimates of issues with
+	// children, such as epics, as the aggregate_original_estimate_seconds,
+	// aggregate_remaining_estimate_seconds, aggregate_time_spent_seconds
+	// and aggregate_progress_percent metadata keys, each only when set.
+	IncludeAggregateTime bool
+
+	// IncludeComponents writes the issue's component names as the
+	// components metadata key and their leads as component_leads, both
+	// comma-joined. Leads are given by account ID, or username on Server
+	// and Data Center.
+	IncludeComponents bool
+
+	// ResolveComponentLeads looks up the leads of components, which search
+	// results usually omit, with one extra request per distinct project.
+	// It only applies with IncludeComponents.
+	ResolveComponentLeads bool
+
+	// SprintField is the ID of the sprint custom field, e.g.
+	// "customfield_10020". With ResolveSprintNames it is used to backfill
+	// sprint_name and sprint_state metadata.
+	SprintField string
+
+	// ResolveSprintNames looks up the sprints referenced by SprintField,
+	// once per distinct sprint, for instances whose sprint field carries
+	// only IDs.
+	ResolveSprintNames bool
+
+	// ResolveProjectCategories looks up, once per project, the category of
+	// issues whose embedded project does not carry it. The category is
+	// written as the project_category metadata key whenever known.
+	ResolveProjectCategories bool
+
+	// IncludeParentContext prepends "Parent: KEY — summary" and the
+	// parent's description to the content of sub-tasks. Parents are
+	// fetched in batches, once each; grandparents are not included.
+	IncludeParentContext bool
+
+	// RequireContentBytes treats issues whose assembled content is shorter
+	// than this many bytes as thin, e.g. sub-tasks with only a summary.
+	// 0 disables the check.
+	RequireContentBytes int
+
+	// ThinContentMode is applied to thin issues. Default ThinContentFlag.
+	ThinContentMode ThinContentMode
+
+	// Classifier, e.g. DefaultClassifier, derives the category metadata
+	// key. Activity inputs name a classifier registered with
+	// RegisterClassifier in ClassifierName instead, e.g.
+	// DefaultClassifierName. Neither writes no category.
+	Classifier     Classifier `json:"-"`
+	ClassifierName string
+
+	// SLAField is the ID of a Jira Service Management SLA field, e.g.
+	// "customfield_10030" for "Time to resolution", whose state is written
+	// as the sla_breached and sla_remaining_ms metadata keys.
+	SLAField string
+
+	// RankField is the ID of the agile rank custom field, e.g.
+	// "customfield_10019", whose lexorank value is written as the rank
+	// metadata key.
+	RankField string
+
+	// MetadataKeyAliases renames metadata keys in the stored documents,
+	// e.g. {"status": "jira_status"}, to fit an index's schema. Keys not in
+	// the map are unchanged. A renamed key replaces an existing key of the
+	// same name, so {"status": "state"} drops any original state key, and
+	// keys can be swapped. Two keys may not be renamed to the same name.
+	MetadataKeyAliases map[string]string
+
+	// SortDocumentsBy sorts the stored documents by SortByKey (natural
+	// order, so PROJ-2 precedes PROJ-10), SortByUpdated or SortByCreated
+	// (oldest first, ties broken by key), or SortByRank (backlog order by
+	// the rank metadata, unranked issues last), making stored order
+	// reproducible across runs. Comment documents follow their issue.
+	// Empty keeps fetch order.
+	SortDocumentsBy string
+
+	// ContinueOnError leaves out issues that fail to convert, e.g. for
+	// malformed ADF or a failed comment fetch, and reports them as
+	// FailedIssues instead of failing the activity. The same goes for
+	// documents that still fail to store after retries: every issue of the
+	// failed chunk, or of the whole batch without a ChunkSize, is reported
+	// with a "store: " reason.
+	ContinueOnError bool
+
+	// ConversionWorkers converts up to this many issues of a page to
+	// documents concurrently, which speeds up pages of large ADF
+	// descriptions on multi-core workers. Output order is preserved.
+	// 0 or 1 converts serially.
+	ConversionWorkers int
+
+	// ADF controls how ADF descriptions, comments and content fields are
+	// rendered to plain text.
+	ADF adf.Options
+}
+
+// attachmentSummary is the per-attachment entry of the attachments metadata value.
+type attachmentSummary struct {
+	Filename string `json:"filename"`
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	Author   string `json:"author,omitempty"`
+	Created  string `json:"created"`
+}
+
+// ContentField maps a custom field to a labeled section of document content.
+type ContentField struct {
+	FieldID string // e.g. "customfield_10050"
+	Label   string // e.g. "Acceptance Criteria"
+}
+
+// FailedIssue records an issue left out of the stored documents because
+// it could not be converted or stored.
+type FailedIssue struct {
+	Key    string
+	Reason string
+}
+
+// conversion is the result of issuesToDocuments.
+type conversion struct {
+	Docs    []transform.Document
+	Skipped []string      // keys of issues skipped for thin content
+	Failed  []FailedIssue // with ContinueOnError, issues that failed
+
+	Excluded int // issues dropped by ExcludeKeys
+}
+
+// add appends another page's conversion.
+func (c *conversion) add(page conversion) {
+	c.Docs = append(c.Docs, page.Docs...)
+	c.Skipped = append(c.Skipped, page.Skipped...)
+	c.Failed = append(c.Failed, page.Failed...)
+	c.Excluded += page.Excluded
+}
+
+// maxExcludeClauseKeys is the largest ExcludeKeys list also excluded in
+// the JQL; longer lists would make the query unwieldy.
+const maxExcludeClauseKeys = 100
+
+// excludeKeysClause adds a "key not in" clause for opts.ExcludeKeys to
+// query when the list is short enough. An unbounded query is left alone so
+// the search still refuses it with ErrUnboundedJQL.
+func excludeKeysClause(query string, opts DocumentOptions) string {
+	if len(opts.ExcludeKeys) > maxExcludeClauseKeys || jql.IsUnbounded(query) {
+		return query
+	}
+	return jql.AndClause(query, jql.NotIn("key", opts.ExcludeKeys))
+}
+
+// issuesToDocuments converts issues to documents, fetching any additional
+// data the options require. Documents are returned in the order of issues.
+// A failure converting one issue fails the call unless ContinueOnError is
+// set, in which case the issue is reported in Failed instead.
+func issuesToDocuments(ctx context.Context, client *Client, issues []Issue, opts DocumentOptions) (conversion, error) {
+	if _, err := opts.classifier(); err != nil {
+		return conversion{}, err
+	}
+	authorFilter, err := opts.commentAuthorFilter()
+	if err != nil {
+		return conversion{}, err
+	}
+	if err := validateMetadataAliases(opts.MetadataKeyAliases); err != nil {
+		return conversion{}, err
+	}
+	switch opts.SortDocumentsBy {
+	case "", SortByKey, SortByUpdated, SortByCreated, SortByRank:
+	default:
+		return conversion{}, fmt.Errorf("unknown document order %q", opts.SortDocumentsBy)
+	}
+
+	issues = append([]Issue(nil), issues...)
+	var excluded int
+	if len(opts.ExcludeKeys) > 0 {
+		exclude := make(map[string]bool, len(opts.ExcludeKeys))
+		for _, key := range opts.ExcludeKeys {
+			exclude[key] = true
+		}
+		issues = slices.DeleteFunc(issues, func(issue Issue) bool {
+			if exclude[issue.Key] {
+				excluded++
+				return true
+			}
+			return false
+		})
+	}
+	failures := make([]error, len(issues))
+	commentsAccessible := make([]bool, len(issues))
+	for i := range issues {
+		commentsAccessible[i] = true
+		if !opts.IncludeAllComments {
+			continue
+		}
+
+		key := issues[i].Key
+		comments, err := client.GetComments(ctx, key)
+		switch {
+		case errors.Is(err, ErrForbidden), errors.Is(err, ErrNotFound):
+			client.logger.WarnContext(ctx, "jira: comments inaccessible, using embedded comments",
+				"issue", key, "error", err)
+			commentsAccessible[i] = false
+		case err != nil && opts.ContinueOnError && ctx.Err() == nil:
+			failures[i] = fmt.Errorf("get comments: %w", err)
+		case err != nil:
+			return conversion{}, fmt.Errorf("get comments for %s: %w", key, err)
+		default:
+			issues[i].Fields.Comments = &Comments{Total: len(comments), Comments: comments}
+		}
+	}
+
+	var sprints map[int]Sprint
+	if opts.ResolveSprintNames && opts.SprintField != "" {
+		var err error
+		sprints, err = client.resolveSprints(ctx, issues, opts.SprintField)
+		if err != nil {
+			return conversion{}, err
+		}
+	}
+
+	if opts.IncludeComponents && opts.ResolveComponentLeads {
+		if err := client.resolveComponentLeads(ctx, issues); err != nil {
+			return conversion{}, err
+		}
+	}
+
+	if opts.ResolveProjectCategories {
+		if err := client.resolveProjectCategories(ctx, issues); err != nil {
+			return conversion{}, err
+		}
+	}
+
+	var parents map[string]Issue
+	if opts.IncludeParentContext {
+		var err error
+		parents, err = client.resolveParents(ctx, issues)
+		if err != nil {
+			return conversion{}, err
+		}
+	}
+
+	perIssue := make([][]transform.Document, len(issues))
+	convert := func(i int) {
+		if failures[i] != nil {
+			return
+		}
+		if err := checkRenderable(issues[i], opts); err != nil {
+			failures[i] = err
+			return
+		}
+
+		doc := issueToDocument(issues[i], opts)
+		if !commentsAccessible[i] {
+			doc.Metadata["comments_accessible"] = "false"
+		}
+		for key, value := range sprintMetadata(issues[i].Fields.CustomFields[opts.SprintField], sprints) {
+			doc.Metadata[key] = value
+		}
+		if len(doc.Content) < opts.RequireContentBytes {
+			if opts.ThinContentMode == ThinContentSkip {
+				return
+			}
+			doc.Metadata["thin_content"] = "true"
+		}
+		if block := parentContext(issues[i], parents, opts); block != "" {
+			doc.Content = block + "\n\n" + doc.Content
+		}
+		perIssue[i] = []transform.Document{doc}
+		if opts.ExplodeComments && issues[i].Fields.Comments != nil {
+			for _, comment := range filterComments(issues[i].Fields.Comments.Comments, authorFilter) {
+				perIssue[i] = append(perIssue[i], commentToDocument(issues[i], comment, opts))
+			}
+		}
+	}
+
+	workers := min(opts.ConversionWorkers, len(issues))
+	if workers <= 1 {
+		for i := range issues {
+			convert(i)
+		}
+	} else {
+		next := make(chan int)
+		var wg sync.WaitGroup
+		for range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range next {
+					convert(i)
+				}
+			}()
+		}
+		for i := range issues {
+			next <- i
+		}
+		close(next)
+		wg.Wait()
+	}
+
+	result := conversion{Docs: flattenDocuments(perIssue), Excluded: excluded}
+	for i, docs := range perIssue {
+		switch {
+		case failures[i] != nil && !opts.ContinueOnError:
+			return conversion{}, fmt.Errorf("convert %s: %w", issues[i].Key, failures[i])
+		case failures[i] != nil:
+			client.logger.WarnContext(ctx, "jira: issue conversion failed", "issue", issues[i].Key, "error", failures[i])
+			result.Failed = append(result.Failed, FailedIssue{Key: issues[i].Key, Reason: failures[i].Error()})
+		case len(docs) == 0:
+			result.Skipped = append(result.Skipped, issues[i].Key)
+		}
+	}
+	return result, nil
+}
+
+// checkRenderable reports ADF in the description or comments of issue
+// that cannot be rendered, which the lenient decoding let through.
+func checkRenderable(issue Issue, opts DocumentOptions) error {
+	if len(issue.Fields.DescriptionADF) > 0 {
+		if _, err := adf.PlainTextWithOptions(issue.Fields.DescriptionADF, opts.ADF); err != nil {
+			return fmt.Errorf("render description: %w", err)
+		}
+	}
+	if issue.Fields.Comments != nil {
+		for _, comment := range issue.Fields.Comments.Comments {
+			if len(comment.BodyADF) == 0 {
+				continue
+			}
+			if _, err := adf.PlainTextWithOptions(comment.BodyADF, opts.ADF); err != nil {
+				return fmt.Errorf("render comment %s: %w", comment.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// storeDocuments stores docs, first sorting them and renaming their
+// metadata keys as opts require. The metadata of docs is left as is, as
+// callers still read it, e.g. to count comment documents.
+func storeDocuments(ctx context.Context, docs []transform.Document, opts DocumentOptions) (core.DataRef, error) {
+	if err := validateMetadataAliases(opts.MetadataKeyAliases); err != nil {
+		return core.DataRef{}, err
+	}
+	sortDocuments(docs, opts.SortDocumentsBy)
+	return storeWithRetry(ctx, aliasMetadata(docs, opts.MetadataKeyAliases))
+}
+
+// storeAttempts bounds the attempts of storeWithRetry.
+const storeAttempts = 3
+
+// storeWithRetry stores docs, retrying failures with backoff so a storage
+// hiccup at the end of a long fetch does not lose it. Attempts stop early
+// when ctx is done.
+func storeWithRetry(ctx context.Context, docs []transform.Document) (core.DataRef, error) {
+	for attempt := 1; ; attempt++ {
+		ref, err := transform.StoreDocuments(ctx, docs)
+		if err == nil || attempt == storeAttempts || ctx.Err() != nil {
+			if err != nil && attempt > 1 {
+				err = fmt.Errorf("after %d attempts: %w", attempt, err)
+			}
+			return ref, err
+		}
+		if err := sleep(ctx, backoff(attempt, nil)); err != nil {
+			return core.DataRef{}, err
+		}
+	}
+}
+
+// validateMetadataAliases rejects aliases renaming several keys to the
+// same name, which would make the surviving value depend on map order.
+func validateMetadataAliases(aliases map[string]string) error {
+	sources := make(map[string]string, len(aliases))
+	for from, to := range aliases {
+		if to == "" {
+			return fmt.Errorf("metadata key %q aliased to an empty name", from)
+		}
+		if other, ok := sources[to]; ok {
+			first, second := min(from, other), max(from, other)
+			return fmt.Errorf("metadata keys %q and %q both aliased to %q", first, second, to)
+		}
+		sources[to] = from
+	}
+	return nil
+}
+
+// aliasMetadata returns copies of docs with their metadata keys renamed
+// by aliases, or docs itself when there are none.
+func aliasMetadata(docs []transform.Document, aliases map[string]string) []transform.Document {
+	if len(aliases) == 0 {
+		return docs
+	}
+
+	aliased := make([]transform.Document, len(docs))
+	for i, doc := range docs {
+		metadata := make(map[string]string, len(doc.Metadata))
+		for key, value := range doc.Metadata {
+			if _, renamed := aliases[key]; !renamed {
+				metadata[key] = value
+			}
+		}
+		// Renamed keys go last so they replace same-named original keys.
+		for key, value := range doc.Metadata {
+			if alias, ok := aliases[key]; ok {
+				metadata[alias] = value
+			}
+		}
+		doc.Metadata = metadata
+		aliased[i] = doc
+	}
+	return aliased
+}
+
+// storeConversion stores the documents of converted. With ContinueOnError
+// a storage failure does not fail the call: the issues of the documents
+// move to converted.Failed, its Docs are emptied and the zero ref is
+// returned.
+func storeConversion(ctx context.Context, client *Client, converted *conversion, opts DocumentOptions) (core.DataRef, error) {
+	ref, err := storeDocuments(ctx, converted.Docs, opts)
+	if err == nil || !opts.ContinueOnError || ctx.Err() != nil {
+		return ref, err
+	}
+	client.logger.WarnContext(ctx, "jira: storing documents failed", "documents", len(converted.Docs), "error", err)
+	converted.Failed = append(converted.Failed, storeFailures(converted.Docs, err)...)
+	converted.Docs = nil
+	return core.DataRef{}, nil
+}
+
+// storeFailures reports each issue of docs, which failed to store with err.
+func storeFailures(docs []transform.Document, err error) []FailedIssue {
+	var failed []FailedIssue
+	seen := make(map[string]bool)
+	for _, doc := range docs {
+		key := documentIssueKey(doc)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		failed = append(failed, FailedIssue{Key: key, Reason: "store: " + err.Error()})
+	}
+	return failed
+}
+
+// sortDocuments sorts docs in place by the given order. Comment documents
+// sort with their parent issue, after it.
+func sortDocuments(docs []transform.Document, by string) {
+	if by == "" {
+		return
+	}
+
+	issueTimes := make(map[string]time.Time)
+	if by == SortByCreated || by == SortByUpdated {
+		for _, doc := range docs {
+			if doc.Metadata["parent_issue"] != "" {
+				continue
+			}
+			t := doc.UpdatedAt
+			if by == SortByCreated {
+				t, _ = parseTime(doc.Metadata["created"])
+			}
+			issueTimes[doc.ID] = t
+		}
+	}
+
+	issueRanks := make(map[string]string)
+	if by == SortByRank {
+		for _, doc := range docs {
+			if doc.Metadata["parent_issue"] == "" {
+				issueRanks[doc.ID] = doc.Metadata["rank"]
+			}
+		}
+	}
+
+	sort.SliceStable(docs, func(i, j int) bool {
+		ki, kj := documentIssueKey(docs[i]), documentIssueKey(docs[j])
+		if ki != kj {
+			if by == SortByRank {
+				ri, rj := issueRanks[ki], issueRanks[kj]
+				if ri != rj {
+					// Lexoranks compare bytewise; unranked issues go last.
+					return rj == "" || (ri != "" && ri < rj)
+				}
+			} else if by != SortByKey {
+				ti, tj := issueTimes[ki], issueTimes[kj]
+				if !ti.Equal(tj) {
+					return ti.Before(tj)
+				}
+			}
+			return lessIssueKey(ki, kj)
+		}
+		// Same issue: the issue document precedes its comments.
+		return docs[i].Metadata["parent_issue"] == "" && docs[j].Metadata["parent_issue"] != ""
+	})
+}
+
+// documentIssueKey returns the key of the issue a document belongs to.
+func documentIssueKey(doc transform.Document) string {
+	if parent := doc.Metadata["parent_issue"]; parent != "" {
+		return parent
+	}
+	return doc.ID
+}
+
+// lessIssueKey orders issue keys by project, then numerically by number.
+func lessIssueKey(a, b string) bool {
+	pa, na, okA := splitIssueKey(a)
+	pb, nb, okB := splitIssueKey(b)
+	if !okA || !okB || pa != pb {
+		return a < b
+	}
+	return na < nb
+}
+
+// splitIssueKey splits "PROJ-12" into "PROJ" and 12.
+func splitIssueKey(key string) (string, int, bool) {
+	i := strings.LastIndexByte(key, '-')
+	if i < 0 {
+		return "", 0, false
+	}
+	n, err := strconv.Atoi(key[i+1:])
+	if err != nil {
+		return "", 0, false
+	}
+	return key[:i], n, true
+}
+
+// flattenDocuments concatenates per-issue documents in issue order.
+func flattenDocuments(perIssue [][]transform.Document) []transform.Document {
+	var n int
+	for _, docs := range perIssue {
+		n += len(docs)
+	}
+	flat := make([]transform.Document, 0, n)
+	for _, docs := range perIssue {
+		flat = append(flat, docs...)
+	}
+	return flat
+}
+
+// countDocuments splits a document count into issue and comment documents.
+func countDocuments(docs []transform.Document) (issues, comments int) {
+	for _, doc := range docs {
+		if doc.Metadata["parent_issue"] != "" {
+			comments++
+		} else {
+			issues++
+		}
+	}
+	return issues, comments
+}
+
+// maxDescriptionFormatBytes caps each representation stored by
+// setDescriptionFormats.
+const maxDescriptionFormatBytes = 32 << 10
+
+// setDescriptionFormats stores the ADF and HTML forms of the description of
+// fields in metadata, leaving out those that are unavailable or too large.
+func setDescriptionFormats(metadata map[string]string, fields IssueFields) {
+	raw := fields.DescriptionADF
+	if len(raw) == 0 && fields.Description != "" {
+		raw, _ = json.Marshal(fields.Description)
+	}
+	if len(raw) == 0 {
+		return
+	}
+
+	if adf.IsDocument(raw) && len(raw) <= maxDescriptionFormatBytes {
+		var compact bytes.Buffer
+		if json.Compact(&compact, raw) == nil {
+			metadata["description_adf"] = compact.String()
+		}
+	}
+
+	if html, err := adf.HTML(raw); err == nil && html != "" && len(html) <= maxDescriptionFormatBytes {
+		metadata["description_html"] = html
+	}
+}
+
+// setAggregateTimeMetadata writes the aggregate time tracking metadata
+// keys of the aggregate fields that are set.
+func setAggregateTimeMetadata(metadata map[string]string, fields IssueFields) {
+	for key, seconds := range map[string]*int64{
+		"aggregate_original_estimate_seconds":  fields.AggregateOriginalEstimate,
+		"aggregate_remaining_estimate_seconds": fields.AggregateRemainingEstimate,
+		"aggregate_time_spent_seconds":         fields.AggregateTimeSpent,
+	} {
+		if seconds != nil {
+			metadata[key] = strconv.FormatInt(*seconds, 10)
+		}
+	}
+	if progress := fields.AggregateProgress; progress != nil && progress.Percent != nil {
+		metadata["aggregate_progress_percent"] = strconv.Itoa(*progress.Percent)
+	}
+}
+
+// maxLabelKeys bounds the label_<name> metadata keys of a document.
+const maxLabelKeys = 50
+
+// setLabelMetadata writes the labels metadata key and a label_<name> key
+// per label, up to maxLabelKeys.
+func setLabelMetadata(metadata map[string]string, labels []string) {
+	if len(labels) == 0 {
+		return
+	}
+	metadata["labels"] = strings.Join(labels, ",")
+	for _, label := range labels[:min(len(labels), maxLabelKeys)] {
+		metadata["label_"+labelKey(label)] = "true"
+	}
+}
+
+// labelKey turns a label into a metadata key suffix: lowercase letters,
+// digits and underscores only.
+func labelKey(label string) string {
+	return strings.Map(func(r rune) rune {
+		r = unicode.ToLower(r)
+		if r == '_' || ('a' <= r && r <= 'z') || ('0' <= r && r <= '9') {
+			return r
+		}
+		return '_'
+	}, label)
+}
+
+// setComponentMetadata writes the components and component_leads
+// metadata keys, leaving out empty values and duplicate leads.
+func setComponentMetadata(metadata map[string]string, components []Component) {
+	var names, leads []string
+	seen := make(map[string]bool)
+	for _, component := range components {
+		names = append(names, component.Name)
+		if component.Lead == nil {
+			continue
+		}
+		lead := component.Lead.AccountID
+		if lead == "" {
+			lead = component.Lead.Name
+		}
+		if lead != "" && !seen[lead] {
+			seen[lead] = true
+			leads = append(leads, lead)
+		}
+	}
+	if len(names) > 0 {
+		metadata["components"] = strings.Join(names, ",")
+	}
+	if len(leads) > 0 {
+		metadata["component_leads"] = strings.Join(leads, ",")
+	}
+}
+
+// setTimestamp sets metadata[key] to the Jira timestamp value normalized
+// to UTC in RFC 3339, keeping the original offset-bearing value under
+// key+"_local". A value that does not parse is stored as is.
+func setTimestamp(metadata map[string]string, key, value string) {
+	if value == "" {
+		return
+	}
+
+	t, err := parseTime(value)
+	if err != nil {
+		metadata[key] = value
+		return
+	}
+
+	metadata[key] = t.UTC().Format(time.RFC3339)
+	metadata[key+"_local"] = value
+}
+
+// commentToDocument converts a comment of issue to its own document.
+func commentToDocument(issue Issue, comment Comment, opts DocumentOptions) transform.Document {
+	updated := comment.Updated
+	if updated == "" {
+		updated = comment.Created
+	}
+	var updatedAt time.Time
+	if updated != "" {
+		updatedAt, _ = parseTime(updated)
+		updatedAt = updatedAt.UTC()
+	}
+
+	metadata := map[string]string{
+		"parent_issue": issue.Key,
+		"project":      issue.Fields.Project.Key,
+		"comment_id":   comment.ID,
+		"author":       comment.Author.DisplayName,
+	}
+	setTimestamp(metadata, "created", comment.Created)
+	setTimestamp(metadata, "updated", comment.Updated)
+	for key, value := range opts.ExtraMetadata {
+		if _, exists := metadata[key]; !exists {
+			metadata[key] = value
+		}
+	}
+
+	return transform.Document{
+		ID:        issue.Key + "#comment-" + comment.ID,
+		Content:   renderADF(comment.BodyADF, comment.Body, opts.ADF),
+		Title:     fmt.Sprintf("Comment on %s: %s", issue.Key, issue.Fields.Summary),
+		Source:    "jira",
+		URL:       issue.Self,
+		Metadata:  metadata,
+		UpdatedAt: updatedAt,
+	}
+}
+
+// issueToDocument converts a Jira issue to a transform.Document.
+func issueToDocument(issue Issue, opts DocumentOptions) transform.Document {
+	content := issue.Fields.Summary
+	if description := renderADF(issue.Fields.DescriptionADF, issue.Fields.Description, opts.ADF); description != "" {
+		content += "\n\n" + description
+	}
+
+	if opts.IncludeEnvironment {
+		environment := renderADF(issue.Fields.EnvironmentADF, issue.Fields.Environment, opts.ADF)
+		if strings.TrimSpace(environment) != "" {
+			content += "\n\nEnvironment:\n" + environment
+		}
+	}
+
+	for _, field := range opts.ExtraContentFields {
+		text, err := adf.PlainTextWithOptions(issue.Fields.CustomFields[field.FieldID], opts.ADF)
+		if err != nil || strings.TrimSpace(text) == "" {
+			continue
+		}
+		content += fmt.Sprintf("\n\n%s:\n%s", field.Label, text)
+	}
+
+	var links []string
+	for _, link := range issue.Fields.IssueLinks {
+		if text := link.Describe(); text != "" {
+			links = append(links, "- "+text)
+		}
+	}
+	if len(links) > 0 {
+		content += "\n\nLinks:\n" + strings.Join(links, "\n")
+	}
+
+	if issue.Fields.Comments != nil && !opts.ExplodeComments {
+		authorFilter, _ := opts.commentAuthorFilter()
+		comments := filterComments(issue.Fields.Comments.Comments, authorFilter)
+		omitted := 0
+		if opts.MaxComments > 0 && len(comments) > opts.MaxComments {
+			omitted = len(comments) - opts.MaxComments
+			comments = comments[omitted:]
+		}
+		if omitted > 0 {
+			content += fmt.Sprintf("\n\n[… and %d earlier comments]", omitted)
+		}
+		for _, comment := range comments {
+			content += fmt.Sprintf("\n\n[Comment by %s]: %s",
+				comment.Author.DisplayName, renderADF(comment.BodyADF, comment.Body, opts.ADF))
+		}
+	}
+
+	var updatedAt time.Time
+	if issue.Fields.Updated != "" {
+		updatedAt, _ = parseTime(issue.Fields.Updated)
+		updatedAt = updatedAt.UTC()
+	}
+
+	metadata := map[string]string{
+		"issue_key":  issue.Key,
+		"project":    issue.Fields.Project.Key,
+		"status":     issue.Fields.Status.Name,
+		"issue_type": issue.Fields.IssueType.Name,
+	}
+
+	setTimestamp(metadata, "created", issue.Fields.Created)
+	setTimestamp(metadata, "updated", issue.Fields.Updated)
+
+	if category := issue.Fields.Project.ProjectCategory; category != nil && category.Name != "" {
+		metadata["project_category"] = category.Name
+	}
+
+	if issue.Fields.Priority != nil {
+		metadata["priority"] = issue.Fields.Priority.Name
+	}
+
+	if issue.Fields.Assignee != nil {
+		metadata["assignee"] = issue.Fields.Assignee.DisplayName
+	}
+
+	if opts.LabelsAsKeys {
+		setLabelMetadata(metadata, issue.Fields.Labels)
+	}
+
+	if opts.IncludeComponents {
+		setComponentMetadata(metadata, issue.Fields.Components)
+	}
+
+	if tt := issue.Fields.TimeTracking; tt != nil {
+		for _, d := range []struct {
+			key     string
+			text    string
+			seconds int64
+		}{
+			{"original_estimate_seconds", tt.OriginalEstimate, tt.OriginalEstimateSeconds},
+			{"remaining_estimate_seconds", tt.RemainingEstimate, tt.RemainingEstimateSeconds},
+			{"time_spent_seconds", tt.TimeSpent, tt.TimeSpentSeconds},
+		} {
+			if d.text != "" {
+				metadata[d.key] = strconv.FormatInt(d.seconds, 10)
+			}
+		}
+	}
+
+	if opts.IncludeAggregateTime {
+		setAggregateTimeMetadata(metadata, issue.Fields)
+	}
+
+	if issue.Fields.DueDate != "" {
+		if due, err := time.Parse("2006-01-02", issue.Fields.DueDate); err == nil {
+			metadata["due_date"] = due.Format("2006-01-02")
+		}
+	}
+
+	if opts.SLAField != "" {
+		for key, value := range slaMetadata(issue.Fields.CustomFields[opts.SLAField]) {
+			metadata[key] = value
+		}
+	}
+
+	if opts.RankField != "" {
+		if rank, ok := decodeCustomFieldValue(issue.Fields.CustomFields[opts.RankField]); ok {
+			metadata["rank"] = rank
+		}
+	}
+
+	if opts.StoreDescriptionFormats {
+		setDescriptionFormats(metadata, issue.Fields)
+	}
+
+	if classify, _ := opts.classifier(); classify != nil {
+		if category := classify(issue); category != "" {
+			metadata["category"] = category
+		}
+	}
+
+	for _, field := range opts.MetadataFields {
+		if value, ok := decodeCustomFieldValue(issue.Fields.CustomFields[field.FieldID]); ok {
+			metadata[field.Key] = value
+		}
+	}
+
+	if opts.IncludeAttachmentMetadata && len(issue.Fields.Attachments) > 0 {
+		summaries := make([]attachmentSummary, 0, len(issue.Fields.Attachments))
+		for _, a := range issue.Fields.Attachments {
+			summaries = append(summaries, attachmentSummary{
+				Filename: a.Filename,
+				Size:     a.Size,
+				MimeType: a.MimeType,
+				Author:   a.Author.DisplayName,
+				Created:  a.Created,
+			})
+		}
+		if data, err := json.Marshal(summaries); err == nil {
+			metadata["attachments"] = string(data)
+		}
+		metadata["attachment_count"] = strconv.Itoa(len(summaries))
+	}
+
+	for key, value := range opts.ExtraMetadata {
+		if _, exists := metadata[key]; !exists {
+			metadata[key] = value
+		}
+	}
+
+	return transform.Document{
+		ID:        issue.Key,
+		Content:   content,
+		Title:     issue.Fields.Summary,
+		Source:    "jira",
+		URL:       issue.Self,
+		Metadata:  metadata,
+		UpdatedAt: updatedAt,
+	}
+}
+
+// renderADF renders raw ADF with opts, falling back to the plain text
+// decoded with default options when there is no ADF or it fails to render.
+func renderADF(raw json.RawMessage, fallback string, opts adf.Options) string {
+	if len(raw) == 0 {
+		return fallback
+	}
+	text, err := adf.PlainTextWithOptions(raw, opts)
+	if err != nil {
+		return fallback
+	}
+	return text
+}
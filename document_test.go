@@ -0,0 +1,724 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	transform "github.com/resolute-sh/resolute-transform"
+	"github.com/resolute-sh/resolute/core"
+)
+
+// decodeIssue decodes the JSON of testIssue(key, fields) as Jira would
+// return it.
+func decodeIssue(t testing.TB, key string, fields map[string]any) Issue {
+	t.Helper()
+
+	data, err := json.Marshal(testIssue(key, fields))
+	if err != nil {
+		t.Fatalf("encode issue: %v", err)
+	}
+	var issue Issue
+	if err := json.Unmarshal(data, &issue); err != nil {
+		t.Fatalf("decode issue: %v", err)
+	}
+	return issue
+}
+
+// testComments returns the JSON of a comment field holding one comment
+// per body, by alternating authors inside and outside acme.com.
+func testComments(bodies ...string) map[string]any {
+	comments := make([]map[string]any, len(bodies))
+	for i, body := range bodies {
+		email := "dev@acme.com"
+		if i%2 == 1 {
+			email = "customer@example.com"
+		}
+		comments[i] = map[string]any{
+			"id":      fmt.Sprint(10001 + i),
+			"body":    body,
+			"author":  map[string]any{"displayName": email, "emailAddress": email},
+			"created": fmt.Sprintf("2024-03-0%dT10:00:00.000+0000", i+1),
+		}
+	}
+	return map[string]any{"total": len(bodies), "comments": comments}
+}
+
+// adfParagraphs returns an ADF document of n paragraphs.
+func adfParagraphs(n int) map[string]any {
+	content := make([]map[string]any, n)
+	for i := range content {
+		content[i] = map[string]any{
+			"type": "paragraph",
+			"content": []map[string]any{
+				{"type": "text", "text": fmt.Sprintf("Paragraph %d of the description, ", i)},
+				{"type": "text", "text": "with some emphasis.", "marks": []map[string]any{{"type": "em"}}},
+			},
+		}
+	}
+	return map[string]any{"type": "doc", "version": 1, "content": content}
+}
+
+func TestSetTimestamp(t *testing.T) {
+	tests := []struct {
+		value     string
+		wantUTC   string
+		wantLocal string
+	}{
+		{value: "2024-03-01T09:30:00.000+0530", wantUTC: "2024-03-01T04:00:00Z", wantLocal: "2024-03-01T09:30:00.000+0530"},
+		{value: "2024-03-01T20:00:00.000-0800", wantUTC: "2024-03-02T04:00:00Z", wantLocal: "2024-03-01T20:00:00.000-0800"},
+		{value: "2024-03-01T04:00:00+0000", wantUTC: "2024-03-01T04:00:00Z", wantLocal: "2024-03-01T04:00:00+0000"},
+		{value: "yesterday", wantUTC: "yesterday"},
+		{value: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			metadata := map[string]string{}
+			setTimestamp(metadata, "created", tt.value)
+			if got := metadata["created"]; got != tt.wantUTC {
+				t.Errorf("created = %q, want %q", got, tt.wantUTC)
+			}
+			if got := metadata["created_local"]; got != tt.wantLocal {
+				t.Errorf("created_local = %q, want %q", got, tt.wantLocal)
+			}
+		})
+	}
+}
+
+func TestIssueToDocument(t *testing.T) {
+	RegisterCommentAuthorFilter("test-acme", SameDomain("acme.com"))
+
+	tests := []struct {
+		name         string
+		fields       map[string]any
+		opts         DocumentOptions
+		wantContent  string
+		wantMetadata map[string]string
+		wantAbsent   []string
+	}{
+		{
+			name:        "no description",
+			fields:      map[string]any{"summary": "Login fails", "description": nil},
+			wantContent: "Login fails",
+		},
+		{
+			name: "updated in UTC",
+			fields: map[string]any{
+				"created": "2024-03-01T09:30:00.000+0530",
+				"updated": "2024-03-01T20:00:00.000-0800",
+			},
+			wantMetadata: map[string]string{
+				"created":       "2024-03-01T04:00:00Z",
+				"created_local": "2024-03-01T09:30:00.000+0530",
+				"updated":       "2024-03-02T04:00:00Z",
+				"updated_local": "2024-03-01T20:00:00.000-0800",
+			},
+		},
+		{
+			name:   "extra metadata",
+			fields: map[string]any{"status": map[string]any{"name": "Open"}},
+			opts:   DocumentOptions{ExtraMetadata: map[string]string{"tenant": "acme", "status": "ignored"}},
+			wantMetadata: map[string]string{
+				"tenant": "acme",
+				"status": "Open",
+			},
+		},
+		{
+			name:   "labels as keys",
+			fields: map[string]any{"labels": []string{"Needs Review", "backend"}},
+			opts:   DocumentOptions{LabelsAsKeys: true},
+			wantMetadata: map[string]string{
+				"labels":             "Needs Review,backend",
+				"label_needs_review": "true",
+				"label_backend":      "true",
+			},
+		},
+		{
+			name: "extra content fields",
+			fields: map[string]any{
+				"summary":           "S",
+				"customfield_10050": adfParagraphs(1),
+				"customfield_10051": "Signed off by QA",
+				"customfield_10052": "  ",
+			},
+			opts: DocumentOptions{ExtraContentFields: []ContentField{
+				{FieldID: "customfield_10051", Label: "Sign-off"},
+				{FieldID: "customfield_10050", Label: "Acceptance Criteria"},
+				{FieldID: "customfield_10052", Label: "Empty"},
+				{FieldID: "customfield_10053", Label: "Absent"},
+			}},
+			wantContent: "S\n\nSign-off:\nSigned off by QA\n\nAcceptance Criteria:\nParagraph 0 of the description, with some emphasis.",
+		},
+		{
+			name: "attachment metadata",
+			fields: map[string]any{
+				"attachment": []map[string]any{{
+					"id":       "1",
+					"filename": "trace.log",
+					"size":     2048,
+					"mimeType": "text/plain",
+					"author":   map[string]any{"displayName": "Ada"},
+					"created":  "2024-03-01T10:00:00.000+0000",
+					"content":  "https://example.atlassian.net/secure/attachment/1/trace.log",
+				}},
+			},
+			opts: DocumentOptions{IncludeAttachmentMetadata: true},
+			wantMetadata: map[string]string{
+				"attachment_count": "1",
+				"attachments":      `[{"filename":"trace.log","size":2048,"mimeType":"text/plain","author":"Ada","created":"2024-03-01T10:00:00.000+0000"}]`,
+			},
+		},
+		{
+			name: "attachments without metadata",
+			fields: map[string]any{
+				"attachment": []map[string]any{{
+					"id":       "1",
+					"filename": "trace.log",
+					"size":     2048,
+					"mimeType": "text/plain",
+					"author":   map[string]any{"displayName": "Ada"},
+					"created":  "2024-03-01T10:00:00.000+0000",
+					"content":  "https://example.atlassian.net/secure/attachment/1/trace.log",
+				}},
+			},
+			wantAbsent: []string{"attachment_count", "attachments"},
+		},
+		{
+			name: "time tracking",
+			fields: map[string]any{"timetracking": map[string]any{
+				"originalEstimate":         "1w",
+				"remainingEstimate":        "0m",
+				"timeSpent":                "1d 2h",
+				"originalEstimateSeconds":  144000,
+				"remainingEstimateSeconds": 0,
+				"timeSpentSeconds":         36000,
+			}},
+			wantMetadata: map[string]string{
+				"original_estimate_seconds":  "144000",
+				"remaining_estimate_seconds": "0",
+				"time_spent_seconds":         "36000",
+			},
+		},
+		{
+			name:       "time tracking partly set",
+			fields:     map[string]any{"timetracking": map[string]any{"timeSpent": "2h", "timeSpentSeconds": 7200}},
+			wantAbsent: []string{"original_estimate_seconds", "remaining_estimate_seconds"},
+			wantMetadata: map[string]string{
+				"time_spent_seconds": "7200",
+			},
+		},
+		{
+			name:       "no time tracking",
+			fields:     map[string]any{"summary": "S"},
+			wantAbsent: []string{"original_estimate_seconds", "remaining_estimate_seconds", "time_spent_seconds"},
+		},
+		{
+			name: "aggregate time",
+			fields: map[string]any{
+				"summary":                       "S",
+				"aggregatetimeoriginalestimate": 288000,
+				"aggregatetimeestimate":         0,
+				"aggregatetimespent":            302400,
+				"aggregateprogress":             map[string]any{"progress": 302400, "total": 302400, "percent": 100},
+			},
+			opts: DocumentOptions{IncludeAggregateTime: true},
+			wantMetadata: map[string]string{
+				"aggregate_original_estimate_seconds":  "288000",
+				"aggregate_remaining_estimate_seconds": "0",
+				"aggregate_time_spent_seconds":         "302400",
+				"aggregate_progress_percent":           "100",
+			},
+		},
+		{
+			name: "aggregate time partly set",
+			fields: map[string]any{
+				"summary":            "S",
+				"aggregatetimespent": 3600,
+				"aggregateprogress":  map[string]any{"progress": 3600, "total": 0},
+			},
+			opts:         DocumentOptions{IncludeAggregateTime: true},
+			wantMetadata: map[string]string{"aggregate_time_spent_seconds": "3600"},
+			wantAbsent:   []string{"aggregate_original_estimate_seconds", "aggregate_remaining_estimate_seconds", "aggregate_progress_percent"},
+		},
+		{
+			name:       "aggregate time not requested",
+			fields:     map[string]any{"summary": "S", "aggregatetimespent": 3600},
+			wantAbsent: []string{"aggregate_time_spent_seconds"},
+		},
+		{
+			name: "environment as ADF",
+			fields: map[string]any{
+				"summary":     "S",
+				"environment": adfParagraphs(1),
+			},
+			opts:        DocumentOptions{IncludeEnvironment: true},
+			wantContent: "S\n\nEnvironment:\nParagraph 0 of the description, with some emphasis.",
+		},
+		{
+			name:        "environment as a string",
+			fields:      map[string]any{"summary": "S", "environment": "Chrome 120 on macOS 14"},
+			opts:        DocumentOptions{IncludeEnvironment: true},
+			wantContent: "S\n\nEnvironment:\nChrome 120 on macOS 14",
+		},
+		{
+			name:        "empty environment",
+			fields:      map[string]any{"summary": "S", "environment": " "},
+			opts:        DocumentOptions{IncludeEnvironment: true},
+			wantContent: "S",
+		},
+		{
+			name:        "environment not included",
+			fields:      map[string]any{"summary": "S", "environment": "Chrome 120 on macOS 14"},
+			wantContent: "S",
+		},
+		{
+			name:         "due date",
+			fields:       map[string]any{"duedate": "2024-04-30"},
+			wantMetadata: map[string]string{"due_date": "2024-04-30"},
+		},
+		{
+			name:       "no due date",
+			fields:     map[string]any{"duedate": nil},
+			wantAbsent: []string{"due_date"},
+		},
+		{
+			name: "SLA",
+			fields: map[string]any{"customfield_10030": map[string]any{
+				"name": "Time to resolution",
+				"ongoingCycle": map[string]any{
+					"breached":      false,
+					"remainingTime": map[string]any{"millis": 5400000, "friendly": "1h 30m"},
+				},
+			}},
+			opts: DocumentOptions{SLAField: "customfield_10030"},
+			wantMetadata: map[string]string{
+				"sla_breached":     "false",
+				"sla_remaining_ms": "5400000",
+			},
+		},
+		{
+			name:       "SLA without a cycle",
+			fields:     map[string]any{"customfield_10030": map[string]any{"name": "Time to resolution", "completedCycles": []any{}}},
+			opts:       DocumentOptions{SLAField: "customfield_10030"},
+			wantAbsent: []string{"sla_breached", "sla_remaining_ms"},
+		},
+		{
+			name:       "labels without keys",
+			fields:     map[string]any{"labels": []string{"backend"}},
+			wantAbsent: []string{"labels", "label_backend"},
+		},
+		{
+			name:         "default classifier",
+			fields:       map[string]any{"issuetype": map[string]any{"name": "Defect"}},
+			opts:         DocumentOptions{ClassifierName: DefaultClassifierName},
+			wantMetadata: map[string]string{"category": "bug"},
+		},
+		{
+			name:   "classifier func",
+			fields: map[string]any{"issuetype": map[string]any{"name": "Defect"}},
+			opts: DocumentOptions{
+				Classifier:     func(Issue) string { return "custom" },
+				ClassifierName: DefaultClassifierName,
+			},
+			wantMetadata: map[string]string{"category": "custom"},
+		},
+		{
+			name:        "max comments",
+			fields:      map[string]any{"summary": "S", "comment": testComments("first", "second", "third")},
+			opts:        DocumentOptions{MaxComments: 2},
+			wantContent: "S\n\n[… and 1 earlier comments]\n\n[Comment by customer@example.com]: second\n\n[Comment by dev@acme.com]: third",
+		},
+		{
+			name:        "comment author filter",
+			fields:      map[string]any{"summary": "S", "comment": testComments("first", "second", "third")},
+			opts:        DocumentOptions{CommentAuthorFilterName: "test-acme", MaxComments: 1},
+			wantContent: "S\n\n[… and 1 earlier comments]\n\n[Comment by dev@acme.com]: third",
+		},
+		{
+			name:        "comment author filter func",
+			fields:      map[string]any{"summary": "S", "comment": testComments("first", "second", "third")},
+			opts:        DocumentOptions{CommentAuthorFilter: SameDomain("example.com")},
+			wantContent: "S\n\n[Comment by customer@example.com]: second",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := issueToDocument(decodeIssue(t, "PROJ-1", tt.fields), tt.opts)
+			if tt.wantContent != "" && doc.Content != tt.wantContent {
+				t.Errorf("content = %q, want %q", doc.Content, tt.wantContent)
+			}
+			for key, want := range tt.wantMetadata {
+				if got := doc.Metadata[key]; got != want {
+					t.Errorf("metadata[%s] = %q, want %q", key, got, want)
+				}
+			}
+			for _, key := range tt.wantAbsent {
+				if value, ok := doc.Metadata[key]; ok {
+					t.Errorf("metadata[%s] = %q, want it absent", key, value)
+				}
+			}
+		})
+	}
+}
+
+func TestIssueToDocumentDescriptionFormats(t *testing.T) {
+	description := map[string]any{
+		"type":    "doc",
+		"version": 1,
+		"content": []map[string]any{{
+			"type": "paragraph",
+			"content": []map[string]any{
+				{"type": "text", "text": "Fails on "},
+				{"type": "text", "text": "Safari", "marks": []map[string]any{{"type": "strong"}}},
+			},
+		}},
+	}
+	opts := DocumentOptions{StoreDescriptionFormats: true}
+
+	doc := issueToDocument(decodeIssue(t, "PROJ-1", map[string]any{"summary": "S", "description": description}), opts)
+	if doc.Content != "S\n\nFails on Safari" {
+		t.Errorf("content = %q, want the plain text", doc.Content)
+	}
+	if want := `{"content":[{"content":[{"text":"Fails on ","type":"text"},{"marks":[{"type":"strong"}],"text":"Safari","type":"text"}],"type":"paragraph"}],"type":"doc","version":1}`; doc.Metadata["description_adf"] != want {
+		t.Errorf("description_adf = %s, want %s", doc.Metadata["description_adf"], want)
+	}
+	if want := "<p>Fails on <strong>Safari</strong></p>"; doc.Metadata["description_html"] != want {
+		t.Errorf("description_html = %q, want %q", doc.Metadata["description_html"], want)
+	}
+
+	// Representations over the size cap are left out, the content is not.
+	doc = issueToDocument(decodeIssue(t, "PROJ-2", map[string]any{"summary": "S", "description": adfParagraphs(2000)}), opts)
+	for _, key := range []string{"description_adf", "description_html"} {
+		if _, ok := doc.Metadata[key]; ok {
+			t.Errorf("%s stored for an oversized description", key)
+		}
+	}
+	if !strings.Contains(doc.Content, "Paragraph 1999 of the description") {
+		t.Errorf("content lost the end of the oversized description")
+	}
+
+	doc = issueToDocument(decodeIssue(t, "PROJ-3", map[string]any{"summary": "S", "description": description}), DocumentOptions{})
+	if _, ok := doc.Metadata["description_adf"]; ok {
+		t.Errorf("description_adf stored without StoreDescriptionFormats")
+	}
+}
+
+func TestIssueToDocumentMaxComments(t *testing.T) {
+	bodies := make([]string, 10)
+	for i := range bodies {
+		bodies[i] = fmt.Sprintf("comment %d ", i+1) + strings.Repeat("x", 5000)
+	}
+	doc := issueToDocument(decodeIssue(t, "PROJ-1", map[string]any{
+		"summary": "S",
+		"comment": testComments(bodies...),
+	}), DocumentOptions{MaxComments: 3})
+
+	marker := strings.Index(doc.Content, "[… and 7 earlier comments]")
+	if marker < 0 {
+		t.Fatalf("content has no note about the 7 earlier comments")
+	}
+	for i, body := range bodies {
+		at := strings.Index(doc.Content, body)
+		switch {
+		case i < 7 && at >= 0:
+			t.Errorf("comment %d kept, want only the 3 most recent", i+1)
+		case i >= 7 && at < 0:
+			t.Errorf("comment %d missing or cut", i+1)
+		case i >= 7 && at < marker:
+			t.Errorf("comment %d comes before the note", i+1)
+		}
+	}
+}
+
+func TestIssuesToDocuments(t *testing.T) {
+	client := NewClient(ClientConfig{BaseURL: "http://jira.invalid"})
+	defer client.Close()
+
+	issues := []Issue{
+		decodeIssue(t, "PROJ-1", map[string]any{"comment": testComments("first", "second")}),
+		decodeIssue(t, "PROJ-2", nil),
+		decodeIssue(t, "PROJ-3", map[string]any{"description": map[string]any{"type": "doc", "content": "broken"}}),
+	}
+
+	tests := []struct {
+		name       string
+		opts       DocumentOptions
+		wantIDs    []string
+		wantFailed []string
+		wantErr    string
+	}{
+		{
+			name:    "malformed description",
+			wantErr: "convert PROJ-3",
+		},
+		{
+			name:       "continue on error",
+			opts:       DocumentOptions{ContinueOnError: true},
+			wantIDs:    []string{"PROJ-1", "PROJ-2"},
+			wantFailed: []string{"PROJ-3"},
+		},
+		{
+			name:       "explode comments",
+			opts:       DocumentOptions{ContinueOnError: true, ExplodeComments: true, ConversionWorkers: 4},
+			wantIDs:    []string{"PROJ-1", "PROJ-1#comment-10001", "PROJ-1#comment-10002", "PROJ-2"},
+			wantFailed: []string{"PROJ-3"},
+		},
+		{
+			name:    "excluded keys",
+			opts:    DocumentOptions{ExcludeKeys: []string{"PROJ-1", "PROJ-3"}},
+			wantIDs: []string{"PROJ-2"},
+		},
+		{
+			name:    "unknown classifier",
+			opts:    DocumentOptions{ClassifierName: "missing"},
+			wantErr: `unknown classifier "missing"`,
+		},
+		{
+			name:    "unknown comment author filter",
+			opts:    DocumentOptions{CommentAuthorFilterName: "missing"},
+			wantErr: `unknown comment author filter "missing"`,
+		},
+		{
+			name:    "unknown order",
+			opts:    DocumentOptions{SortDocumentsBy: "priority"},
+			wantErr: `unknown document order "priority"`,
+		},
+		{
+			name:    "conflicting aliases",
+			opts:    DocumentOptions{MetadataKeyAliases: map[string]string{"status": "state", "priority": "state"}},
+			wantErr: `metadata keys "priority" and "status" both aliased to "state"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converted, err := issuesToDocuments(context.Background(), client, issues, tt.opts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("issuesToDocuments: %v", err)
+			}
+
+			var ids, failed []string
+			for _, doc := range converted.Docs {
+				ids = append(ids, doc.ID)
+			}
+			for _, f := range converted.Failed {
+				failed = append(failed, f.Key)
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.wantIDs, ",") {
+				t.Errorf("documents = %v, want %v", ids, tt.wantIDs)
+			}
+			if strings.Join(failed, ",") != strings.Join(tt.wantFailed, ",") {
+				t.Errorf("failed = %v, want %v", failed, tt.wantFailed)
+			}
+		})
+	}
+}
+
+func TestSortDocuments(t *testing.T) {
+	docs := func() []transform.Document {
+		doc := func(id, parent, created, rank string) transform.Document {
+			return transform.Document{ID: id, Metadata: map[string]string{
+				"parent_issue": parent,
+				"created":      created,
+				"rank":         rank,
+			}}
+		}
+		return []transform.Document{
+			doc("PROJ-10", "", "2024-01-01T00:00:00Z", "0|b"),
+			doc("PROJ-2#comment-1", "PROJ-2", "2024-03-01T00:00:00Z", ""),
+			doc("PROJ-2", "", "2024-02-01T00:00:00Z", ""),
+			doc("PROJ-1", "", "2024-02-01T00:00:00Z", "0|a"),
+		}
+	}
+
+	tests := []struct {
+		by   string
+		want string
+	}{
+		{by: "", want: "PROJ-10,PROJ-2#comment-1,PROJ-2,PROJ-1"},
+		{by: SortByKey, want: "PROJ-1,PROJ-2,PROJ-2#comment-1,PROJ-10"},
+		{by: SortByCreated, want: "PROJ-10,PROJ-1,PROJ-2,PROJ-2#comment-1"},
+		{by: SortByRank, want: "PROJ-1,PROJ-10,PROJ-2,PROJ-2#comment-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.by, func(t *testing.T) {
+			sorted := docs()
+			sortDocuments(sorted, tt.by)
+			var ids []string
+			for _, doc := range sorted {
+				ids = append(ids, doc.ID)
+			}
+			if got := strings.Join(ids, ","); got != tt.want {
+				t.Errorf("order = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAliasMetadata(t *testing.T) {
+	docs := []transform.Document{{ID: "PROJ-1", Metadata: map[string]string{
+		"status":   "Open",
+		"state":    "stale",
+		"priority": "High",
+		"project":  "PROJ",
+	}}}
+
+	aliased := aliasMetadata(docs, map[string]string{"status": "state", "priority": "jira_priority"})
+
+	want := map[string]string{"state": "Open", "jira_priority": "High", "project": "PROJ"}
+	if fmt.Sprint(aliased[0].Metadata) != fmt.Sprint(want) {
+		t.Errorf("metadata = %v, want %v", aliased[0].Metadata, want)
+	}
+	if docs[0].Metadata["status"] != "Open" {
+		t.Errorf("the original metadata was modified")
+	}
+}
+
+// failingStorage is a StorageBackend whose stores fail.
+type failingStorage struct{}
+
+func (failingStorage) Store(context.Context, string, []byte) (core.DataRef, error) {
+	return core.DataRef{}, errors.New("disk full")
+}
+
+func (failingStorage) Load(context.Context, core.DataRef) ([]byte, error) {
+	return nil, errors.New("not found")
+}
+
+func (failingStorage) Delete(context.Context, core.DataRef) error { return nil }
+
+func (failingStorage) Backend() string { return "failing" }
+
+func TestStoreConversionFailure(t *testing.T) {
+	storage, err := core.GetStorage()
+	if err != nil {
+		t.Fatalf("get storage: %v", err)
+	}
+	core.SetStorage(core.NewStorage(failingStorage{}))
+	defer core.SetStorage(storage)
+
+	client := NewClient(ClientConfig{BaseURL: "http://jira.invalid"})
+	defer client.Close()
+
+	newConversion := func() *conversion {
+		return &conversion{Docs: []transform.Document{
+			{ID: "PROJ-1", Metadata: map[string]string{}},
+			{ID: "PROJ-1#comment-1", Metadata: map[string]string{"parent_issue": "PROJ-1"}},
+			{ID: "PROJ-2", Metadata: map[string]string{}},
+		}}
+	}
+
+	converted := newConversion()
+	if _, err := storeConversion(context.Background(), client, converted, DocumentOptions{}); err == nil {
+		t.Fatalf("storeConversion without ContinueOnError succeeded")
+	}
+
+	converted = newConversion()
+	ref, err := storeConversion(context.Background(), client, converted, DocumentOptions{ContinueOnError: true})
+	if err != nil {
+		t.Fatalf("storeConversion: %v", err)
+	}
+	if ref != (core.DataRef{}) || len(converted.Docs) != 0 {
+		t.Errorf("ref = %v with %d documents, want the zero ref and none", ref, len(converted.Docs))
+	}
+	if len(converted.Failed) != 2 || converted.Failed[0].Key != "PROJ-1" || converted.Failed[1].Key != "PROJ-2" {
+		t.Fatalf("failed = %v, want PROJ-1 and PROJ-2", converted.Failed)
+	}
+	if reason := converted.Failed[0].Reason; !strings.HasPrefix(reason, "store: ") || !strings.Contains(reason, "disk full") {
+		t.Errorf("reason = %q, want a store failure", reason)
+	}
+}
+
+// flakyStorage is a StorageBackend whose first failures stores fail before
+// it starts delegating to StorageBackend.
+type flakyStorage struct {
+	core.StorageBackend
+	failures int
+	stores   int
+}
+
+func (s *flakyStorage) Store(ctx context.Context, schema string, data []byte) (core.DataRef, error) {
+	s.stores++
+	if s.stores <= s.failures {
+		return core.DataRef{}, errors.New("connection reset")
+	}
+	return s.StorageBackend.Store(ctx, schema, data)
+}
+
+func TestStoreWithRetry(t *testing.T) {
+	storage, err := core.GetStorage()
+	if err != nil {
+		t.Fatalf("get storage: %v", err)
+	}
+	defer core.SetStorage(storage)
+
+	local, err := core.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("local storage: %v", err)
+	}
+	docs := []transform.Document{{ID: "PROJ-1", Content: "Summary of PROJ-1", Metadata: map[string]string{}}}
+
+	t.Run("recovers", func(t *testing.T) {
+		flaky := &flakyStorage{StorageBackend: local, failures: storeAttempts - 1}
+		core.SetStorage(core.NewStorage(flaky))
+
+		ref, err := storeWithRetry(context.Background(), docs)
+		if err != nil {
+			t.Fatalf("storeWithRetry: %v", err)
+		}
+		if flaky.stores != storeAttempts {
+			t.Errorf("stored %d times, want %d", flaky.stores, storeAttempts)
+		}
+		if stored := loadDocuments(t, ref); len(stored) != 1 || stored[0].ID != "PROJ-1" {
+			t.Errorf("stored %v, want PROJ-1", stored)
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		flaky := &flakyStorage{StorageBackend: local, failures: storeAttempts}
+		core.SetStorage(core.NewStorage(flaky))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := storeWithRetry(ctx, docs); err == nil {
+			t.Fatalf("storeWithRetry succeeded with failing storage")
+		}
+		if flaky.stores != 1 {
+			t.Errorf("stored %d times after cancellation, want once", flaky.stores)
+		}
+	})
+}
+
+func BenchmarkIssuesToDocuments(b *testing.B) {
+	client := NewClient(ClientConfig{BaseURL: "http://jira.invalid"})
+	defer client.Close()
+
+	issues := make([]Issue, 100)
+	for i := range issues {
+		issues[i] = decodeIssue(b, fmt.Sprintf("PROJ-%d", i+1), map[string]any{"description": adfParagraphs(200)})
+	}
+
+	for _, workers := range []int{1, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			opts := DocumentOptions{ConversionWorkers: workers}
+			b.ResetTimer()
+			for range b.N {
+				if _, err := issuesToDocuments(context.Background(), client, issues, opts); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/resolute-sh/resolute/core"
+)
+
+// ErrNotEditable is returned by UpdateIssueValidated when some requested
+// fields cannot be edited on the issue.
+var ErrNotEditable = errors.New("jira: fields not editable")
+
+// FieldMeta describes a field that can be edited on an issue.
+type FieldMeta struct {
+	Key           string            `json:"key"`
+	Name          string            `json:"name"`
+	Required      bool              `json:"required"`
+	Schema        FieldSchema       `json:"schema"`
+	Operations    []string          `json:"operations"`
+	AllowedValues []json.RawMessage `json:"allowedValues,omitempty"`
+}
+
+// FieldSchema describes the type of a field's value.
+type FieldSchema struct {
+	Type     string `json:"type"`
+	Items    string `json:"items,omitempty"`
+	System   string `json:"system,omitempty"`
+	Custom   string `json:"custom,omitempty"`
+	CustomID int    `json:"customId,omitempty"`
+}
+
+// GetEditMeta returns the fields that can be edited on an issue, keyed by
+// field ID. The set depends on the caller's permissions and the issue's
+// screen and workflow, so it can differ between issues of a project.
+func (c *Client) GetEditMeta(ctx context.Context, issueKey string) (map[string]FieldMeta, error) {
+	var result struct {
+		Fields map[string]FieldMeta `json:"fields"`
+	}
+	path := "/rest/api/3/issue/" + url.PathEscape(issueKey) + "/editmeta"
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Fields, nil
+}
+
+// UpdateIssueValidated is UpdateIssue checking fields against the issue's
+// edit metadata first. When any field is not editable it returns an error
+// wrapping ErrNotEditable that names those fields, without attempting the
+// edit.
+func (c *Client) UpdateIssueValidated(ctx context.Context, issueKey string, fields map[string]any) error {
+	meta, err := c.GetEditMeta(ctx, issueKey)
+	if err != nil {
+		return fmt.Errorf("get edit meta: %w", err)
+	}
+
+	var notEditable []string
+	for field := range fields {
+		if _, ok := meta[field]; !ok {
+			notEditable = append(notEditable, field)
+		}
+	}
+	if len(notEditable) > 0 {
+		sort.Strings(notEditable)
+		return fmt.Errorf("%w on %s: %s", ErrNotEditable, issueKey, strings.Join(notEditable, ", "))
+	}
+
+	return c.UpdateIssue(ctx, issueKey, fields)
+}
+
+// FetchEditMetaInput is the input for FetchEditMetaActivity.
+type FetchEditMetaInput struct {
+	BaseURL  string
+	Email    string
+	APIToken string
+	IssueKey string
+
+	ClientOptions
+}
+
+// FetchEditMetaOutput is the output of FetchEditMetaActivity.
+type FetchEditMetaOutput struct {
+	Fields map[string]FieldMeta
+}
+
+// FetchEditMetaActivity fetches the edit metadata of an issue.
+func FetchEditMetaActivity(ctx context.Context, input FetchEditMetaInput) (FetchEditMetaOutput, error) {
+	client, err := input.ClientOptions.newClient(ClientConfig{
+		BaseURL:  input.BaseURL,
+		Email:    input.Email,
+		APIToken: input.APIToken,
+	})
+	if err != nil {
+		return FetchEditMetaOutput{}, err
+	}
+	defer client.Close()
+
+	fields, err := client.GetEditMeta(ctx, input.IssueKey)
+	if err != nil {
+		return FetchEditMetaOutput{}, fmt.Errorf("get edit meta: %w", err)
+	}
+
+	return FetchEditMetaOutput{Fields: fields}, nil
+}
+
+// FetchEditMeta creates a node for fetching an issue's edit metadata.
+func FetchEditMeta(input FetchEditMetaInput) *core.Node[FetchEditMetaInput, FetchEditMetaOutput] {
+	return core.NewNode("jira.FetchEditMeta", FetchEditMetaActivity, input)
+}
@@ -0,0 +1,88 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestUpdateIssueValidated(t *testing.T) {
+	editMeta := map[string]any{"fields": map[string]any{
+		"summary": map[string]any{
+			"key":        "summary",
+			"name":       "Summary",
+			"required":   true,
+			"schema":     map[string]any{"type": "string", "system": "summary"},
+			"operations": []string{"set"},
+		},
+		"priority": map[string]any{
+			"key":           "priority",
+			"name":          "Priority",
+			"schema":        map[string]any{"type": "priority", "system": "priority"},
+			"operations":    []string{"set"},
+			"allowedValues": []map[string]any{{"id": "1", "name": "High"}, {"id": "2", "name": "Low"}},
+		},
+	}}
+
+	tests := []struct {
+		name    string
+		fields  map[string]any
+		wantErr string
+	}{
+		{name: "editable", fields: map[string]any{"summary": "New title", "priority": map[string]any{"name": "High"}}},
+		{
+			name:    "not editable",
+			fields:  map[string]any{"summary": "New title", "resolution": map[string]any{"name": "Done"}, "assignee": nil},
+			wantErr: "on PROJ-1: assignee, resolution",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var edited bool
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodGet && r.URL.Path == "/rest/api/3/issue/PROJ-1/editmeta":
+					writeJSON(w, editMeta)
+				case r.Method == http.MethodPut && r.URL.Path == "/rest/api/3/issue/PROJ-1":
+					edited = true
+					w.WriteHeader(http.StatusNoContent)
+				default:
+					http.NotFound(w, r)
+				}
+			}), ClientConfig{})
+
+			err := client.UpdateIssueValidated(context.Background(), "PROJ-1", tt.fields)
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrNotEditable) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want ErrNotEditable naming %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Errorf("UpdateIssueValidated: %v", err)
+			}
+			if edited != (tt.wantErr == "") {
+				t.Errorf("edited = %v, want %v", edited, tt.wantErr == "")
+			}
+		})
+	}
+
+	t.Run("decode", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, editMeta)
+		}), ClientConfig{})
+
+		meta, err := client.GetEditMeta(context.Background(), "PROJ-1")
+		if err != nil {
+			t.Fatalf("GetEditMeta: %v", err)
+		}
+		priority := meta["priority"]
+		if priority.Name != "Priority" || priority.Schema.System != "priority" || len(priority.AllowedValues) != 2 {
+			t.Errorf("priority = %+v, want its schema and 2 allowed values", priority)
+		}
+		if !meta["summary"].Required || meta["summary"].Operations[0] != "set" {
+			t.Errorf("summary = %+v, want a required field with the set operation", meta["summary"])
+		}
+	})
+}
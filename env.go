@@ -0,0 +1,67 @@
+package jira
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// DefaultEnvPrefix is the prefix of the environment variables read by
+// ClientConfigFromEnv.
+const DefaultEnvPrefix = "JIRA"
+
+// ClientConfigFromEnv builds a ClientConfig from JIRA_BASE_URL, JIRA_EMAIL
+// and JIRA_API_TOKEN.
+func ClientConfigFromEnv() (ClientConfig, error) {
+	return ClientConfigFromEnvPrefix(DefaultEnvPrefix)
+}
+
+// ClientConfigFromEnvPrefix builds a ClientConfig from <prefix>_BASE_URL,
+// <prefix>_EMAIL and <prefix>_API_TOKEN. All three are required; the error
+// names every variable that is missing.
+func ClientConfigFromEnvPrefix(prefix string) (ClientConfig, error) {
+	prefix = strings.TrimSuffix(prefix, "_")
+
+	names := []string{prefix + "_BASE_URL", prefix + "_EMAIL", prefix + "_API_TOKEN"}
+	values := make([]string, len(names))
+
+	var missing []string
+	for i, name := range names {
+		values[i] = strings.TrimSpace(os.Getenv(name))
+		if values[i] == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return ClientConfig{}, fmt.Errorf("missing required environment variables: %s", strings.Join(missing, ", "))
+	}
+
+	baseURL := strings.TrimRight(values[0], "/")
+	u, err := url.Parse(baseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ClientConfig{}, fmt.Errorf("%s must be an absolute http(s) URL, got %q", names[0], values[0])
+	}
+
+	return ClientConfig{
+		BaseURL:  baseURL,
+		Email:    values[1],
+		APIToken: values[2],
+	}, nil
+}
+
+// FetchAllIssuesConfigFromEnv builds a FetchAllIssuesConfig for project
+// using the credentials from ClientConfigFromEnv.
+func FetchAllIssuesConfigFromEnv(project string) (FetchAllIssuesConfig, error) {
+	cfg, err := ClientConfigFromEnv()
+	if err != nil {
+		return FetchAllIssuesConfig{}, err
+	}
+
+	return FetchAllIssuesConfig{
+		BaseURL:  cfg.BaseURL,
+		Email:    cfg.Email,
+		APIToken: cfg.APIToken,
+		Project:  project,
+	}, nil
+}
@@ -0,0 +1,50 @@
+package jira
+
+import "testing"
+
+func TestClientConfigFromEnvPrefix(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    ClientConfig
+		wantErr string
+	}{
+		{
+			name: "complete",
+			env:  map[string]string{"ACME_BASE_URL": " https://acme.atlassian.net/ ", "ACME_EMAIL": "dev@acme.com", "ACME_API_TOKEN": "secret"},
+			want: ClientConfig{BaseURL: "https://acme.atlassian.net", Email: "dev@acme.com", APIToken: "secret"},
+		},
+		{
+			name:    "missing",
+			env:     map[string]string{"ACME_EMAIL": "dev@acme.com", "ACME_API_TOKEN": "  "},
+			wantErr: "missing required environment variables: ACME_BASE_URL, ACME_API_TOKEN",
+		},
+		{
+			name:    "relative URL",
+			env:     map[string]string{"ACME_BASE_URL": "acme.atlassian.net", "ACME_EMAIL": "dev@acme.com", "ACME_API_TOKEN": "secret"},
+			wantErr: `ACME_BASE_URL must be an absolute http(s) URL, got "acme.atlassian.net"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{"ACME_BASE_URL", "ACME_EMAIL", "ACME_API_TOKEN"} {
+				t.Setenv(name, tt.env[name])
+			}
+
+			got, err := ClientConfigFromEnvPrefix("ACME_")
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ClientConfigFromEnvPrefix: %v", err)
+			}
+			if got.BaseURL != tt.want.BaseURL || got.Email != tt.want.Email || got.APIToken != tt.want.APIToken {
+				t.Errorf("config = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	transform "github.com/resolute-sh/resolute-transform"
+	"github.com/resolute-sh/resolute/core"
+)
+
+// FetchEpicBundleInput is the input for FetchEpicBundleActivity.
+type FetchEpicBundleInput struct {
+	BaseURL  string
+	Email    string
+	APIToken string
+	Epic     string // issue key
+
+	// Aggregate stores the epic and its children as one document: the
+	// epic's content followed by a "Child issues:" list with each child's
+	// key, status and summary. Otherwise the epic and each child get their
+	// own documents, the children's carrying epic_key metadata.
+	Aggregate bool
+
+	DocumentOptions
+	ClientOptions
+}
+
+// FetchEpicBundleOutput is the output of FetchEpicBundleActivity.
+type FetchEpicBundleOutput struct {
+	Ref        core.DataRef
+	Count      int
+	ChildCount int
+
+	// StatusCounts counts the children by status name.
+	StatusCounts map[string]int
+
+	// Skipped lists the keys of issues dropped for thin content.
+	Skipped []string
+
+	// FailedIssues lists the issues left out because they failed to
+	// convert, with ContinueOnError.
+	FailedIssues []FailedIssue
+}
+
+// FetchEpicBundleActivity fetches an epic issue with all its child issues
+// and stores them together, so a single ref answers questions about the
+// whole epic.
+func FetchEpicBundleActivity(ctx context.Context, input FetchEpicBundleInput) (FetchEpicBundleOutput, error) {
+	client, err := input.ClientOptions.newClient(ClientConfig{
+		BaseURL:  input.BaseURL,
+		Email:    input.Email,
+		APIToken: input.APIToken,
+	})
+	if err != nil {
+		return FetchEpicBundleOutput{}, err
+	}
+	defer client.Close()
+
+	epic, err := client.GetIssue(ctx, input.Epic)
+	if err != nil {
+		return FetchEpicBundleOutput{}, fmt.Errorf("get epic: %w", err)
+	}
+
+	children, err := client.GetEpicIssues(ctx, epic.Key)
+	if err != nil {
+		return FetchEpicBundleOutput{}, fmt.Errorf("get epic issues: %w", err)
+	}
+
+	statusCounts := make(map[string]int)
+	for _, child := range children {
+		statusCounts[child.Fields.Status.Name]++
+	}
+
+	var converted conversion
+	if input.Aggregate {
+		converted, err = issuesToDocuments(ctx, client, []Issue{*epic}, input.DocumentOptions)
+		if err != nil {
+			return FetchEpicBundleOutput{}, err
+		}
+		for i, doc := range converted.Docs {
+			if doc.ID == epic.Key {
+				converted.Docs[i] = aggregateEpicDocument(doc, children, statusCounts)
+			}
+		}
+	} else {
+		converted, err = issuesToDocuments(ctx, client, append([]Issue{*epic}, children...), input.DocumentOptions)
+		if err != nil {
+			return FetchEpicBundleOutput{}, err
+		}
+		for _, doc := range converted.Docs {
+			if documentIssueKey(doc) != epic.Key {
+				doc.Metadata["epic_key"] = epic.Key
+			}
+		}
+	}
+
+	ref, err := storeConversion(ctx, client, &converted, input.DocumentOptions)
+	if err != nil {
+		return FetchEpicBundleOutput{}, fmt.Errorf("store documents: %w", err)
+	}
+	docs := converted.Docs
+
+	return FetchEpicBundleOutput{
+		Ref:          ref,
+		Count:        len(docs),
+		ChildCount:   len(children),
+		StatusCounts: statusCounts,
+		Skipped:      converted.Skipped,
+		FailedIssues: converted.Failed,
+	}, nil
+}
+
+// aggregateEpicDocument appends the children of an epic to the epic's
+// document, with child_count and child_statuses (a JSON object of counts
+// by status) metadata.
+func aggregateEpicDocument(doc transform.Document, children []Issue, statusCounts map[string]int) transform.Document {
+	if len(children) > 0 {
+		lines := make([]string, 0, len(children))
+		for _, child := range children {
+			lines = append(lines, fmt.Sprintf("- %s [%s]: %s",
+				child.Key, child.Fields.Status.Name, child.Fields.Summary))
+		}
+		doc.Content += "\n\nChild issues:\n" + strings.Join(lines, "\n")
+	}
+
+	doc.Metadata["child_count"] = strconv.Itoa(len(children))
+	if statuses, err := json.Marshal(statusCounts); err == nil {
+		doc.Metadata["child_statuses"] = string(statuses)
+	}
+	return doc
+}
+
+// FetchEpicBundle creates a node for fetching an epic with its children.
+func FetchEpicBundle(input FetchEpicBundleInput) *core.Node[FetchEpicBundleInput, FetchEpicBundleOutput] {
+	return core.NewNode("jira.FetchEpicBundle", FetchEpicBundleActivity, input).
+		WithTimeout(30 * time.Minute)
+}
@@ -0,0 +1,94 @@
+package jira
+
+import (
+	"net/http"
+	"testing"
+)
+
+// epicBundleJira serves epic PROJ-100 with three children and returns its
+// base URL.
+func epicBundleJira(t *testing.T) string {
+	t.Helper()
+
+	child := func(key, status, summary string) map[string]any {
+		return testIssue(key, map[string]any{"summary": summary, "status": map[string]any{"name": status}})
+	}
+	fake := &fakeJira{routes: map[string]http.HandlerFunc{
+		"/rest/api/3/issue/PROJ-100": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, testIssue("PROJ-100", map[string]any{
+				"summary":   "Checkout rework",
+				"issuetype": map[string]any{"name": "Epic"},
+			}))
+		},
+		"/rest/agile/1.0/epic/PROJ-100/issue": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, map[string]any{"total": 3, "issues": []map[string]any{
+				child("PROJ-101", "Done", "New payment API"),
+				child("PROJ-102", "In Progress", "Migrate the cart"),
+				child("PROJ-103", "Done", "Drop the old flow"),
+			}})
+		},
+	}}
+	return fake.start(t)
+}
+
+func TestFetchEpicBundleAggregate(t *testing.T) {
+	out, err := runActivity(t, FetchEpicBundleActivity, FetchEpicBundleInput{
+		BaseURL:   epicBundleJira(t),
+		Epic:      "PROJ-100",
+		Aggregate: true,
+	})
+	if err != nil {
+		t.Fatalf("FetchEpicBundleActivity: %v", err)
+	}
+
+	if out.Count != 1 || out.ChildCount != 3 {
+		t.Errorf("count = %d with %d children, want 1 with 3", out.Count, out.ChildCount)
+	}
+	if out.StatusCounts["Done"] != 2 || out.StatusCounts["In Progress"] != 1 {
+		t.Errorf("status counts = %v, want Done 2, In Progress 1", out.StatusCounts)
+	}
+
+	docs := loadDocuments(t, out.Ref)
+	if len(docs) != 1 {
+		t.Fatalf("stored %d documents, want 1", len(docs))
+	}
+	wantContent := "Checkout rework\n\nChild issues:\n" +
+		"- PROJ-101 [Done]: New payment API\n" +
+		"- PROJ-102 [In Progress]: Migrate the cart\n" +
+		"- PROJ-103 [Done]: Drop the old flow"
+	if docs[0].Content != wantContent {
+		t.Errorf("content = %q, want %q", docs[0].Content, wantContent)
+	}
+	if docs[0].Metadata["child_count"] != "3" || docs[0].Metadata["child_statuses"] != `{"Done":2,"In Progress":1}` {
+		t.Errorf("metadata = %v, want child_count 3 and child_statuses", docs[0].Metadata)
+	}
+}
+
+func TestFetchEpicBundleSeparate(t *testing.T) {
+	out, err := runActivity(t, FetchEpicBundleActivity, FetchEpicBundleInput{
+		BaseURL: epicBundleJira(t),
+		Epic:    "PROJ-100",
+	})
+	if err != nil {
+		t.Fatalf("FetchEpicBundleActivity: %v", err)
+	}
+
+	if out.Count != 4 || out.ChildCount != 3 {
+		t.Errorf("count = %d with %d children, want 4 with 3", out.Count, out.ChildCount)
+	}
+
+	docs := loadDocuments(t, out.Ref)
+	if len(docs) != 4 {
+		t.Fatalf("stored %d documents, want 4", len(docs))
+	}
+	for _, doc := range docs {
+		epicKey, ok := doc.Metadata["epic_key"]
+		if doc.ID == "PROJ-100" {
+			if ok {
+				t.Errorf("epic document has epic_key %q", epicKey)
+			}
+		} else if epicKey != "PROJ-100" {
+			t.Errorf("%s epic_key = %q, want PROJ-100", doc.ID, epicKey)
+		}
+	}
+}
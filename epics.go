@@ -0,0 +1,124 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	transform "github.com/resolute-sh/resolute-transform"
+	"github.com/resolute-sh/resolute/core"
+)
+
+// Epic represents an agile epic.
+type Epic struct {
+	ID      int    `json:"id"`
+	Key     string `json:"key"`
+	Self    string `json:"self"`
+	Name    string `json:"name"`
+	Summary string `json:"summary"`
+	Done    bool   `json:"done"`
+}
+
+// GetEpic returns an epic by ID or issue key. A missing epic yields an
+// error matching ErrNotFound.
+func (c *Client) GetEpic(ctx context.Context, epicIDOrKey string) (*Epic, error) {
+	var epic Epic
+	if err := c.do(ctx, http.MethodGet, "/rest/agile/1.0/epic/"+url.PathEscape(epicIDOrKey), nil, nil, &epic); err != nil {
+		return nil, err
+	}
+
+	return &epic, nil
+}
+
+// GetEpicIssues returns all issues in an epic, sub-tasks excluded.
+func (c *Client) GetEpicIssues(ctx context.Context, epicIDOrKey string) ([]Issue, error) {
+	path := "/rest/agile/1.0/epic/" + url.PathEscape(epicIDOrKey) + "/issue"
+
+	var issues []Issue
+	startAt := 0
+	for {
+		query := url.Values{}
+		query.Set("startAt", strconv.Itoa(startAt))
+		query.Set("maxResults", "100")
+
+		var page SearchResult
+		if err := c.do(ctx, http.MethodGet, path, query, nil, &page); err != nil {
+			return nil, err
+		}
+
+		issues = append(issues, page.Issues...)
+		startAt += len(page.Issues)
+		if len(page.Issues) == 0 || startAt >= page.Total {
+			return issues, nil
+		}
+	}
+}
+
+// FetchEpicInput is the input for FetchEpicActivity.
+type FetchEpicInput struct {
+	BaseURL  string
+	Email    string
+	APIToken string
+	Epic     string // ID or issue key
+
+	ClientOptions
+}
+
+// FetchEpicOutput is the output of FetchEpicActivity.
+type FetchEpicOutput struct {
+	Ref  core.DataRef
+	Epic Epic
+}
+
+// FetchEpicActivity fetches an epic and stores it as a document.
+func FetchEpicActivity(ctx context.Context, input FetchEpicInput) (FetchEpicOutput, error) {
+	client, err := input.ClientOptions.newClient(ClientConfig{
+		BaseURL:  input.BaseURL,
+		Email:    input.Email,
+		APIToken: input.APIToken,
+	})
+	if err != nil {
+		return FetchEpicOutput{}, err
+	}
+	defer client.Close()
+
+	epic, err := client.GetEpic(ctx, input.Epic)
+	if err != nil {
+		return FetchEpicOutput{}, fmt.Errorf("get epic: %w", err)
+	}
+
+	ref, err := storeWithRetry(ctx, []transform.Document{epicToDocument(*epic)})
+	if err != nil {
+		return FetchEpicOutput{}, fmt.Errorf("store documents: %w", err)
+	}
+
+	return FetchEpicOutput{Ref: ref, Epic: *epic}, nil
+}
+
+// epicToDocument converts an epic to a transform.Document.
+func epicToDocument(epic Epic) transform.Document {
+	content := epic.Name
+	if epic.Summary != "" && epic.Summary != epic.Name {
+		content += "\n\n" + epic.Summary
+	}
+
+	return transform.Document{
+		ID:      "epic-" + epic.Key,
+		Content: content,
+		Title:   epic.Name,
+		Source:  "jira",
+		URL:     epic.Self,
+		Metadata: map[string]string{
+			"epic_id":  strconv.Itoa(epic.ID),
+			"epic_key": epic.Key,
+			"done":     strconv.FormatBool(epic.Done),
+		},
+	}
+}
+
+// FetchEpic creates a node for fetching an epic.
+func FetchEpic(input FetchEpicInput) *core.Node[FetchEpicInput, FetchEpicOutput] {
+	return core.NewNode("jira.FetchEpic", FetchEpicActivity, input)
+}
@@ -0,0 +1,30 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestGetEpic(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/agile/1.0/epic/PROJ-10" {
+			w.WriteHeader(http.StatusNotFound)
+			writeJSON(w, map[string]any{"errorMessages": []string{"Issue does not exist or you do not have permission to see it."}})
+			return
+		}
+		writeJSON(w, map[string]any{"id": 10010, "key": "PROJ-10", "name": "Checkout", "summary": "Rework the checkout", "done": true})
+	}), ClientConfig{})
+
+	epic, err := client.GetEpic(context.Background(), "PROJ-10")
+	if err != nil {
+		t.Fatalf("GetEpic: %v", err)
+	}
+	if epic.ID != 10010 || epic.Name != "Checkout" || epic.Summary != "Rework the checkout" || !epic.Done {
+		t.Errorf("epic = %+v, want the done Checkout epic", epic)
+	}
+	if _, err := client.GetEpic(context.Background(), "PROJ-404"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("error for a missing epic = %v, want ErrNotFound", err)
+	}
+}
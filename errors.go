@@ -0,0 +1,116 @@
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// Sentinel errors matched by APIError via errors.Is.
+var (
+	ErrUnauthorized = errors.New("jira: unauthorized")
+	ErrForbidden    = errors.New("jira: forbidden")
+	ErrNotFound     = errors.New("jira: not found")
+)
+
+// ErrClientClosed is returned by requests made after Client.Close.
+var ErrClientClosed = errors.New("jira: client closed")
+
+// ErrUnboundedJQL is returned when a search has no restricting clause,
+// either caught client-side or rejected by Jira.
+var ErrUnboundedJQL = errors.New("jira: unbounded JQL; add a project or filter clause to restrict the search")
+
+// ErrDeepPagination is returned when Jira rejects an offset-paginated
+// search because startAt went past its result window (about 10,000 issues).
+// Cursor-search mode, ClientConfig.CursorSearch or ClientOptions.CursorSearch
+// on activity inputs, pages with tokens and has no such limit.
+var ErrDeepPagination = errors.New("jira: search offset beyond Jira's pagination limit; narrow the JQL (e.g. shard by project or date) or enable CursorSearch in the client options")
+
+// ErrConflict is returned when a conditional update finds the issue was
+// modified since the caller read it.
+var ErrConflict = errors.New("jira: conflict")
+
+// APIError is returned when the Jira API responds with a non-2xx status.
+type APIError struct {
+	StatusCode    int
+	Body          string
+	ErrorMessages []string
+	Errors        map[string]string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("jira API error: status=%d body=%s", e.StatusCode, e.Body)
+}
+
+// Is reports whether the error matches one of the sentinel errors.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnboundedJQL:
+		return e.StatusCode == http.StatusBadRequest && e.hasMessage("unbounded jql")
+	}
+	return false
+}
+
+// hasMessage reports whether any error message contains substr,
+// case-insensitively.
+func (e *APIError) hasMessage(substr string) bool {
+	for _, msg := range e.ErrorMessages {
+		if strings.Contains(strings.ToLower(msg), substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// maxBodySnippet bounds the Body kept for a non-JSON error response.
+const maxBodySnippet = 200
+
+// newAPIError builds an APIError from a response status and body, decoding
+// Jira's standard error envelope when present. A non-JSON body, such as
+// the HTML login page served for a revoked API token, is only kept as a
+// short snippet; the status alone still matches the sentinel errors.
+func newAPIError(statusCode int, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Body:       strings.TrimSpace(string(body)),
+	}
+
+	if !json.Valid(body) {
+		apiErr.Body = bodySnippet(apiErr.Body)
+		return apiErr
+	}
+
+	var envelope struct {
+		ErrorMessages []string          `json:"errorMessages"`
+		Errors        map[string]string `json:"errors"`
+	}
+	if json.Unmarshal(body, &envelope) == nil {
+		apiErr.ErrorMessages = envelope.ErrorMessages
+		apiErr.Errors = envelope.Errors
+	}
+
+	return apiErr
+}
+
+// bodySnippet collapses the whitespace of body and truncates it to
+// maxBodySnippet bytes, at a rune boundary.
+func bodySnippet(body string) string {
+	body = strings.Join(strings.Fields(body), " ")
+	if len(body) <= maxBodySnippet {
+		return body
+	}
+	cut := maxBodySnippet
+	for cut > 0 && !utf8.RuneStart(body[cut]) {
+		cut--
+	}
+	return body[:cut] + "…"
+}
@@ -0,0 +1,47 @@
+package jira
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestAPIErrorIs(t *testing.T) {
+	tests := []struct {
+		status int
+		body   string
+		target error
+		want   bool
+	}{
+		{status: 401, target: ErrUnauthorized, want: true},
+		{status: 403, target: ErrForbidden, want: true},
+		{status: 404, target: ErrNotFound, want: true},
+		{status: 404, target: ErrForbidden},
+		{status: 400, body: `{"errorMessages":["Unbounded JQL queries are not allowed here."]}`, target: ErrUnboundedJQL, want: true},
+		{status: 400, body: `{"errorMessages":["Field 'x' does not exist."]}`, target: ErrUnboundedJQL},
+		{status: 401, body: "<html><body>Log in</body></html>", target: ErrUnauthorized, want: true},
+	}
+
+	for _, tt := range tests {
+		if got := errors.Is(newAPIError(tt.status, []byte(tt.body)), tt.target); got != tt.want {
+			t.Errorf("status %d %s is %v = %v, want %v", tt.status, tt.body, tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestNewAPIError(t *testing.T) {
+	apiErr := newAPIError(400, []byte(`{"errorMessages":["bad"],"errors":{"summary":"required"}}`))
+	if len(apiErr.ErrorMessages) != 1 || apiErr.Errors["summary"] != "required" {
+		t.Errorf("envelope = %v, %v", apiErr.ErrorMessages, apiErr.Errors)
+	}
+
+	page := "<html>\n  <head><title>Log in</title></head>\n" + strings.Repeat("<p>ünïcode</p>\n", 50) + "</html>"
+	apiErr = newAPIError(401, []byte(page))
+	if !strings.HasPrefix(apiErr.Body, "<html> <head><title>Log in</title></head> <p>") {
+		t.Errorf("body = %q, want collapsed whitespace", apiErr.Body)
+	}
+	if !strings.HasSuffix(apiErr.Body, "…") || len(apiErr.Body) > maxBodySnippet+len("…") || !utf8.ValidString(apiErr.Body) {
+		t.Errorf("body = %q, want a valid snippet of at most %d bytes", apiErr.Body, maxBodySnippet)
+	}
+}
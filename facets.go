@@ -0,0 +1,84 @@
+package jira
+
+import (
+	"context"
+	"time"
+
+	"github.com/resolute-sh/resolute/core"
+)
+
+// FacetInput is the input for FacetActivity.
+type FacetInput struct {
+	BaseURL  string
+	Email    string
+	APIToken string
+	JQL      string
+
+	MaxResults int // per page, default 100
+
+	ClientOptions
+}
+
+// FacetOutput is the output of FacetActivity. Each map counts the matching
+// issues by a field's display value; unassigned issues are counted under
+// "Unassigned".
+type FacetOutput struct {
+	Total            int
+	ByStatus         map[string]int
+	ByStatusCategory map[string]int
+	ByAssignee       map[string]int
+	ByIssueType      map[string]int
+}
+
+// FacetActivity counts the issues matching a JQL query by status, status
+// category, assignee and issue type. Only those fields are fetched and no
+// documents are stored, so it is far cheaper than fetching the issues and
+// tallying them.
+func FacetActivity(ctx context.Context, input FacetInput) (FacetOutput, error) {
+	client, err := input.ClientOptions.newClient(ClientConfig{
+		BaseURL:  input.BaseURL,
+		Email:    input.Email,
+		APIToken: input.APIToken,
+	})
+	if err != nil {
+		return FacetOutput{}, err
+	}
+	defer client.Close()
+
+	output := FacetOutput{
+		ByStatus:         map[string]int{},
+		ByStatusCategory: map[string]int{},
+		ByAssignee:       map[string]int{},
+		ByIssueType:      map[string]int{},
+	}
+
+	_, _, err = paginateSearch(ctx, client, input.JQL, paginateOptions{
+		PageSize: input.MaxResults,
+		Fields:   []string{"status", "assignee", "issuetype"},
+	}, func(issues []Issue) error {
+		for _, issue := range issues {
+			output.Total++
+			output.ByStatus[issue.Fields.Status.Name]++
+			output.ByStatusCategory[issue.Fields.Status.StatusCategory.Name]++
+			output.ByIssueType[issue.Fields.IssueType.Name]++
+
+			assignee := "Unassigned"
+			if issue.Fields.Assignee != nil {
+				assignee = issue.Fields.Assignee.DisplayName
+			}
+			output.ByAssignee[assignee]++
+		}
+		return nil
+	})
+	if err != nil {
+		return FacetOutput{}, err
+	}
+
+	return output, nil
+}
+
+// Facet creates a node for counting issues by status, assignee and type.
+func Facet(input FacetInput) *core.Node[FacetInput, FacetOutput] {
+	return core.NewNode("jira.Facet", FacetActivity, input).
+		WithTimeout(30 * time.Minute)
+}
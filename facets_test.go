@@ -0,0 +1,55 @@
+package jira
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFacetActivity(t *testing.T) {
+	issue := func(key, status, category, issueType, assignee string) map[string]any {
+		fields := map[string]any{
+			"status":    map[string]any{"name": status, "statusCategory": map[string]any{"name": category}},
+			"issuetype": map[string]any{"name": issueType},
+		}
+		if assignee != "" {
+			fields["assignee"] = map[string]any{"displayName": assignee}
+		}
+		return testIssue(key, fields)
+	}
+	fake := &fakeJira{issues: []map[string]any{
+		issue("PROJ-1", "Open", "To Do", "Bug", "Ada"),
+		issue("PROJ-2", "In Review", "In Progress", "Bug", "Ada"),
+		issue("PROJ-3", "Open", "To Do", "Story", ""),
+		issue("PROJ-4", "Done", "Done", "Bug", "Grace"),
+		issue("PROJ-5", "In Progress", "In Progress", "Task", ""),
+	}}
+
+	out, err := runActivity(t, FacetActivity, FacetInput{
+		BaseURL:    fake.start(t),
+		JQL:        "project = PROJ",
+		MaxResults: 2,
+	})
+	if err != nil {
+		t.Fatalf("FacetActivity: %v", err)
+	}
+
+	if pages := len(fake.recorded()); pages != 3 {
+		t.Errorf("fetched %d pages, want 3", pages)
+	}
+	if out.Total != 5 {
+		t.Errorf("total = %d, want 5", out.Total)
+	}
+	for name, tt := range map[string]struct {
+		got  map[string]int
+		want string
+	}{
+		"status":          {out.ByStatus, "map[Done:1 In Progress:1 In Review:1 Open:2]"},
+		"status category": {out.ByStatusCategory, "map[Done:1 In Progress:2 To Do:2]"},
+		"assignee":        {out.ByAssignee, "map[Ada:2 Grace:1 Unassigned:2]"},
+		"issue type":      {out.ByIssueType, "map[Bug:3 Story:1 Task:1]"},
+	} {
+		if got := fmt.Sprint(tt.got); got != tt.want {
+			t.Errorf("by %s = %s, want %s", name, got, tt.want)
+		}
+	}
+}
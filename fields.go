@@ -0,0 +1,85 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/resolute-sh/resolute/core"
+)
+
+// FieldDefinition describes a system or custom field of the instance.
+type FieldDefinition struct {
+	ID          string      `json:"id"` // e.g. "customfield_10050"
+	Key         string      `json:"key"`
+	Name        string      `json:"name"`
+	Custom      bool        `json:"custom"`
+	Orderable   bool        `json:"orderable"`
+	Navigable   bool        `json:"navigable"`
+	Searchable  bool        `json:"searchable"`
+	ClauseNames []string    `json:"clauseNames"` // names usable in JQL
+	Schema      FieldSchema `json:"schema"`
+}
+
+// ListFields returns every field visible to the caller, for discovering the
+// customfield_* IDs behind field names.
+func (c *Client) ListFields(ctx context.Context) ([]FieldDefinition, error) {
+	var fields []FieldDefinition
+	if err := c.do(ctx, http.MethodGet, "/rest/api/3/field", nil, nil, &fields); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+// ListFieldsInput is the input for ListFieldsActivity.
+type ListFieldsInput struct {
+	BaseURL  string
+	Email    string
+	APIToken string
+
+	// CustomOnly leaves out system fields.
+	CustomOnly bool
+
+	ClientOptions
+}
+
+// ListFieldsOutput is the output of ListFieldsActivity.
+type ListFieldsOutput struct {
+	Fields []FieldDefinition
+}
+
+// ListFieldsActivity lists the fields of the instance.
+func ListFieldsActivity(ctx context.Context, input ListFieldsInput) (ListFieldsOutput, error) {
+	client, err := input.ClientOptions.newClient(ClientConfig{
+		BaseURL:  input.BaseURL,
+		Email:    input.Email,
+		APIToken: input.APIToken,
+	})
+	if err != nil {
+		return ListFieldsOutput{}, err
+	}
+	defer client.Close()
+
+	fields, err := client.ListFields(ctx)
+	if err != nil {
+		return ListFieldsOutput{}, fmt.Errorf("list fields: %w", err)
+	}
+
+	if input.CustomOnly {
+		custom := fields[:0]
+		for _, field := range fields {
+			if field.Custom {
+				custom = append(custom, field)
+			}
+		}
+		fields = custom
+	}
+
+	return ListFieldsOutput{Fields: fields}, nil
+}
+
+// ListFields creates a node for listing the fields of the instance.
+func ListFields(input ListFieldsInput) *core.Node[ListFieldsInput, ListFieldsOutput] {
+	return core.NewNode("jira.ListFields", ListFieldsActivity, input)
+}
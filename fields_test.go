@@ -0,0 +1,48 @@
+package jira
+
+import (
+	"io"
+	"net/http"
+	"testing"
+)
+
+const fieldsResponse = `[
+	{"id": "summary", "key": "summary", "name": "Summary", "custom": false, "orderable": true, "navigable": true, "searchable": true, "clauseNames": ["summary"], "schema": {"type": "string", "system": "summary"}},
+	{"id": "customfield_10016", "key": "customfield_10016", "name": "Story Points", "custom": true, "orderable": true, "navigable": true, "searchable": true, "clauseNames": ["cf[10016]", "Story Points"], "schema": {"type": "number", "custom": "com.atlassian.jira.plugin.system.customfieldtypes:float", "customId": 10016}}
+]`
+
+func TestListFieldsActivity(t *testing.T) {
+	fake := &fakeJira{routes: map[string]http.HandlerFunc{
+		"/rest/api/3/field": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			io.WriteString(w, fieldsResponse)
+		},
+	}}
+	baseURL := fake.start(t)
+
+	out, err := runActivity(t, ListFieldsActivity, ListFieldsInput{BaseURL: baseURL})
+	if err != nil {
+		t.Fatalf("ListFieldsActivity: %v", err)
+	}
+	if len(out.Fields) != 2 {
+		t.Fatalf("got %d fields, want 2", len(out.Fields))
+	}
+	if summary := out.Fields[0]; summary.Custom || summary.Schema.System != "summary" {
+		t.Errorf("summary = %+v, want a system field", summary)
+	}
+	points := out.Fields[1]
+	if !points.Custom || points.Name != "Story Points" || points.Schema.Type != "number" || points.Schema.CustomID != 10016 {
+		t.Errorf("story points = %+v, want the custom number field 10016", points)
+	}
+	if len(points.ClauseNames) != 2 || points.ClauseNames[0] != "cf[10016]" {
+		t.Errorf("clause names = %v, want cf[10016] first", points.ClauseNames)
+	}
+
+	out, err = runActivity(t, ListFieldsActivity, ListFieldsInput{BaseURL: baseURL, CustomOnly: true})
+	if err != nil {
+		t.Fatalf("ListFieldsActivity with CustomOnly: %v", err)
+	}
+	if len(out.Fields) != 1 || out.Fields[0].ID != "customfield_10016" {
+		t.Errorf("custom fields = %+v, want only customfield_10016", out.Fields)
+	}
+}
@@ -0,0 +1,213 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	transform "github.com/resolute-sh/resolute-transform"
+	"github.com/resolute-sh/resolute/core"
+)
+
+// Sections of an issue context bundle.
+const (
+	SectionComments    = "comments"
+	SectionChangelog   = "changelog"
+	SectionWorklogs    = "worklogs"
+	SectionLinks       = "links"
+	SectionAttachments = "attachments"
+)
+
+// FetchIssueContextInput is the input for FetchIssueContextActivity.
+// Every section is included unless its Skip flag is set.
+type FetchIssueContextInput struct {
+	BaseURL  string
+	Email    string
+	APIToken string
+	IssueKey string
+
+	SkipComments    bool
+	SkipChangelog   bool
+	SkipWorklogs    bool
+	SkipLinks       bool
+	SkipAttachments bool
+
+	DocumentOptions
+	ClientOptions
+}
+
+// FetchIssueContextOutput is the output of FetchIssueContextActivity.
+type FetchIssueContextOutput struct {
+	Document transform.Document
+
+	// Comments holds one document per comment with ExplodeComments.
+	Comments []transform.Document
+
+	// Included lists the sections present in the document.
+	Included []string
+
+	// Skipped lists requested sections left out because Jira denied access
+	// or did not find them, or did not return them, e.g. links on an
+	// instance with issue linking turned off.
+	Skipped []string
+
+	// Dropped is set when DocumentOptions left the issue out, for thin
+	// content or ExcludeKeys; Document is then empty.
+	Dropped bool
+}
+
+// FetchIssueContextActivity fetches an issue together with its comments,
+// changelog, worklogs, links and attachment metadata, and merges them into
+// a single document. The per-issue sub-resources are fetched concurrently.
+func FetchIssueContextActivity(ctx context.Context, input FetchIssueContextInput) (FetchIssueContextOutput, error) {
+	client, err := input.ClientOptions.newClient(ClientConfig{
+		BaseURL:  input.BaseURL,
+		Email:    input.Email,
+		APIToken: input.APIToken,
+	})
+	if err != nil {
+		return FetchIssueContextOutput{}, err
+	}
+	defer client.Close()
+
+	issue, err := client.GetIssue(ctx, input.IssueKey)
+	if err != nil {
+		return FetchIssueContextOutput{}, fmt.Errorf("get issue: %w", err)
+	}
+
+	var (
+		comments  []Comment
+		changelog []ChangelogEntry
+		worklogs  []Worklog
+		errs      = make(map[string]error)
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+	)
+
+	fetch := func(section string, skip bool, fn func() error) {
+		if skip {
+			return
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := fn()
+			mu.Lock()
+			errs[section] = err
+			mu.Unlock()
+		}()
+	}
+
+	fetch(SectionComments, input.SkipComments, func() (err error) {
+		comments, err = client.GetComments(ctx, issue.Key)
+		return err
+	})
+	fetch(SectionChangelog, input.SkipChangelog, func() (err error) {
+		changelog, err = client.GetChangelog(ctx, issue.Key)
+		return err
+	})
+	fetch(SectionWorklogs, input.SkipWorklogs, func() (err error) {
+		worklogs, err = client.GetWorklogs(ctx, issue.Key)
+		return err
+	})
+	wg.Wait()
+
+	var out FetchIssueContextOutput
+	for _, section := range []string{SectionComments, SectionChangelog, SectionWorklogs} {
+		err, requested := errs[section]
+		switch {
+		case !requested:
+		case errors.Is(err, ErrForbidden), errors.Is(err, ErrNotFound):
+			out.Skipped = append(out.Skipped, section)
+		case err != nil:
+			return FetchIssueContextOutput{}, fmt.Errorf("get %s: %w", section, err)
+		default:
+			out.Included = append(out.Included, section)
+		}
+	}
+
+	switch {
+	case input.SkipComments:
+		issue.Fields.Comments = nil
+	case errs[SectionComments] == nil:
+		issue.Fields.Comments = &Comments{Total: len(comments), Comments: comments}
+	}
+	// Links and attachments come with the issue, which leaves their
+	// fields out when the feature is turned off.
+	switch {
+	case input.SkipLinks:
+		issue.Fields.IssueLinks = nil
+	case issue.Fields.IssueLinks == nil:
+		out.Skipped = append(out.Skipped, SectionLinks)
+	default:
+		out.Included = append(out.Included, SectionLinks)
+	}
+	switch {
+	case input.SkipAttachments:
+	case issue.Fields.Attachments == nil:
+		out.Skipped = append(out.Skipped, SectionAttachments)
+	default:
+		out.Included = append(out.Included, SectionAttachments)
+	}
+
+	opts := input.DocumentOptions
+	opts.IncludeAttachmentMetadata = !input.SkipAttachments
+	// Comments were fetched above.
+	opts.IncludeAllComments = false
+
+	converted, err := issuesToDocuments(ctx, client, []Issue{*issue}, opts)
+	if err != nil {
+		return FetchIssueContextOutput{}, err
+	}
+	if len(converted.Failed) > 0 {
+		return FetchIssueContextOutput{}, fmt.Errorf("convert %s: %s", issue.Key, converted.Failed[0].Reason)
+	}
+	if len(converted.Docs) == 0 {
+		out.Dropped = true
+		return out, nil
+	}
+
+	doc := converted.Docs[0]
+	if errs[SectionChangelog] == nil && len(changelog) > 0 {
+		doc.Content += "\n\nChangelog:\n" + renderChangelog(changelog)
+	}
+	if errs[SectionWorklogs] == nil && len(worklogs) > 0 {
+		doc.Content += "\n\nWorklogs:\n" + renderWorklogs(worklogs)
+	}
+	out.Document = doc
+	out.Comments = converted.Docs[1:]
+
+	return out, nil
+}
+
+// renderChangelog renders changelog entries as one line per field change.
+func renderChangelog(entries []ChangelogEntry) string {
+	var lines []string
+	for _, entry := range entries {
+		for _, item := range entry.Items {
+			lines = append(lines, fmt.Sprintf("- %s %s changed %s: %q -> %q",
+				entry.Created, entry.Author.DisplayName, item.Field, item.FromString, item.ToString))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderWorklogs renders worklogs as one line per entry.
+func renderWorklogs(worklogs []Worklog) string {
+	lines := make([]string, 0, len(worklogs))
+	for _, w := range worklogs {
+		line := fmt.Sprintf("- %s %s logged %s", w.Started, w.Author.DisplayName, w.TimeSpent)
+		if w.Comment != "" {
+			line += ": " + w.Comment
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// FetchIssueContext creates a node for fetching an issue's full context bundle.
+func FetchIssueContext(input FetchIssueContextInput) *core.Node[FetchIssueContextInput, FetchIssueContextOutput] {
+	return core.NewNode("jira.FetchIssueContext", FetchIssueContextActivity, input)
+}
@@ -0,0 +1,134 @@
+package jira
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestFetchIssueContext(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        FetchIssueContextInput
+		fields       map[string]any
+		status       map[string]int // per section, default 200
+		wantIncluded string
+		wantSkipped  string
+		wantContent  []string
+		wantComments int
+		wantDropped  bool
+		wantErr      string
+	}{
+		{
+			name:         "everything",
+			fields:       map[string]any{"issuelinks": []any{}, "attachment": []any{}},
+			wantIncluded: "comments,changelog,worklogs,links,attachments",
+			wantContent: []string{
+				"[Comment by Dev]: fetched comment",
+				"Changelog:\n- 2024-03-02 Dev changed status: \"To Do\" -> \"Done\"",
+				"Worklogs:\n- 2024-03-03 Dev logged 2h: debugging",
+			},
+		},
+		{
+			name:         "denied and missing sections",
+			fields:       map[string]any{"attachment": []any{}},
+			status:       map[string]int{SectionWorklogs: http.StatusForbidden, SectionChangelog: http.StatusNotFound},
+			wantIncluded: "comments,attachments",
+			wantSkipped:  "changelog,worklogs,links",
+		},
+		{
+			name:         "skipped by input",
+			input:        FetchIssueContextInput{SkipComments: true, SkipChangelog: true, SkipWorklogs: true, SkipLinks: true, SkipAttachments: true},
+			fields:       map[string]any{"comment": map[string]any{"total": 1, "comments": []any{map[string]any{"id": "9", "body": "embedded comment"}}}},
+			wantIncluded: "",
+		},
+		{
+			name:         "exploded comments",
+			input:        FetchIssueContextInput{SkipLinks: true, SkipAttachments: true, DocumentOptions: DocumentOptions{ExplodeComments: true}},
+			wantIncluded: "comments,changelog,worklogs",
+			wantComments: 1,
+		},
+		{
+			name:        "thin issue dropped",
+			input:       FetchIssueContextInput{SkipLinks: true, SkipAttachments: true, DocumentOptions: DocumentOptions{RequireContentBytes: 10000, ThinContentMode: ThinContentSkip}},
+			wantDropped: true,
+		},
+		{
+			name:    "failed section",
+			status:  map[string]int{SectionChangelog: http.StatusInternalServerError},
+			wantErr: "get changelog",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			respond := func(section string, v any) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					if status := tt.status[section]; status != 0 {
+						http.Error(w, `{"errorMessages":["no"]}`, status)
+						return
+					}
+					writeJSON(w, v)
+				}
+			}
+			dev := map[string]any{"displayName": "Dev"}
+			fake := &fakeJira{routes: map[string]http.HandlerFunc{
+				"/rest/api/3/issue/PROJ-1": respond("issue", testIssue("PROJ-1", tt.fields)),
+				"/rest/api/3/issue/PROJ-1/comment": respond(SectionComments, map[string]any{"total": 1, "comments": []any{
+					map[string]any{"id": "1", "body": "fetched comment", "author": dev},
+				}}),
+				"/rest/api/3/issue/PROJ-1/changelog": respond(SectionChangelog, map[string]any{"total": 1, "isLast": true, "values": []any{
+					map[string]any{"id": "1", "created": "2024-03-02", "author": dev, "items": []any{
+						map[string]any{"field": "status", "fromString": "To Do", "toString": "Done"},
+					}},
+				}}),
+				"/rest/api/3/issue/PROJ-1/worklog": respond(SectionWorklogs, map[string]any{"total": 1, "worklogs": []any{
+					map[string]any{"id": "1", "started": "2024-03-03", "author": dev, "timeSpent": "2h", "comment": "debugging"},
+				}}),
+			}}
+
+			input := tt.input
+			input.BaseURL = fake.start(t)
+			input.IssueKey = "PROJ-1"
+			out, err := runActivity(t, FetchIssueContextActivity, input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FetchIssueContextActivity: %v", err)
+			}
+
+			if out.Dropped != tt.wantDropped {
+				t.Errorf("dropped = %v, want %v", out.Dropped, tt.wantDropped)
+			}
+			if tt.wantDropped {
+				return
+			}
+			if got := strings.Join(out.Included, ","); got != tt.wantIncluded {
+				t.Errorf("included = %s, want %s", got, tt.wantIncluded)
+			}
+			if got := strings.Join(out.Skipped, ","); got != tt.wantSkipped {
+				t.Errorf("skipped = %s, want %s", got, tt.wantSkipped)
+			}
+			for _, want := range tt.wantContent {
+				if !strings.Contains(out.Document.Content, want) {
+					t.Errorf("content = %q, want it to contain %q", out.Document.Content, want)
+				}
+			}
+			if input.SkipComments && strings.Contains(out.Document.Content, "comment") {
+				t.Errorf("content = %q, want no comments", out.Document.Content)
+			}
+			if len(out.Comments) != tt.wantComments {
+				t.Errorf("comment documents = %d, want %d", len(out.Comments), tt.wantComments)
+			}
+			if tt.wantComments > 0 {
+				if id := out.Comments[0].ID; id != "PROJ-1#comment-1" {
+					t.Errorf("comment document ID = %s", id)
+				}
+			}
+		})
+	}
+}
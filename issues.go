@@ -3,11 +3,13 @@ package jira
 import (
 	"context"
 	"fmt"
-	"strconv"
+	"slices"
+	"strings"
 	"time"
 
-	"github.com/resolute-sh/resolute/core"
+	"github.com/resolute-sh/resolute-jira/jql"
 	transform "github.com/resolute-sh/resolute-transform"
+	"github.com/resolute-sh/resolute/core"
 )
 
 // FetchIssuesInput is the input for FetchIssuesActivity.
@@ -17,7 +19,41 @@ type FetchIssuesInput struct {
 	APIToken   string
 	Project    string
 	Since      *time.Time
+	Until      *time.Time
 	MaxResults int
+
+	// UpdatedBy restricts results to issues updated by this user (email or
+	// account ID) within the Since/Until window.
+	UpdatedBy string
+
+	// WorklogAuthor restricts results to issues with work logged by this
+	// user (email or account ID).
+	WorklogAuthor string
+
+	// Components restricts results to issues in any of these components.
+	// Without a Project, the search spans all accessible projects.
+	Components []string
+
+	// StatusCategories restricts results to issues whose status is in any
+	// of these categories: "To Do", "In Progress" or "Done". Unlike status
+	// names they are the same across workflows.
+	StatusCategories []string
+
+	// SinceRelative is a lower bound relative to now, such as "-24h" or
+	// "-7d" (units m, h, d, w), evaluated by Jira in its own timezone, so
+	// no client clock or timezone is involved. Unlike an absolute Since it
+	// is not reproducible: a retried run covers a shifted window. It cannot
+	// be combined with Since.
+	SinceRelative string
+
+	// PreviewIssues is a testing aid: when set, the activity converts
+	// these raw issue JSON objects, as returned by the Jira API, instead of
+	// searching Jira, so no credentials are needed. Search filters are
+	// ignored, and document options that need more data from Jira fail.
+	PreviewIssues [][]byte
+
+	DocumentOptions
+	ClientOptions
 }
 
 // FetchIssuesOutput is the output of FetchIssuesActivity.
@@ -25,20 +61,48 @@ type FetchIssuesOutput struct {
 	Ref   core.DataRef
 	Count int
 	Total int
+
+	// IssueCount and CommentCount split Count into issue documents and,
+	// with ExplodeComments, comment documents.
+	IssueCount   int
+	CommentCount int
+
+	// Skipped lists the keys of issues dropped for thin content.
+	Skipped []string
+
+	// FailedIssues lists the issues left out because they failed to
+	// convert, with ContinueOnError.
+	FailedIssues []FailedIssue
 }
 
 // FetchIssuesActivity fetches issues from a Jira project and stores them.
 func FetchIssuesActivity(ctx context.Context, input FetchIssuesInput) (FetchIssuesOutput, error) {
-	client := NewClient(ClientConfig{
+	if len(input.PreviewIssues) > 0 {
+		return previewIssues(ctx, input)
+	}
+
+	client, err := input.ClientOptions.newClient(ClientConfig{
 		BaseURL:  input.BaseURL,
 		Email:    input.Email,
 		APIToken: input.APIToken,
 	})
-
-	jql := fmt.Sprintf("project = %s ORDER BY updated DESC", input.Project)
-	if input.Since != nil {
-		jql = fmt.Sprintf("project = %s AND updated >= '%s' ORDER BY updated DESC",
-			input.Project, input.Since.Format("2006-01-02 15:04"))
+	if err != nil {
+		return FetchIssuesOutput{}, err
+	}
+	defer client.Close()
+
+	query, err := projectQuery{
+		Project:          input.Project,
+		Since:            input.Since,
+		Until:            input.Until,
+		UpdatedBy:        input.UpdatedBy,
+		WorklogAuthor:    input.WorklogAuthor,
+		Components:       input.Components,
+		StatusCategories: input.StatusCategories,
+		SinceRelative:    input.SinceRelative,
+	}.build(ctx, client)
+	if err != nil {
+		return FetchIssuesOutput{}, err
 	}
 
 	maxResults := input.MaxResults
@@ -46,26 +110,31 @@ func FetchIssuesActivity(ctx context.Context, input FetchIssuesInput) (FetchIssu
 		maxResults = 100
 	}
 
-	result, err := client.SearchJQL(ctx, jql, maxResults)
+	result, err := client.SearchJQL(ctx, query, maxResults)
 	if err != nil {
 		return FetchIssuesOutput{}, fmt.Errorf("search jql: %w", err)
 	}
 
-	docs := make([]transform.Document, 0, len(result.Issues))
-	for _, issue := range result.Issues {
-		doc := issueToDocument(issue)
-		docs = append(docs, doc)
+	converted, err := issuesToDocuments(ctx, client, result.Issues, input.DocumentOptions)
+	if err != nil {
+		return FetchIssuesOutput{}, err
 	}
 
-	ref, err := transform.StoreDocuments(ctx, docs)
+	ref, err := storeConversion(ctx, client, &converted, input.DocumentOptions)
 	if err != nil {
 		return FetchIssuesOutput{}, fmt.Errorf("store documents: %w", err)
 	}
+	docs := converted.Docs
 
+	issueCount, commentCount := countDocuments(docs)
 	return FetchIssuesOutput{
-		Ref:   ref,
-		Count: len(docs),
-		Total: result.Total,
+		Ref:          ref,
+		Count:        len(docs),
+		Total:        result.Total,
+		IssueCount:   issueCount,
+		CommentCount: commentCount,
+		Skipped:      converted.Skipped,
+		FailedIssues: converted.Failed,
 	}, nil
 }
 
@@ -75,30 +144,70 @@ type FetchIssueInput struct {
 	Email    string
 	APIToken string
 	IssueKey string
+
+	// UpdateHistory records the read in the user's issue history; see
+	// ClientConfig.UpdateHistory.
+	UpdateHistory bool
+
+	// JustCreated retries the read briefly while Jira reports the issue as
+	// not found, for issues created moments ago; see GetCreatedIssue.
+	JustCreated bool
+
+	DocumentOptions
+	ClientOptions
 }
 
 // FetchIssueOutput is the output of FetchIssueActivity.
 type FetchIssueOutput struct {
 	Document transform.Document
 	Found    bool
+
+	// Comments holds the comment documents when ExplodeComments is set.
+	Comments []transform.Document
+
+	// Skipped is set when DocumentOptions left the issue out, for thin
+	// content or ExcludeKeys; Document is empty then.
+	Skipped bool
 }
 
 // FetchIssueActivity fetches a single issue by key.
 func FetchIssueActivity(ctx context.Context, input FetchIssueInput) (FetchIssueOutput, error) {
-	client := NewClient(ClientConfig{
-		BaseURL:  input.BaseURL,
-		Email:    input.Email,
-		APIToken: input.APIToken,
+	client, err := input.ClientOptions.newClient(ClientConfig{
+		BaseURL:       input.BaseURL,
+		Email:         input.Email,
+		APIToken:      input.APIToken,
+		UpdateHistory: input.UpdateHistory,
 	})
+	if err != nil {
+		return FetchIssueOutput{}, err
+	}
+	defer client.Close()
 
-	issue, err := client.GetIssue(ctx, input.IssueKey)
+	getIssue := client.GetIssue
+	if input.JustCreated {
+		getIssue = client.GetCreatedIssue
+	}
+	issue, err := getIssue(ctx, input.IssueKey)
 	if err != nil {
 		return FetchIssueOutput{}, fmt.Errorf("get issue: %w", err)
 	}
 
+	converted, err := issuesToDocuments(ctx, client, []Issue{*issue}, input.DocumentOptions)
+	if err != nil {
+		return FetchIssueOutput{}, err
+	}
+	if len(converted.Failed) > 0 {
+		return FetchIssueOutput{}, fmt.Errorf("convert %s: %s", issue.Key, converted.Failed[0].Reason)
+	}
+	if len(converted.Docs) == 0 {
+		return FetchIssueOutput{Found: true, Skipped: true}, nil
+	}
+	docs := converted.Docs
+
 	return FetchIssueOutput{
-		Document: issueToDocument(*issue),
+		Document: docs[0],
 		Found:    true,
+		Comments: docs[1:],
 	}, nil
 }
 
@@ -109,6 +218,9 @@ type SearchJQLInput struct {
 	APIToken   string
 	JQL        string
 	MaxResults int
+
+	DocumentOptions
+	ClientOptions
 }
 
 // SearchJQLOutput is the output of SearchJQLActivity.
@@ -116,15 +228,31 @@ type SearchJQLOutput struct {
 	Ref   core.DataRef
 	Count int
 	Total int
+
+	// IssueCount and CommentCount split Count into issue documents and,
+	// with ExplodeComments, comment documents.
+	IssueCount   int
+	CommentCount int
+
+	// Skipped lists the keys of issues dropped for thin content.
+	Skipped []string
+
+	// FailedIssues lists the issues left out because they failed to
+	// convert, with ContinueOnError.
+	FailedIssues []FailedIssue
 }
 
 // SearchJQLActivity searches for issues using JQL and stores them.
 func SearchJQLActivity(ctx context.Context, input SearchJQLInput) (SearchJQLOutput, error) {
-	client := NewClient(ClientConfig{
+	client, err := input.ClientOptions.newClient(ClientConfig{
 		BaseURL:  input.BaseURL,
 		Email:    input.Email,
 		APIToken: input.APIToken,
 	})
+	if err != nil {
+		return SearchJQLOutput{}, err
+	}
+	defer client.Close()
 
 	maxResults := input.MaxResults
 	if maxResults <= 0 {
@@ -136,214 +264,160 @@ func SearchJQLActivity(ctx context.Context, input SearchJQLInput) (SearchJQLOutp
 		return SearchJQLOutput{}, fmt.Errorf("search jql: %w", err)
 	}
 
-	docs := make([]transform.Document, 0, len(result.Issues))
-	for _, issue := range result.Issues {
-		doc := issueToDocument(issue)
-		docs = append(docs, doc)
+	converted, err := issuesToDocuments(ctx, client, result.Issues, input.DocumentOptions)
+	if err != nil {
+		return SearchJQLOutput{}, err
 	}
 
-	ref, err := transform.StoreDocuments(ctx, docs)
+	ref, err := storeConversion(ctx, client, &converted, input.DocumentOptions)
 	if err != nil {
 		return SearchJQLOutput{}, fmt.Errorf("store documents: %w", err)
 	}
+	docs := converted.Docs
 
+	issueCount, commentCount := countDocuments(docs)
 	return SearchJQLOutput{
-		Ref:   ref,
-		Count: len(docs),
-		Total: result.Total,
+		Ref:          ref,
+		Count:        len(docs),
+		Total:        result.Total,
+		IssueCount:   issueCount,
+		CommentCount: commentCount,
+		Skipped:      converted.Skipped,
+		FailedIssues: converted.Failed,
 	}, nil
 }
 
-// issueToDocument converts a Jira issue to a transform.Document.
-func issueToDocument(issue Issue) transform.Document {
-	content := issue.Fields.Summary
-	if issue.Fields.Description != "" {
-		content += "\n\n" + issue.Fields.Description
-	}
+// projectQuery holds the filters used to compose project-scoped JQL.
+type projectQuery struct {
+	Project          string
+	Projects         []string
+	Since            *time.Time
+	Until            *time.Time
+	UpdatedBy        string
+	WorklogAuthor    string
+	Components       []string
+	StatusCategories []string
+	SinceRelative    string
+	OrderBy          string // default "updated DESC"
+}
 
-	if issue.Fields.Comments != nil {
-		for _, comment := range issue.Fields.Comments.Comments {
-			content += fmt.Sprintf("\n\n[Comment by %s]: %s",
-				comment.Author.DisplayName, comment.Body)
-		}
-	}
+// statusCategories are the status category names accepted in JQL.
+var statusCategories = []string{"To Do", "In Progress", "Done"}
 
-	var updatedAt time.Time
-	if issue.Fields.Updated != "" {
-		updatedAt, _ = time.Parse("2006-01-02T15:04:05.000-0700", issue.Fields.Updated)
+// normalizeStatusCategories returns categories with their canonical
+// capitalization, failing on names that are not a status category.
+func normalizeStatusCategories(categories []string) ([]string, error) {
+	normalized := make([]string, 0, len(categories))
+	for _, category := range categories {
+		i := slices.IndexFunc(statusCategories, func(known string) bool {
+			return strings.EqualFold(known, strings.TrimSpace(category))
+		})
+		if i < 0 {
+			return nil, fmt.Errorf("unknown status category %q, want one of %s",
+				category, strings.Join(statusCategories, ", "))
+		}
+		normalized = append(normalized, statusCategories[i])
 	}
+	return normalized, nil
+}
 
-	metadata := map[string]string{
-		"issue_key":   issue.Key,
-		"project":     issue.Fields.Project.Key,
-		"status":      issue.Fields.Status.Name,
-		"issue_type":  issue.Fields.IssueType.Name,
+// build composes the JQL, resolving user emails to account IDs via client.
+// At least one scoping clause (projects or components) is required so a
+// missing project never silently searches the whole instance.
+func (q projectQuery) build(ctx context.Context, client *Client) (string, error) {
+	if q.Project == "" && len(q.Projects) == 0 && len(q.Components) == 0 {
+		return "", fmt.Errorf("project or components must be set")
 	}
 
-	if issue.Fields.Priority != nil {
-		metadata["priority"] = issue.Fields.Priority.Name
+	projects := q.Projects
+	if q.Project != "" {
+		projects = append([]string{q.Project}, projects...)
 	}
 
-	if issue.Fields.Assignee != nil {
-		metadata["assignee"] = issue.Fields.Assignee.DisplayName
+	var query jql.Query
+	if len(projects) == 1 {
+		query.And(jql.Equals("project", projects[0]))
+	} else {
+		query.And(jql.In("project", projects))
 	}
+	query.And(jql.In("component", q.Components))
 
-	return transform.Document{
-		ID:        issue.Key,
-		Content:   content,
-		Title:     issue.Fields.Summary,
-		Source:    "jira",
-		URL:       issue.Self,
-		Metadata:  metadata,
-		UpdatedAt: updatedAt,
+	categories, err := normalizeStatusCategories(q.StatusCategories)
+	if err != nil {
+		return "", err
 	}
-}
-
-// FetchIssues creates a node for fetching Jira issues.
-func FetchIssues(input FetchIssuesInput) *core.Node[FetchIssuesInput, FetchIssuesOutput] {
-	return core.NewNode("jira.FetchIssues", FetchIssuesActivity, input)
-}
-
-// FetchIssue creates a node for fetching a single Jira issue.
-func FetchIssue(input FetchIssueInput) *core.Node[FetchIssueInput, FetchIssueOutput] {
-	return core.NewNode("jira.FetchIssue", FetchIssueActivity, input)
-}
+	query.And(jql.In("statusCategory", categories))
 
-// SearchJQL creates a node for searching Jira with JQL.
-func SearchJQL(input SearchJQLInput) *core.Node[SearchJQLInput, SearchJQLOutput] {
-	return core.NewNode("jira.SearchJQL", SearchJQLActivity, input)
-}
-
-// FetchAllIssuesConfig contains configuration for fetching all issues.
-type FetchAllIssuesConfig struct {
-	BaseURL    string
-	Email      string
-	APIToken   string
-	Project    string
-	Since      *time.Time
-	MaxResults int // per page, default 100
-}
-
-// FetchAllIssuesOutput is the output of FetchAllIssuesActivity.
-type FetchAllIssuesOutput struct {
-	Ref        core.DataRef
-	Count      int
-	PageCount  int
-	FinalCursor string
-}
-
-// FetchAllIssues creates a node that fetches ALL issues using pagination.
-// Unlike FetchIssues which fetches a single page, this fetches all pages.
-func FetchAllIssues(config FetchAllIssuesConfig) *core.Node[core.PaginateWithInputParams[FetchAllIssuesConfig], core.PaginateWithInputOutput[Issue, FetchAllIssuesConfig]] {
-	fetcher := func(ctx context.Context, cfg FetchAllIssuesConfig, cursor string) (core.PageResult[Issue], error) {
-		client := NewClient(ClientConfig{
-			BaseURL:  cfg.BaseURL,
-			Email:    cfg.Email,
-			APIToken: cfg.APIToken,
-		})
-
-		jql := fmt.Sprintf("project = %s ORDER BY updated DESC", cfg.Project)
-		if cfg.Since != nil {
-			jql = fmt.Sprintf("project = %s AND updated >= '%s' ORDER BY updated DESC",
-				cfg.Project, cfg.Since.Format("2006-01-02 15:04"))
+	// Jira reads JQL dates in the time zone of the user's profile.
+	since, until := q.Since, q.Until
+	if since != nil || until != nil {
+		loc, err := client.UserLocation(ctx)
+		if err != nil {
+			return "", fmt.Errorf("get user time zone: %w", err)
 		}
+		since, until = inLocation(since, loc), inLocation(until, loc)
+	}
 
-		startAt := 0
-		if cursor != "" {
-			var err error
-			startAt, err = strconv.Atoi(cursor)
-			if err != nil {
-				return core.PageResult[Issue]{}, fmt.Errorf("parse cursor: %w", err)
-			}
+	if since != nil {
+		query.And("updated >= " + jql.Date(*since))
+	}
+	if q.SinceRelative != "" {
+		if since != nil {
+			return "", fmt.Errorf("since and sinceRelative are mutually exclusive")
 		}
-
-		maxResults := cfg.MaxResults
-		if maxResults <= 0 {
-			maxResults = 100
+		if !jql.IsRelativeDuration(q.SinceRelative) {
+			return "", fmt.Errorf("invalid relative date %q, want e.g. -24h or -7d", q.SinceRelative)
 		}
+		query.And("updated >= " + jql.Quote(q.SinceRelative))
+	}
+	if until != nil {
+		query.And("updated <= " + jql.Date(*until))
+	}
 
-		result, err := client.SearchJQLWithParams(ctx, SearchJQLParams{
-			JQL:        jql,
-			StartAt:    startAt,
-			MaxResults: maxResults,
-		})
+	if q.UpdatedBy != "" {
+		accountID, err := client.FindAccountID(ctx, q.UpdatedBy)
 		if err != nil {
-			return core.PageResult[Issue]{}, fmt.Errorf("search jql: %w", err)
+			return "", fmt.Errorf("resolve updatedBy user: %w", err)
 		}
+		query.And(jql.UpdatedBy(accountID, since, until))
+	}
 
-		nextStartAt := startAt + len(result.Issues)
-		hasMore := nextStartAt < result.Total
-		nextCursor := ""
-		if hasMore {
-			nextCursor = strconv.Itoa(nextStartAt)
+	if q.WorklogAuthor != "" {
+		accountID, err := client.FindAccountID(ctx, q.WorklogAuthor)
+		if err != nil {
+			return "", fmt.Errorf("resolve worklogAuthor user: %w", err)
 		}
+		query.And(jql.WorklogAuthor(accountID))
+	}
 
-		return core.PageResult[Issue]{
-			Items:      result.Issues,
-			NextCursor: nextCursor,
-			HasMore:    hasMore,
-		}, nil
+	orderBy := q.OrderBy
+	if orderBy == "" {
+		orderBy = "updated DESC"
 	}
 
-	return core.PaginateWithConfig[Issue, FetchAllIssuesConfig]("jira.FetchAllIssues", fetcher).
-		WithTimeout(30 * time.Minute) // Long timeout for large datasets
+	return query.OrderBy(orderBy).String(), nil
 }
 
-// SearchAllJQL creates a node that fetches ALL issues matching a JQL query using pagination.
-type SearchAllJQLConfig struct {
-	BaseURL    string
-	Email      string
-	APIToken   string
-	JQL        string
-	MaxResults int // per page, default 100
+// inLocation returns t converted to loc, or nil when t is nil.
+func inLocation(t *time.Time, loc *time.Location) *time.Time {
+	if t == nil {
+		return nil
+	}
+	converted := t.In(loc)
+	return &converted
 }
 
-// SearchAllJQL creates a node that searches with JQL and fetches all results.
-func SearchAllJQL(config SearchAllJQLConfig) *core.Node[core.PaginateWithInputParams[SearchAllJQLConfig], core.PaginateWithInputOutput[Issue, SearchAllJQLConfig]] {
-	fetcher := func(ctx context.Context, cfg SearchAllJQLConfig, cursor string) (core.PageResult[Issue], error) {
-		client := NewClient(ClientConfig{
-			BaseURL:  cfg.BaseURL,
-			Email:    cfg.Email,
-			APIToken: cfg.APIToken,
-		})
-
-		startAt := 0
-		if cursor != "" {
-			var err error
-			startAt, err = strconv.Atoi(cursor)
-			if err != nil {
-				return core.PageResult[Issue]{}, fmt.Errorf("parse cursor: %w", err)
-			}
-		}
-
-		maxResults := cfg.MaxResults
-		if maxResults <= 0 {
-			maxResults = 100
-		}
-
-		result, err := client.SearchJQLWithParams(ctx, SearchJQLParams{
-			JQL:        cfg.JQL,
-			StartAt:    startAt,
-			MaxResults: maxResults,
-		})
-		if err != nil {
-			return core.PageResult[Issue]{}, fmt.Errorf("search jql: %w", err)
-		}
-
-		nextStartAt := startAt + len(result.Issues)
-		hasMore := nextStartAt < result.Total
-		nextCursor := ""
-		if hasMore {
-			nextCursor = strconv.Itoa(nextStartAt)
-		}
+// FetchIssues creates a node for fetching Jira issues.
+func FetchIssues(input FetchIssuesInput) *core.Node[FetchIssuesInput, FetchIssuesOutput] {
+	return core.NewNode("jira.FetchIssues", FetchIssuesActivity, input)
+}
 
-		return core.PageResult[Issue]{
-			Items:      result.Issues,
-			NextCursor: nextCursor,
-			HasMore:    hasMore,
-		}, nil
-	}
+// FetchIssue creates a node for fetching a single Jira issue.
+func FetchIssue(input FetchIssueInput) *core.Node[FetchIssueInput, FetchIssueOutput] {
+	return core.NewNode("jira.FetchIssue", FetchIssueActivity, input)
+}
 
-	return core.PaginateWithConfig[Issue, SearchAllJQLConfig]("jira.SearchAllJQL", fetcher).
-		WithTimeout(30 * time.Minute)
+// SearchJQL creates a node for searching Jira with JQL.
+func SearchJQL(input SearchJQLInput) *core.Node[SearchJQLInput, SearchJQLOutput] {
+	return core.NewNode("jira.SearchJQL", SearchJQLActivity, input)
 }
@@ -0,0 +1,211 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProjectQuery(t *testing.T) {
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		timeZone string
+		query    projectQuery
+		want     string
+		wantErr  string
+	}{
+		{
+			name:  "project",
+			query: projectQuery{Project: "PROJ"},
+			want:  `project = "PROJ" ORDER BY updated DESC`,
+		},
+		{
+			name:  "projects and components",
+			query: projectQuery{Project: "A", Projects: []string{"B"}, Components: []string{"API"}, OrderBy: "created ASC"},
+			want:  `project in ("A", "B") AND component in ("API") ORDER BY created ASC`,
+		},
+		{
+			name:     "dates ahead of UTC",
+			timeZone: "Asia/Kolkata",
+			query:    projectQuery{Project: "PROJ", Since: &since, Until: &until},
+			want:     `project = "PROJ" AND updated >= "2024-03-01 05:30" AND updated <= "2024-03-02 05:30" ORDER BY updated DESC`,
+		},
+		{
+			name:     "dates behind UTC",
+			timeZone: "America/Los_Angeles",
+			query:    projectQuery{Project: "PROJ", Since: &since},
+			want:     `project = "PROJ" AND updated >= "2024-02-29 16:00" ORDER BY updated DESC`,
+		},
+		{
+			name:     "unknown time zone",
+			timeZone: "Mars/Olympus_Mons",
+			query:    projectQuery{Project: "PROJ", Since: &since},
+			want:     `project = "PROJ" AND updated >= "2024-03-01 00:00" ORDER BY updated DESC`,
+		},
+		{
+			name:     "updated by",
+			timeZone: "America/Los_Angeles",
+			query:    projectQuery{Project: "PROJ", Since: &since, UpdatedBy: "dev@acme.com"},
+			want:     `project = "PROJ" AND updated >= "2024-02-29 16:00" AND issue in updatedBy("acc-dev", "2024-02-29 16:00") ORDER BY updated DESC`,
+		},
+		{
+			name:  "worklog author",
+			query: projectQuery{Project: "PROJ", WorklogAuthor: "acc-ops"},
+			want:  `project = "PROJ" AND worklogAuthor = "acc-ops" ORDER BY updated DESC`,
+		},
+		{
+			name:  "status categories",
+			query: projectQuery{Project: "PROJ", StatusCategories: []string{"in progress", " DONE "}},
+			want:  `project = "PROJ" AND statusCategory in ("In Progress", "Done") ORDER BY updated DESC`,
+		},
+		{
+			name:  "relative since",
+			query: projectQuery{Project: "PROJ", SinceRelative: "-7d"},
+			want:  `project = "PROJ" AND updated >= "-7d" ORDER BY updated DESC`,
+		},
+		{
+			name:    "no scope",
+			query:   projectQuery{StatusCategories: []string{"Done"}},
+			wantErr: "project or components must be set",
+		},
+		{
+			name:    "unknown status category",
+			query:   projectQuery{Project: "PROJ", StatusCategories: []string{"Blocked"}},
+			wantErr: `unknown status category "Blocked"`,
+		},
+		{
+			name:    "since and relative since",
+			query:   projectQuery{Project: "PROJ", Since: &since, SinceRelative: "-7d"},
+			wantErr: "mutually exclusive",
+		},
+		{
+			name:    "invalid relative since",
+			query:   projectQuery{Project: "PROJ", SinceRelative: "yesterday"},
+			wantErr: `invalid relative date "yesterday"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeJira{timeZone: tt.timeZone, routes: map[string]http.HandlerFunc{
+				"/rest/api/3/user/search": func(w http.ResponseWriter, r *http.Request) {
+					writeJSON(w, []map[string]any{{"accountId": "acc-dev", "emailAddress": r.URL.Query().Get("query")}})
+				},
+			}}
+			client := NewClient(ClientConfig{BaseURL: fake.start(t)})
+			defer client.Close()
+
+			got, err := tt.query.build(context.Background(), client)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("build: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("query = %s\nwant    %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchIssuesAllComments(t *testing.T) {
+	comment := func(body string) map[string]any {
+		return map[string]any{"total": 1, "comments": []map[string]any{{
+			"id":     "1",
+			"body":   body,
+			"author": map[string]any{"displayName": "Dev"},
+		}}}
+	}
+
+	tests := []struct {
+		name         string
+		status       int
+		opts         DocumentOptions
+		wantContent  string
+		wantMetadata string
+		wantFailed   bool
+		wantErr      bool
+	}{
+		{name: "fetched", status: http.StatusOK, wantContent: "[Comment by Dev]: fetched"},
+		{name: "forbidden", status: http.StatusForbidden, wantContent: "[Comment by Dev]: embedded", wantMetadata: "false"},
+		{name: "not found", status: http.StatusNotFound, wantContent: "[Comment by Dev]: embedded", wantMetadata: "false"},
+		{name: "failed", status: http.StatusInternalServerError, wantErr: true},
+		{name: "failed, continuing", status: http.StatusInternalServerError, opts: DocumentOptions{ContinueOnError: true}, wantFailed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeJira{
+				issues: []map[string]any{testIssue("PROJ-1", map[string]any{"comment": comment("embedded")})},
+				routes: map[string]http.HandlerFunc{
+					"/rest/api/3/issue/PROJ-1/comment": func(w http.ResponseWriter, r *http.Request) {
+						if tt.status != http.StatusOK {
+							http.Error(w, `{"errorMessages":["nope"]}`, tt.status)
+							return
+						}
+						writeJSON(w, comment("fetched"))
+					},
+				},
+			}
+
+			tt.opts.IncludeAllComments = true
+			out, err := runActivity(t, FetchIssuesActivity, FetchIssuesInput{
+				BaseURL:         fake.start(t),
+				Project:         "PROJ",
+				DocumentOptions: tt.opts,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if tt.wantFailed {
+				if out.Count != 0 || len(out.FailedIssues) != 1 || out.FailedIssues[0].Key != "PROJ-1" {
+					t.Errorf("count = %d, failed = %v, want PROJ-1 failed", out.Count, out.FailedIssues)
+				}
+				return
+			}
+
+			docs := loadDocuments(t, out.Ref)
+			if len(docs) != 1 {
+				t.Fatalf("stored %d documents, want 1", len(docs))
+			}
+			if !strings.Contains(docs[0].Content, tt.wantContent) {
+				t.Errorf("content = %q, want %q", docs[0].Content, tt.wantContent)
+			}
+			if got := docs[0].Metadata["comments_accessible"]; got != tt.wantMetadata {
+				t.Errorf("comments_accessible = %q, want %q", got, tt.wantMetadata)
+			}
+		})
+	}
+}
+
+func TestFetchIssueExcluded(t *testing.T) {
+	fake := &fakeJira{routes: map[string]http.HandlerFunc{
+		"/rest/api/3/issue/PROJ-1": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, testIssue("PROJ-1", nil))
+		},
+	}}
+
+	out, err := runActivity(t, FetchIssueActivity, FetchIssueInput{
+		BaseURL:         fake.start(t),
+		IssueKey:        "PROJ-1",
+		DocumentOptions: DocumentOptions{ExcludeKeys: []string{"PROJ-1"}},
+	})
+	if err != nil {
+		t.Fatalf("FetchIssueActivity: %v", err)
+	}
+	if !out.Found || !out.Skipped || out.Document.ID != "" {
+		t.Errorf("found = %v, skipped = %v, document = %q, want found and skipped without a document", out.Found, out.Skipped, out.Document.ID)
+	}
+}
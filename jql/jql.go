@@ -0,0 +1,251 @@
+// Package jql provides helpers for composing Jira Query Language strings.
+package jql
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DateFormat is the layout used for absolute dates in JQL.
+const DateFormat = "2006-01-02 15:04"
+
+// Query is a conjunction of JQL clauses with an optional ORDER BY.
+type Query struct {
+	clauses []string
+	orderBy string
+}
+
+// And appends a clause to the query. Empty clauses are ignored.
+func (q *Query) And(clause string) *Query {
+	if clause != "" {
+		q.clauses = append(q.clauses, clause)
+	}
+	return q
+}
+
+// OrderBy sets the ORDER BY part of the query, e.g. "updated DESC".
+func (q *Query) OrderBy(order string) *Query {
+	q.orderBy = order
+	return q
+}
+
+// String renders the query.
+func (q *Query) String() string {
+	s := strings.Join(q.clauses, " AND ")
+	if q.orderBy != "" {
+		if s != "" {
+			s += " "
+		}
+		s += "ORDER BY " + q.orderBy
+	}
+	return s
+}
+
+// IsUnbounded reports whether query has no restricting clause, i.e. it is
+// empty or consists only of an ORDER BY.
+func IsUnbounded(query string) bool {
+	q := strings.TrimSpace(query)
+	upper := strings.ToUpper(q)
+	if strings.HasPrefix(upper, "ORDER BY") {
+		return true
+	}
+	return q == ""
+}
+
+// Quote returns s as a double-quoted JQL string literal.
+func Quote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// Date returns t formatted as a quoted JQL date literal, in t's location.
+// Jira interprets the literal in the time zone of the searching user's
+// profile, so convert t to that zone first.
+func Date(t time.Time) string {
+	return Quote(t.Format(DateFormat))
+}
+
+// relativeDuration matches Jira relative dates such as "-24h", "-7d" or
+// "-1w 2d": a sign, then number-unit pairs in minutes, hours, days or weeks.
+var relativeDuration = regexp.MustCompile(`^[-+]?\d+[mhdw](\s+\d+[mhdw])*$`)
+
+// IsRelativeDuration reports whether s is a relative date Jira accepts,
+// e.g. "-24h" or "-1w 2d".
+func IsRelativeDuration(s string) bool {
+	return relativeDuration.MatchString(s)
+}
+
+// Equals returns a `field = "value"` clause.
+func Equals(field, value string) string {
+	return field + " = " + Quote(value)
+}
+
+// In returns a `field in ("a", "b")` clause, or an empty string when values
+// is empty.
+func In(field string, values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = Quote(v)
+	}
+	return field + " in (" + strings.Join(quoted, ", ") + ")"
+}
+
+// NotIn returns a `field not in ("a", "b")` clause, or an empty string when
+// values is empty.
+func NotIn(field string, values []string) string {
+	if clause := In(field, values); clause != "" {
+		return field + " not" + strings.TrimPrefix(clause, field)
+	}
+	return ""
+}
+
+// AndClause adds clause to a complete query, keeping its ORDER BY last:
+// "(where) AND clause ORDER BY ...". The original condition is
+// parenthesized so an OR in it cannot swallow the clause. An empty clause
+// returns query unchanged.
+func AndClause(query, clause string) string {
+	if clause == "" {
+		return query
+	}
+
+	where, order := splitOrderBy(query)
+	if strings.TrimSpace(where) != "" {
+		clause = "(" + strings.TrimSpace(where) + ") AND " + clause
+	}
+	if order != "" {
+		clause += " " + order
+	}
+	return clause
+}
+
+// splitOrderBy splits query at its ORDER BY, ignoring occurrences inside
+// quoted strings. order includes the ORDER BY keywords.
+func splitOrderBy(query string) (where, order string) {
+	upper := strings.ToUpper(query)
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0 && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case strings.HasPrefix(upper[i:], "ORDER") && (i == 0 || !isIdentByte(query[i-1])):
+			rest := skipSpaces(upper, i+len("ORDER"))
+			if rest > i+len("ORDER") && strings.HasPrefix(upper[rest:], "BY") &&
+				(rest+2 == len(upper) || !isIdentByte(upper[rest+2])) {
+				return query[:i], strings.TrimSpace(query[i:])
+			}
+		}
+	}
+	return query, ""
+}
+
+// UpdatedBy returns an `issue in updatedBy(...)` clause matching issues
+// updated by the given account. The optional from and to bounds restrict
+// the update window; a nil to with a non-nil from leaves it open-ended.
+func UpdatedBy(accountID string, from, to *time.Time) string {
+	args := []string{Quote(accountID)}
+	switch {
+	case from != nil && to != nil:
+		args = append(args, Date(*from), Date(*to))
+	case from != nil:
+		args = append(args, Date(*from))
+	case to != nil:
+		// updatedBy takes positional dates, so an upper bound alone
+		// needs an explicit lower bound.
+		args = append(args, Date(time.Unix(0, 0).UTC()), Date(*to))
+	}
+	return "issue in updatedBy(" + strings.Join(args, ", ") + ")"
+}
+
+// WorklogAuthor returns a `worklogAuthor = "accountID"` clause.
+func WorklogAuthor(accountID string) string {
+	return Equals("worklogAuthor", accountID)
+}
+
+// SubstituteCurrentUser replaces every currentUser() call in query with a
+// quoted "accountid:<accountID>" literal, so a query written for the
+// caller can be run on behalf of another user. Occurrences inside string
+// literals are left alone; matching is case-insensitive and tolerates
+// whitespace between the parentheses.
+func SubstituteCurrentUser(query, accountID string) string {
+	replacement := Quote("accountid:" + accountID)
+
+	var b strings.Builder
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+
+		if quote != 0 {
+			b.WriteByte(c)
+			switch {
+			case c == '\\' && i+1 < len(query):
+				i++
+				b.WriteByte(query[i])
+			case c == quote:
+				quote = 0
+			}
+			continue
+		}
+
+		if c == '"' || c == '\'' {
+			quote = c
+			b.WriteByte(c)
+			continue
+		}
+
+		if n := matchCurrentUser(query, i); n > 0 {
+			b.WriteString(replacement)
+			i += n - 1
+			continue
+		}
+
+		b.WriteByte(c)
+	}
+
+	return b.String()
+}
+
+// matchCurrentUser returns the length of a currentUser() call starting at
+// query[i], or 0 if there is none.
+func matchCurrentUser(query string, i int) int {
+	const name = "currentuser"
+	if i > 0 && isIdentByte(query[i-1]) {
+		return 0
+	}
+	if len(query)-i < len(name) || !strings.EqualFold(query[i:i+len(name)], name) {
+		return 0
+	}
+
+	j := skipSpaces(query, i+len(name))
+	if j >= len(query) || query[j] != '(' {
+		return 0
+	}
+	j = skipSpaces(query, j+1)
+	if j >= len(query) || query[j] != ')' {
+		return 0
+	}
+
+	return j + 1 - i
+}
+
+func skipSpaces(s string, i int) int {
+	for i < len(s) && (s[i] == ' ' || s[i] == '\t' || s[i] == '\n' || s[i] == '\r') {
+		i++
+	}
+	return i
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '.' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,154 @@
+package jql
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "PROJ", want: `"PROJ"`},
+		{in: `say "hi"`, want: `"say \"hi\""`},
+		{in: `C:\temp`, want: `"C:\\temp"`},
+	}
+
+	for _, tt := range tests {
+		if got := Quote(tt.in); got != tt.want {
+			t.Errorf("Quote(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestQuery(t *testing.T) {
+	var q Query
+	q.And(Equals("project", "PROJ")).And(In("status", nil)).And(NotIn("key", []string{"PROJ-1", "PROJ-2"}))
+	q.OrderBy("updated DESC")
+
+	want := `project = "PROJ" AND key not in ("PROJ-1", "PROJ-2") ORDER BY updated DESC`
+	if got := q.String(); got != want {
+		t.Errorf("query = %s, want %s", got, want)
+	}
+}
+
+func TestIsUnbounded(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{query: "", want: true},
+		{query: "  ", want: true},
+		{query: "ORDER BY created", want: true},
+		{query: " order by created", want: true},
+		{query: "project = PROJ", want: false},
+		{query: "orderly = true", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := IsUnbounded(tt.query); got != tt.want {
+			t.Errorf("IsUnbounded(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestIsRelativeDuration(t *testing.T) {
+	tests := []struct {
+		s    string
+		want bool
+	}{
+		{s: "-24h", want: true},
+		{s: "-7d", want: true},
+		{s: "-1w 2d", want: true},
+		{s: "+30m", want: true},
+		{s: "15m", want: true},
+		{s: "-7 days", want: false},
+		{s: "yesterday", want: false},
+		{s: "-1y", want: false},
+		{s: "", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := IsRelativeDuration(tt.s); got != tt.want {
+			t.Errorf("IsRelativeDuration(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestDate(t *testing.T) {
+	kolkata := time.FixedZone("IST", 5*3600+1800)
+	at := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	if got := Date(at); got != `"2024-03-01 00:00"` {
+		t.Errorf("Date in UTC = %s", got)
+	}
+	if got := Date(at.In(kolkata)); got != `"2024-03-01 05:30"` {
+		t.Errorf("Date in IST = %s", got)
+	}
+}
+
+func TestUpdatedBy(t *testing.T) {
+	from := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 8, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		from, to *time.Time
+		want     string
+	}{
+		{name: "open", want: `issue in updatedBy("acc-1")`},
+		{name: "from", from: &from, want: `issue in updatedBy("acc-1", "2024-03-01 09:00")`},
+		{name: "window", from: &from, to: &to, want: `issue in updatedBy("acc-1", "2024-03-01 09:00", "2024-03-08 09:00")`},
+		{name: "to", to: &to, want: `issue in updatedBy("acc-1", "1970-01-01 00:00", "2024-03-08 09:00")`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UpdatedBy("acc-1", tt.from, tt.to); got != tt.want {
+				t.Errorf("UpdatedBy = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAndClause(t *testing.T) {
+	tests := []struct {
+		query  string
+		clause string
+		want   string
+	}{
+		{query: "project = A", clause: "", want: "project = A"},
+		{query: "project = A OR project = B", clause: `key != "A-1"`, want: `(project = A OR project = B) AND key != "A-1"`},
+		{query: "project = A order by rank", clause: "x = 1", want: "(project = A) AND x = 1 order by rank"},
+		{query: `summary ~ "order by" ORDER BY created`, clause: "x = 1", want: `(summary ~ "order by") AND x = 1 ORDER BY created`},
+		{query: "ORDER BY created", clause: "x = 1", want: "x = 1 ORDER BY created"},
+		{query: "sortorder = 1", clause: "x = 1", want: "(sortorder = 1) AND x = 1"},
+	}
+
+	for _, tt := range tests {
+		if got := AndClause(tt.query, tt.clause); got != tt.want {
+			t.Errorf("AndClause(%q, %q) = %s, want %s", tt.query, tt.clause, got, tt.want)
+		}
+	}
+}
+
+func TestSubstituteCurrentUser(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{query: "assignee = currentUser()", want: `assignee = "accountid:acc-1"`},
+		{query: "reporter = CURRENTUSER( ) OR watcher = currentuser()", want: `reporter = "accountid:acc-1" OR watcher = "accountid:acc-1"`},
+		{query: `summary ~ "currentUser()"`, want: `summary ~ "currentUser()"`},
+		{query: `summary ~ "say \"currentUser()\""`, want: `summary ~ "say \"currentUser()\""`},
+		{query: "assignee = mycurrentUser()", want: "assignee = mycurrentUser()"},
+		{query: "assignee = currentUser", want: "assignee = currentUser"},
+	}
+
+	for _, tt := range tests {
+		if got := SubstituteCurrentUser(tt.query, "acc-1"); got != tt.want {
+			t.Errorf("SubstituteCurrentUser(%q) = %s, want %s", tt.query, got, tt.want)
+		}
+	}
+}
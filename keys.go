@@ -0,0 +1,149 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/resolute-sh/resolute-jira/jql"
+)
+
+// issueKeyPattern matches issue keys such as PROJ-123. Project keys are
+// uppercase, start with a letter and are at least two characters long, so
+// lowercase words such as pre-2020 or utf-8 do not match.
+var issueKeyPattern = regexp.MustCompile(`\b([A-Z][A-Z0-9_]+)-([1-9][0-9]*)\b`)
+
+// ExtractKeys returns the issue keys mentioned in text, de-duplicated, in
+// order of first mention. When projectKeys is non-empty only keys of those
+// projects are returned, which also filters out uppercase look-alikes such
+// as COVID-19 or UTF-8. Pass the result to GetIssues to fetch the mentioned
+// issues.
+func ExtractKeys(text string, projectKeys []string) []string {
+	allowed := make(map[string]bool, len(projectKeys))
+	for _, project := range projectKeys {
+		allowed[strings.ToUpper(strings.TrimSpace(project))] = true
+	}
+
+	seen := make(map[string]bool)
+	var keys []string
+	for _, match := range issueKeyPattern.FindAllStringSubmatch(text, -1) {
+		project := match[1]
+		if len(allowed) > 0 && !allowed[project] {
+			continue
+		}
+		key := project + "-" + match[2]
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// GetIssues returns the issues with the given keys in the order of keys,
+// fetched in batches of 100. On Cloud it uses BulkFetch; on Server and Data
+// Center, or when the instance does not offer bulk fetch, it runs one
+// search per batch. Keys of deleted or inaccessible issues are left out
+// rather than failing the call; issues found under a new key after a move
+// follow the others.
+func (c *Client) GetIssues(ctx context.Context, keys []string) ([]Issue, error) {
+	var fetched []Issue
+	var err error
+	if info, infoErr := c.GetServerInfo(ctx); infoErr == nil && info.IsCloud() {
+		fetched, err = c.BulkFetch(ctx, keys, nil)
+		if errors.Is(err, ErrNotFound) {
+			fetched, err = c.searchKeys(ctx, keys)
+		}
+	} else {
+		fetched, err = c.searchKeys(ctx, keys)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]Issue, len(fetched))
+	for _, issue := range fetched {
+		found[issue.Key] = issue
+	}
+
+	issues := make([]Issue, 0, len(found))
+	for _, key := range keys {
+		if issue, ok := found[key]; ok {
+			issues = append(issues, issue)
+			delete(found, key)
+		}
+	}
+	// Issues moved to another project come back under their new key.
+	for _, issue := range fetched {
+		if _, ok := found[issue.Key]; ok {
+			issues = append(issues, issue)
+			delete(found, issue.Key)
+		}
+	}
+	return issues, nil
+}
+
+// bulkFetchLimit is the maximum number of issues per bulk fetch request.
+const bulkFetchLimit = 100
+
+// BulkFetch returns the issues with the given keys or IDs, with the given
+// fields (default the navigable fields), using the bulk fetch endpoint in
+// requests of 100 issues. Issues are returned in response order; keys of
+// deleted or inaccessible issues are left out. The endpoint is only
+// available on Cloud.
+func (c *Client) BulkFetch(ctx context.Context, keys []string, fields []string) ([]Issue, error) {
+	if err := validateFields(fields); err != nil {
+		return nil, err
+	}
+
+	var issues []Issue
+	for start := 0; start < len(keys); start += bulkFetchLimit {
+		payload := struct {
+			IssueIDsOrKeys []string `json:"issueIdsOrKeys"`
+			Fields         []string `json:"fields,omitempty"`
+		}{
+			IssueIDsOrKeys: keys[start:min(start+bulkFetchLimit, len(keys))],
+			Fields:         fields,
+		}
+
+		// Keys that cannot be read are reported in issueErrors instead of
+		// failing the request.
+		var result struct {
+			Issues []Issue `json:"issues"`
+		}
+		if err := c.do(ctx, http.MethodPost, "/rest/api/3/issue/bulkfetch", nil, payload, &result); err != nil {
+			return nil, fmt.Errorf("bulk fetch issues: %w", err)
+		}
+		issues = append(issues, result.Issues...)
+	}
+	return issues, nil
+}
+
+// searchKeys returns the issues with the given keys using one key search
+// per batch of 100, for instances without bulk fetch.
+func (c *Client) searchKeys(ctx context.Context, keys []string) ([]Issue, error) {
+	const batchSize = 100
+
+	var issues []Issue
+	for start := 0; start < len(keys); start += batchSize {
+		batch := keys[start:min(start+batchSize, len(keys))]
+
+		query := url.Values{}
+		query.Set("jql", jql.In("key", batch))
+		query.Set("maxResults", fmt.Sprint(batchSize))
+		// Keys of deleted issues would otherwise fail the whole query.
+		query.Set("validateQuery", "warn")
+
+		var result SearchResult
+		if err := c.do(ctx, http.MethodGet, "/rest/api/3/search", query, nil, &result); err != nil {
+			return nil, fmt.Errorf("search issues: %w", err)
+		}
+		issues = append(issues, result.Issues...)
+	}
+	return issues, nil
+}
@@ -0,0 +1,146 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestExtractKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		projects []string
+		want     []string
+	}{
+		{
+			name: "order of first mention",
+			text: "Fixed in PROJ-12, see PROJ-3 and again PROJ-12.",
+			want: []string{"PROJ-12", "PROJ-3"},
+		},
+		{
+			name: "lowercase look-alikes",
+			text: "utf-8 handling since pre-2020, proj-1 and X-1 are not keys; AB_2-7 is",
+			want: []string{"AB_2-7"},
+		},
+		{
+			name: "leading zero",
+			text: "PROJ-01 PROJ-0 PROJ-10",
+			want: []string{"PROJ-10"},
+		},
+		{
+			name:     "project filter",
+			text:     "COVID-19 delayed OPS-4 and PROJ-5 (UTF-8)",
+			projects: []string{" proj ", "OPS"},
+			want:     []string{"OPS-4", "PROJ-5"},
+		},
+		{
+			name: "embedded in URLs",
+			text: "https://acme.atlassian.net/browse/PROJ-7?focusedCommentId=1",
+			want: []string{"PROJ-7"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractKeys(tt.text, tt.projects); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("ExtractKeys = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetIssues(t *testing.T) {
+	// OLD-1 was moved and comes back as NEW-5; PROJ-9 was deleted.
+	issues := []map[string]any{testIssue("PROJ-1", nil), testIssue("NEW-5", nil), testIssue("PROJ-2", nil)}
+
+	tests := []struct {
+		name       string
+		deployment string
+		bulkStatus int
+		want       string
+	}{
+		{name: "cloud", deployment: DeploymentCloud, want: "bulkfetch"},
+		{name: "cloud without bulk fetch", deployment: DeploymentCloud, bulkStatus: http.StatusNotFound, want: "bulkfetch,search"},
+		{name: "data center", deployment: DeploymentDataCenter, want: "search"},
+		{name: "unknown deployment", want: "search"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var endpoints []string
+			fake := &fakeJira{routes: map[string]http.HandlerFunc{
+				"/rest/api/2/serverInfo": func(w http.ResponseWriter, r *http.Request) {
+					if tt.deployment == "" {
+						http.Error(w, "unavailable", http.StatusServiceUnavailable)
+						return
+					}
+					writeJSON(w, map[string]any{"deploymentType": tt.deployment})
+				},
+				"/rest/api/3/issue/bulkfetch": func(w http.ResponseWriter, r *http.Request) {
+					endpoints = append(endpoints, "bulkfetch")
+					if tt.bulkStatus != 0 {
+						http.Error(w, "not found", tt.bulkStatus)
+						return
+					}
+					writeJSON(w, map[string]any{"issues": issues})
+				},
+				"/rest/api/3/search": func(w http.ResponseWriter, r *http.Request) {
+					endpoints = append(endpoints, "search")
+					if jql := r.URL.Query().Get("jql"); !strings.HasPrefix(jql, `key in ("PROJ-2", "OLD-1"`) {
+						t.Errorf("jql = %s", jql)
+					}
+					writeJSON(w, map[string]any{"total": len(issues), "issues": issues})
+				},
+			}}
+			client := NewClient(ClientConfig{BaseURL: fake.start(t), ShouldRetry: func(*http.Response, error, int) bool { return false }})
+			defer client.Close()
+
+			got, err := client.GetIssues(context.Background(), []string{"PROJ-2", "OLD-1", "PROJ-9", "PROJ-1"})
+			if err != nil {
+				t.Fatalf("GetIssues: %v", err)
+			}
+			var keys []string
+			for _, issue := range got {
+				keys = append(keys, issue.Key)
+			}
+			if fmt.Sprint(keys) != "[PROJ-2 PROJ-1 NEW-5]" {
+				t.Errorf("issues = %v, want [PROJ-2 PROJ-1 NEW-5]", keys)
+			}
+			if strings.Join(endpoints, ",") != tt.want {
+				t.Errorf("endpoints = %v, want %s", endpoints, tt.want)
+			}
+		})
+	}
+}
+
+func TestBulkFetchBatches(t *testing.T) {
+	var batches []int
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			IssueIDsOrKeys []string `json:"issueIdsOrKeys"`
+		}
+		if err := decodeBody(r, &body); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		batches = append(batches, len(body.IssueIDsOrKeys))
+		writeJSON(w, map[string]any{"issues": []any{}})
+	}), ClientConfig{})
+
+	keys := make([]string, 250)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("PROJ-%d", i+1)
+	}
+	if _, err := client.BulkFetch(context.Background(), keys, nil); err != nil {
+		t.Fatalf("BulkFetch: %v", err)
+	}
+	if fmt.Sprint(batches) != "[100 100 50]" {
+		t.Errorf("batches = %v, want [100 100 50]", batches)
+	}
+
+	if _, err := client.BulkFetch(context.Background(), keys, []string{"bad field"}); err == nil {
+		t.Errorf("BulkFetch with an invalid field succeeded")
+	}
+}
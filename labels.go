@@ -0,0 +1,78 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// labelOp is a single add or remove operation of an issue edit's update block.
+type labelOp struct {
+	Add    string `json:"add,omitempty"`
+	Remove string `json:"remove,omitempty"`
+}
+
+// AddLabels adds labels to an issue, leaving its other labels untouched.
+// Adding a label the issue already has is a no-op, so it is safe to retry.
+func (c *Client) AddLabels(ctx context.Context, issueKey string, labels ...string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	ops := make([]labelOp, 0, len(labels))
+	for _, label := range labels {
+		ops = append(ops, labelOp{Add: label})
+	}
+
+	return c.updateLabels(ctx, issueKey, ops)
+}
+
+// SetLabels makes an issue's labels exactly desired. It reads the current
+// labels and sends one edit adding the missing and removing the extra ones,
+// so labels in both sets are never touched. No edit is sent when the sets
+// already match.
+func (c *Client) SetLabels(ctx context.Context, issueKey string, desired []string) error {
+	query := url.Values{}
+	query.Set("fields", "labels")
+
+	issue, err := c.readIssue(ctx, issueKey, query)
+	if err != nil {
+		return fmt.Errorf("get labels: %w", err)
+	}
+
+	current := make(map[string]bool, len(issue.Fields.Labels))
+	for _, label := range issue.Fields.Labels {
+		current[label] = true
+	}
+	want := make(map[string]bool, len(desired))
+	for _, label := range desired {
+		want[label] = true
+	}
+
+	var ops []labelOp
+	for _, label := range desired {
+		if !current[label] {
+			ops = append(ops, labelOp{Add: label})
+			current[label] = true
+		}
+	}
+	for _, label := range issue.Fields.Labels {
+		if !want[label] {
+			ops = append(ops, labelOp{Remove: label})
+		}
+	}
+
+	if len(ops) == 0 {
+		return nil
+	}
+
+	return c.updateLabels(ctx, issueKey, ops)
+}
+
+func (c *Client) updateLabels(ctx context.Context, issueKey string, ops []labelOp) error {
+	body := map[string]any{
+		"update": map[string]any{"labels": ops},
+	}
+	return c.do(ctx, http.MethodPut, "/rest/api/3/issue/"+url.PathEscape(issueKey), nil, body, nil)
+}
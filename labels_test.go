@@ -0,0 +1,68 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestSetLabels(t *testing.T) {
+	tests := []struct {
+		name    string
+		desired []string
+		want    string // the labels update sent, empty for none
+	}{
+		{name: "add and remove", desired: []string{"ops", "security", "ops"}, want: `[{"add":"security"},{"remove":"legacy"}]`},
+		{name: "already set", desired: []string{"legacy", "ops"}},
+		{name: "clear", desired: nil, want: `[{"remove":"ops"},{"remove":"legacy"}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent string
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.Method {
+				case http.MethodGet:
+					if fields := r.URL.Query().Get("fields"); fields != "labels" {
+						t.Errorf("fields = %s, want labels", fields)
+					}
+					writeJSON(w, testIssue("PROJ-1", map[string]any{"labels": []any{"ops", "legacy"}}))
+				case http.MethodPut:
+					var body struct {
+						Update struct {
+							Labels json.RawMessage `json:"labels"`
+						} `json:"update"`
+					}
+					if err := decodeBody(r, &body); err != nil {
+						t.Errorf("decode body: %v", err)
+					}
+					sent = string(body.Update.Labels)
+					w.WriteHeader(http.StatusNoContent)
+				}
+			}), ClientConfig{})
+
+			if err := client.SetLabels(context.Background(), "PROJ-1", tt.desired); err != nil {
+				t.Fatalf("SetLabels: %v", err)
+			}
+			if sent != tt.want {
+				t.Errorf("update = %s, want %s", sent, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddLabels(t *testing.T) {
+	var requests int
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNoContent)
+	}), ClientConfig{})
+
+	if err := client.AddLabels(context.Background(), "PROJ-1"); err != nil || requests != 0 {
+		t.Errorf("AddLabels without labels: error %v, %d requests, want none", err, requests)
+	}
+	if err := client.AddLabels(context.Background(), "PROJ-1", "ops"); err != nil || requests != 1 {
+		t.Errorf("AddLabels: error %v, %d requests, want 1", err, requests)
+	}
+}
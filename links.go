@@ -0,0 +1,163 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/resolute-sh/resolute/core"
+)
+
+// GetIssueLinkTypes returns the issue link types configured on the
+// instance. The result is cached for the life of the client; failures are
+// not cached. The returned slice is shared and must not be modified.
+func (c *Client) GetIssueLinkTypes(ctx context.Context) ([]IssueLinkType, error) {
+	c.linkTypesMu.Lock()
+	defer c.linkTypesMu.Unlock()
+
+	if c.linkTypes != nil {
+		return c.linkTypes, nil
+	}
+
+	var resp struct {
+		IssueLinkTypes []IssueLinkType `json:"issueLinkTypes"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/rest/api/3/issueLinkType", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	c.linkTypes = resp.IssueLinkTypes
+	if c.linkTypes == nil {
+		c.linkTypes = []IssueLinkType{}
+	}
+	return c.linkTypes, nil
+}
+
+// findIssueLinkType returns the link type named name, case-insensitively.
+func (c *Client) findIssueLinkType(ctx context.Context, name string) (IssueLinkType, error) {
+	types, err := c.GetIssueLinkTypes(ctx)
+	if err != nil {
+		return IssueLinkType{}, fmt.Errorf("get issue link types: %w", err)
+	}
+
+	names := make([]string, 0, len(types))
+	for _, t := range types {
+		if strings.EqualFold(t.Name, strings.TrimSpace(name)) {
+			return t, nil
+		}
+		names = append(names, t.Name)
+	}
+	return IssueLinkType{}, fmt.Errorf("unknown issue link type %q, want one of %s", name, strings.Join(names, ", "))
+}
+
+// CreateIssueLink links two issues with the link type named linkType, such
+// as "Blocks" or "Relates". The keys map to the inwardIssue and
+// outwardIssue of the request. The type is checked against
+// GetIssueLinkTypes first, so a typo fails with the list of valid names
+// instead of Jira's generic 404.
+func (c *Client) CreateIssueLink(ctx context.Context, inwardKey, outwardKey, linkType string) error {
+	t, err := c.findIssueLinkType(ctx, linkType)
+	if err != nil {
+		return err
+	}
+
+	type linkTypeRef struct {
+		Name string `json:"name"`
+	}
+	type issueRef struct {
+		Key string `json:"key"`
+	}
+	payload := struct {
+		Type         linkTypeRef `json:"type"`
+		InwardIssue  issueRef    `json:"inwardIssue"`
+		OutwardIssue issueRef    `json:"outwardIssue"`
+	}{
+		Type:         linkTypeRef{Name: t.Name},
+		InwardIssue:  issueRef{Key: inwardKey},
+		OutwardIssue: issueRef{Key: outwardKey},
+	}
+
+	return c.do(ctx, http.MethodPost, "/rest/api/3/issueLink", nil, payload, nil)
+}
+
+// CreateIssueLinkInput is the input for CreateIssueLinkActivity.
+type CreateIssueLinkInput struct {
+	BaseURL    string
+	Email      string
+	APIToken   string
+	InwardKey  string
+	OutwardKey string
+	LinkType   string // link type name, e.g. "Blocks"
+
+	ClientOptions
+}
+
+// CreateIssueLinkOutput is the output of CreateIssueLinkActivity.
+type CreateIssueLinkOutput struct {
+	Linked bool
+}
+
+// CreateIssueLinkActivity links two issues.
+func CreateIssueLinkActivity(ctx context.Context, input CreateIssueLinkInput) (CreateIssueLinkOutput, error) {
+	client, err := input.ClientOptions.newClient(ClientConfig{
+		BaseURL:  input.BaseURL,
+		Email:    input.Email,
+		APIToken: input.APIToken,
+	})
+	if err != nil {
+		return CreateIssueLinkOutput{}, err
+	}
+	defer client.Close()
+
+	if err := client.CreateIssueLink(ctx, input.InwardKey, input.OutwardKey, input.LinkType); err != nil {
+		return CreateIssueLinkOutput{}, fmt.Errorf("create issue link: %w", err)
+	}
+
+	return CreateIssueLinkOutput{Linked: true}, nil
+}
+
+// CreateIssueLink creates a node for linking two issues.
+func CreateIssueLink(input CreateIssueLinkInput) *core.Node[CreateIssueLinkInput, CreateIssueLinkOutput] {
+	return core.NewNode("jira.CreateIssueLink", CreateIssueLinkActivity, input)
+}
+
+// FetchIssueLinkTypesInput is the input for FetchIssueLinkTypesActivity.
+type FetchIssueLinkTypesInput struct {
+	BaseURL  string
+	Email    string
+	APIToken string
+
+	ClientOptions
+}
+
+// FetchIssueLinkTypesOutput is the output of FetchIssueLinkTypesActivity.
+type FetchIssueLinkTypesOutput struct {
+	LinkTypes []IssueLinkType
+}
+
+// FetchIssueLinkTypesActivity lists the issue link types configured on the
+// instance with their inward and outward phrases.
+func FetchIssueLinkTypesActivity(ctx context.Context, input FetchIssueLinkTypesInput) (FetchIssueLinkTypesOutput, error) {
+	client, err := input.ClientOptions.newClient(ClientConfig{
+		BaseURL:  input.BaseURL,
+		Email:    input.Email,
+		APIToken: input.APIToken,
+	})
+	if err != nil {
+		return FetchIssueLinkTypesOutput{}, err
+	}
+	defer client.Close()
+
+	types, err := client.GetIssueLinkTypes(ctx)
+	if err != nil {
+		return FetchIssueLinkTypesOutput{}, fmt.Errorf("get issue link types: %w", err)
+	}
+
+	return FetchIssueLinkTypesOutput{LinkTypes: types}, nil
+}
+
+// FetchIssueLinkTypes creates a node for listing issue link types.
+func FetchIssueLinkTypes(input FetchIssueLinkTypesInput) *core.Node[FetchIssueLinkTypesInput, FetchIssueLinkTypesOutput] {
+	return core.NewNode("jira.FetchIssueLinkTypes", FetchIssueLinkTypesActivity, input)
+}
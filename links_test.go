@@ -0,0 +1,81 @@
+package jira
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCreateIssueLinkActivity(t *testing.T) {
+	tests := []struct {
+		name     string
+		linkType string
+		wantBody string
+		wantErr  string
+	}{
+		{name: "case-insensitive type", linkType: " blocks ", wantBody: `{"type":{"name":"Blocks"},"inwardIssue":{"key":"PROJ-1"},"outwardIssue":{"key":"PROJ-2"}}`},
+		{name: "unknown type", linkType: "Duplicates", wantErr: `unknown issue link type "Duplicates", want one of Blocks, Relates`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body string
+			fake := &fakeJira{routes: map[string]http.HandlerFunc{
+				"/rest/api/3/issueLinkType": func(w http.ResponseWriter, r *http.Request) {
+					writeJSON(w, map[string]any{"issueLinkTypes": []any{
+						map[string]any{"id": "1", "name": "Blocks", "inward": "is blocked by", "outward": "blocks"},
+						map[string]any{"id": "2", "name": "Relates", "inward": "relates to", "outward": "relates to"},
+					}})
+				},
+				"/rest/api/3/issueLink": func(w http.ResponseWriter, r *http.Request) {
+					raw, _ := io.ReadAll(r.Body)
+					body = strings.TrimSpace(string(raw))
+					w.WriteHeader(http.StatusCreated)
+				},
+			}}
+
+			out, err := runActivity(t, CreateIssueLinkActivity, CreateIssueLinkInput{
+				BaseURL:    fake.start(t),
+				InwardKey:  "PROJ-1",
+				OutwardKey: "PROJ-2",
+				LinkType:   tt.linkType,
+			})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				if body != "" {
+					t.Errorf("link created for an unknown type")
+				}
+				return
+			}
+			if err != nil || !out.Linked {
+				t.Fatalf("CreateIssueLinkActivity = %+v, %v", out, err)
+			}
+			if body != tt.wantBody {
+				t.Errorf("body = %s, want %s", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestGetIssueLinkTypesCached(t *testing.T) {
+	var requests atomic.Int32
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		writeJSON(w, map[string]any{})
+	}), ClientConfig{})
+
+	for range 2 {
+		types, err := client.GetIssueLinkTypes(context.Background())
+		if err != nil || types == nil || len(types) != 0 {
+			t.Fatalf("GetIssueLinkTypes = %v, %v, want an empty list", types, err)
+		}
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("%d requests, want 1", n)
+	}
+}
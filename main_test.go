@@ -0,0 +1,228 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	transform "github.com/resolute-sh/resolute-transform"
+	"github.com/resolute-sh/resolute/core"
+	"go.temporal.io/sdk/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(runTests(m))
+}
+
+// runTests runs m with document storage in a temporary directory.
+func runTests(m *testing.M) int {
+	dir, err := os.MkdirTemp("", "resolute-jira-test")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer os.RemoveAll(dir)
+
+	// GetStorage creates its default storage under the working directory
+	// on first use and SetStorage does not prevent that, so let it do so
+	// in dir before replacing it.
+	wd, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if err := os.Chdir(dir); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	_, err = core.GetStorage()
+	if chdirErr := os.Chdir(wd); err == nil {
+		err = chdirErr
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	backend, err := core.NewLocalStorage(filepath.Join(dir, "data"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	core.SetStorage(core.NewStorage(backend))
+
+	return m.Run()
+}
+
+// runActivity executes an activity in a Temporal test environment, which
+// provides the activity context heartbeats need.
+func runActivity[I, O any](t *testing.T, fn func(context.Context, I) (O, error), input I) (O, error) {
+	t.Helper()
+
+	var suite testsuite.WorkflowTestSuite
+	env := suite.NewTestActivityEnvironment()
+	env.RegisterActivity(fn)
+
+	var out O
+	value, err := env.ExecuteActivity(fn, input)
+	if err != nil {
+		return out, err
+	}
+	if err := value.Get(&out); err != nil {
+		t.Fatalf("decode activity output: %v", err)
+	}
+	return out, nil
+}
+
+// loadDocuments returns the documents stored under ref.
+func loadDocuments(t *testing.T, ref core.DataRef) []transform.Document {
+	t.Helper()
+
+	docs, err := transform.LoadDocuments(context.Background(), ref)
+	if err != nil {
+		t.Fatalf("load documents: %v", err)
+	}
+	return docs
+}
+
+// testIssue returns the JSON of an issue with the given fields, which
+// default to a summary.
+func testIssue(key string, fields map[string]any) map[string]any {
+	if fields == nil {
+		fields = map[string]any{}
+	}
+	if _, ok := fields["summary"]; !ok {
+		fields["summary"] = "Summary of " + key
+	}
+	return map[string]any{
+		"id":     strconv.Itoa(len(key) * 1000),
+		"key":    key,
+		"self":   "https://example.atlassian.net/rest/api/3/issue/" + key,
+		"fields": fields,
+	}
+}
+
+// writeJSON writes v as a JSON response.
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// decodeBody decodes the JSON body of r into v.
+func decodeBody(r *http.Request, v any) error {
+	return json.NewDecoder(r.Body).Decode(v)
+}
+
+// fakeJira serves issue searches over a fixed list of issues, offset- and
+// cursor-paginated, plus the handlers in routes. It records the JQL and
+// page parameters of every search.
+type fakeJira struct {
+	issues   []map[string]any
+	timeZone string
+	routes   map[string]http.HandlerFunc
+
+	mu       sync.Mutex
+	searches []fakeSearch
+}
+
+// fakeSearch is one search request received by fakeJira.
+type fakeSearch struct {
+	JQL        string
+	StartAt    int
+	MaxResults int
+	Token      string
+}
+
+func (f *fakeJira) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if handler, ok := f.routes[r.URL.Path]; ok {
+		handler(w, r)
+		return
+	}
+
+	switch r.URL.Path {
+	case "/rest/api/3/myself":
+		timeZone := f.timeZone
+		if timeZone == "" {
+			timeZone = "UTC"
+		}
+		writeJSON(w, map[string]any{"accountId": "self", "timeZone": timeZone})
+	case "/rest/api/3/search", "/rest/api/3/search/jql":
+		f.search(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeJira) search(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	cursor := r.URL.Path == "/rest/api/3/search/jql"
+	startAt, _ := strconv.Atoi(query.Get("startAt"))
+	if cursor {
+		startAt, _ = strconv.Atoi(query.Get("nextPageToken"))
+	}
+	maxResults, _ := strconv.Atoi(query.Get("maxResults"))
+
+	f.mu.Lock()
+	f.searches = append(f.searches, fakeSearch{
+		JQL:        query.Get("jql"),
+		StartAt:    startAt,
+		MaxResults: maxResults,
+		Token:      query.Get("nextPageToken"),
+	})
+	f.mu.Unlock()
+
+	end := min(startAt+maxResults, len(f.issues))
+	page := f.issues[min(startAt, end):end]
+	response := map[string]any{"issues": page}
+	if cursor {
+		if end < len(f.issues) {
+			response["nextPageToken"] = strconv.Itoa(end)
+		}
+	} else {
+		response["startAt"] = startAt
+		response["maxResults"] = maxResults
+		response["total"] = len(f.issues)
+	}
+	writeJSON(w, response)
+}
+
+// recorded returns the searches received so far.
+func (f *fakeJira) recorded() []fakeSearch {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]fakeSearch(nil), f.searches...)
+}
+
+// start serves f for the duration of the test and returns its base URL.
+func (f *fakeJira) start(t *testing.T) string {
+	t.Helper()
+
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+// newTestClient serves handler for the duration of the test and returns a
+// client for it, with cfg applied on top of the server URL.
+func newTestClient(t *testing.T, handler http.Handler, cfg ClientConfig) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	cfg.BaseURL = srv.URL
+	if cfg.Email == "" {
+		cfg.Email = "bot@example.com"
+		cfg.APIToken = "token"
+	}
+	client := NewClient(cfg)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
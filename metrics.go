@@ -0,0 +1,90 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Metrics receives request counters and latencies from a Client. It is
+// kept small so adapters stay trivial; implementations must be safe for
+// concurrent use.
+//
+// A Prometheus adapter is a few lines:
+//
+//	type promMetrics struct {
+//		requests *prometheus.CounterVec   // labels: method, status
+//		retries  *prometheus.CounterVec   // labels: reason
+//		latency  *prometheus.HistogramVec // labels: method
+//	}
+//
+//	func (m promMetrics) IncRequest(method string, status int) {
+//		m.requests.WithLabelValues(method, strconv.Itoa(status)).Inc()
+//	}
+//
+//	func (m promMetrics) IncRetry(reason string) {
+//		m.retries.WithLabelValues(reason).Inc()
+//	}
+//
+//	func (m promMetrics) ObserveLatency(method string, d time.Duration) {
+//		m.latency.WithLabelValues(method).Observe(d.Seconds())
+//	}
+type Metrics interface {
+	// IncRequest counts a completed request by method and final status.
+	// Status is 0 when no response was received.
+	IncRequest(method string, status int)
+
+	// IncRetry counts a retried attempt. Reason is the status code that
+	// caused it, such as "429" or "503", or "network" for transport errors.
+	IncRetry(reason string)
+
+	// ObserveLatency records a request's duration, retries included.
+	ObserveLatency(method string, d time.Duration)
+}
+
+// SlowRequestObserver is implemented by Metrics that also count requests
+// exceeding ClientConfig.SlowRequestThreshold. It is separate from Metrics
+// so existing implementations keep compiling.
+type SlowRequestObserver interface {
+	IncSlowRequest(method string)
+}
+
+var metricsRegistry = newRegistry[Metrics]("metrics")
+
+// RegisterMetrics makes a Metrics implementation available under name for
+// the ClientOptions.Metrics option.
+func RegisterMetrics(name string, metrics Metrics) {
+	metricsRegistry.register(name, metrics)
+}
+
+// nopMetrics is the Metrics used when ClientConfig.Metrics is nil.
+type nopMetrics struct{}
+
+func (nopMetrics) IncRequest(string, int)               {}
+func (nopMetrics) IncRetry(string)                      {}
+func (nopMetrics) ObserveLatency(string, time.Duration) {}
+
+type metricsKey struct{}
+
+// withMetrics returns ctx carrying m, so transport middlewares such as
+// WithRetry can report to the client's Metrics.
+func withMetrics(ctx context.Context, m Metrics) context.Context {
+	return context.WithValue(ctx, metricsKey{}, m)
+}
+
+// metricsFrom returns the Metrics carried by ctx, or a no-op.
+func metricsFrom(ctx context.Context) Metrics {
+	if m, ok := ctx.Value(metricsKey{}).(Metrics); ok {
+		return m
+	}
+	return nopMetrics{}
+}
+
+// retryReason labels a retried attempt for Metrics.IncRetry.
+func retryReason(resp *http.Response, err error) string {
+	if err != nil || resp == nil {
+		return "network"
+	}
+	return strconv.Itoa(resp.StatusCode)
+}
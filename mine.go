@@ -0,0 +1,119 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/resolute-sh/resolute-jira/jql"
+	"github.com/resolute-sh/resolute/core"
+)
+
+// MineInput is the input for MineActivity.
+type MineInput struct {
+	BaseURL  string
+	Email    string
+	APIToken string
+
+	// Projects restricts results to these project keys. Empty searches
+	// every accessible project.
+	Projects []string
+
+	// IncludeResolved also returns resolved issues; by default only
+	// unresolved issues are fetched.
+	IncludeResolved bool
+
+	MaxResults int // per page, default 100
+	Limit      int // stop after this many issues, 0 for all
+
+	DocumentOptions
+	ClientOptions
+}
+
+// MineOutput is the output of MineActivity.
+type MineOutput struct {
+	Ref          core.DataRef
+	Count        int
+	AccountID    string
+	EffectiveJQL string
+
+	// IssueCount and CommentCount split Count into issue documents and,
+	// with ExplodeComments, comment documents.
+	IssueCount   int
+	CommentCount int
+
+	// Skipped lists the keys of issues dropped for thin content.
+	Skipped []string
+
+	// FailedIssues lists the issues left out because they failed to
+	// convert, with ContinueOnError.
+	FailedIssues []FailedIssue
+}
+
+// MineActivity fetches the issues assigned to the authenticated user across
+// projects, most recently updated first.
+func MineActivity(ctx context.Context, input MineInput) (MineOutput, error) {
+	client, err := input.ClientOptions.newClient(ClientConfig{
+		BaseURL:  input.BaseURL,
+		Email:    input.Email,
+		APIToken: input.APIToken,
+	})
+	if err != nil {
+		return MineOutput{}, err
+	}
+	defer client.Close()
+
+	user, err := client.GetCurrentUser(ctx)
+	if err != nil {
+		return MineOutput{}, fmt.Errorf("get current user: %w", err)
+	}
+
+	var q jql.Query
+	q.And(jql.Equals("assignee", user.AccountID))
+	q.And(jql.In("project", input.Projects))
+	if !input.IncludeResolved {
+		q.And("resolution = Unresolved")
+	}
+	q.OrderBy("updated DESC")
+	query := q.String()
+
+	var converted conversion
+	_, _, err = paginateSearch(ctx, client, query, paginateOptions{
+		PageSize: input.MaxResults,
+		Limit:    input.Limit,
+	}, func(issues []Issue) error {
+		page, err := issuesToDocuments(ctx, client, issues, input.DocumentOptions)
+		if err != nil {
+			return err
+		}
+		converted.add(page)
+		return nil
+	})
+	if err != nil {
+		return MineOutput{}, err
+	}
+
+	ref, err := storeConversion(ctx, client, &converted, input.DocumentOptions)
+	if err != nil {
+		return MineOutput{}, fmt.Errorf("store documents: %w", err)
+	}
+	docs := converted.Docs
+
+	issueCount, commentCount := countDocuments(docs)
+	return MineOutput{
+		Ref:          ref,
+		Count:        len(docs),
+		IssueCount:   issueCount,
+		CommentCount: commentCount,
+		Skipped:      converted.Skipped,
+		FailedIssues: converted.Failed,
+		AccountID:    user.AccountID,
+		EffectiveJQL: query,
+	}, nil
+}
+
+// Mine creates a node for fetching the authenticated user's issues.
+func Mine(input MineInput) *core.Node[MineInput, MineOutput] {
+	return core.NewNode("jira.Mine", MineActivity, input).
+		WithTimeout(30 * time.Minute)
+}
@@ -0,0 +1,913 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/resolute-sh/resolute-jira/jql"
+	transform "github.com/resolute-sh/resolute-transform"
+	"github.com/resolute-sh/resolute/core"
+	"go.temporal.io/sdk/activity"
+)
+
+// FetchAllIssuesConfig contains configuration for fetching all issues.
+type FetchAllIssuesConfig struct {
+	BaseURL    string
+	Email      string
+	APIToken   string
+	Project    string
+	Since      *time.Time
+	Until      *time.Time
+	MaxResults int // per page, default 100
+
+	// Projects restricts results to any of these project keys, in addition
+	// to Project. Use ShardByProject to fetch them in parallel instead.
+	Projects []string
+
+	// UpdatedBy restricts results to issues updated by this user (email or
+	// account ID) within the Since/Until window.
+	UpdatedBy string
+
+	// WorklogAuthor restricts results to issues with work logged by this
+	// user (email or account ID).
+	WorklogAuthor string
+
+	// Components restricts results to issues in any of these components.
+	// Without a Project, the search spans all accessible projects.
+	Components []string
+
+	// StatusCategories restricts results to issues whose status is in any
+	// of these categories: "To Do", "In Progress" or "Done". Unlike status
+	// names they are the same across workflows.
+	StatusCategories []string
+
+	// SinceRelative is a lower bound relative to now, such as "-24h" or
+	// "-7d" (units m, h, d, w), evaluated by Jira in its own timezone, so
+	// no client clock or timezone is involved. Unlike an absolute Since it
+	// is not reproducible: a retried run covers a shifted window. It cannot
+	// be combined with Since.
+	SinceRelative string
+
+	// OrderBy is the JQL ORDER BY clause, default "updated DESC", or
+	// "created ASC, key ASC" with a CursorStore.
+	OrderBy string
+
+	// RawJQL, when set, is searched as is instead of the query composed
+	// from the filters above, which are then ignored, OrderBy included.
+	// Paging, Limit, Fields and document options still apply.
+	RawJQL string
+
+	// Limit stops fetching after this many issues, regardless of how many
+	// match. Combined with OrderBy it selects e.g. the latest or oldest N.
+	Limit int
+
+	// Fields selects the fields fetched per issue, with the syntax of
+	// SearchJQLParams.Fields, e.g. {"*all", "-comment"}. MinUpdated and
+	// MaxUpdated stay zero when "updated" is excluded.
+	Fields []string
+
+	// AutoPageSize starts with small pages and tunes their size toward
+	// TargetPageBytes (default 1 MiB) per response, from the average issue
+	// size observed on the previous page, so pages of issues with huge
+	// descriptions stay small while lean ones grow. MaxResults becomes the
+	// upper bound.
+	AutoPageSize    bool
+	TargetPageBytes int
+
+	// ChunkSize, when set, stores the documents in chunks of this many as
+	// they are fetched, returned in Refs, instead of in one Ref at the
+	// end, so they are never all held in memory. SortDocumentsBy then
+	// orders documents within each chunk only.
+	ChunkSize int
+
+	// CursorStore checkpoints the fetch, for backfills spanning several
+	// runs. Activity inputs name a store registered with
+	// RegisterCursorStore in CursorStoreName instead. It
+	// requires ChunkSize: chunks are then stored on page boundaries, so
+	// they may exceed ChunkSize by up to a page, and after each stored
+	// chunk the position of the next page is saved. A run starts at the
+	// saved position, if any, and clears it once everything is stored.
+	// Positions are keyed by "jira:" and a SHA-256 of the base URL and the
+	// effective JQL, which holds the project, so only runs searching the
+	// same instance with the same query share one.
+	// Limit, MinUpdated and MaxUpdated apply to each run alone.
+	// Saved offsets assume issues keep their positions between runs, so
+	// OrderBy must sort ascending on fields fixed at creation, such as
+	// created and key; an order on updated is rejected. The ORDER BY of a
+	// RawJQL is not checked.
+	CursorStore     CursorStore `json:"-"`
+	CursorStoreName string
+
+	DocumentOptions
+	ClientOptions
+}
+
+// FetchAllIssuesOutput is the output of FetchAllIssuesActivity.
+//
+// MinUpdated and MaxUpdated bound the updated timestamps of every issue
+// fetched by the run, across all chunks with ChunkSize, and are zero when
+// nothing was fetched. MaxUpdated is the value to persist as the next
+// run's Since once the run has covered the whole query. That holds for
+// either OrderBy direction, as the output is only returned after the last
+// page: with "updated ASC" it is the last issue seen, with "updated DESC"
+// it comes from the first page. With ChunkSize, chunks are stored while
+// fetching, so a run failing midway may leave some behind, but it returns
+// no bounds and the previous Since stays in place. A run cut short by
+// Limit, or one run of a CursorStore backfill, covers part of the query
+// only; its bounds describe that part and are not a resume point.
+type FetchAllIssuesOutput struct {
+	Ref         core.DataRef
+	Count       int
+	PageCount   int
+	FinalCursor string
+	MinUpdated  time.Time
+	MaxUpdated  time.Time
+
+	// Refs holds the chunk refs, in fetch order, when ChunkSize is set;
+	// Ref is empty then.
+	Refs []core.DataRef
+
+	// IssueCount and CommentCount split Count into issue documents and,
+	// with ExplodeComments, comment documents.
+	IssueCount   int
+	CommentCount int
+
+	// Skipped lists the keys of issues dropped for thin content.
+	Skipped []string
+
+	// FailedIssues lists the issues left out because they failed to
+	// convert, with ContinueOnError.
+	FailedIssues []FailedIssue
+
+	// EffectiveJQL, PageSize, Limit and OrderBy record the query and
+	// resolved settings the fetch actually ran with. OrderBy is empty with
+	// RawJQL, whose own ORDER BY applies.
+	EffectiveJQL string
+	PageSize     int
+	Limit        int
+	OrderBy      string
+
+	// Excluded counts the fetched issues dropped by ExcludeKeys. Issues
+	// excluded in the JQL are never fetched and not counted.
+	Excluded int
+
+	// Resumed reports that the fetch started at a position loaded from the
+	// CursorStore.
+	Resumed bool
+
+	// RateLimit is the rate-limit state Jira reported at the end of the
+	// fetch, zero when it sent none.
+	RateLimit RateLimitInfo
+}
+
+// FetchAllIssuesActivity fetches every page of issues matching the config
+// and stores them as documents.
+func FetchAllIssuesActivity(ctx context.Context, cfg FetchAllIssuesConfig) (FetchAllIssuesOutput, error) {
+	client, err := cfg.ClientOptions.newClient(ClientConfig{
+		BaseURL:  cfg.BaseURL,
+		Email:    cfg.Email,
+		APIToken: cfg.APIToken,
+	})
+	if err != nil {
+		return FetchAllIssuesOutput{}, err
+	}
+	defer client.Close()
+
+	store, err := cfg.cursorStore()
+	if err != nil {
+		return FetchAllIssuesOutput{}, err
+	}
+	if store != nil {
+		if cfg.ChunkSize <= 0 {
+			return FetchAllIssuesOutput{}, fmt.Errorf("cursor store requires a chunk size")
+		}
+		if cfg.OrderBy == "" {
+			cfg.OrderBy = checkpointOrderBy
+		} else if cfg.RawJQL == "" && !stableOrder(cfg.OrderBy) {
+			return FetchAllIssuesOutput{}, fmt.Errorf("cursor store requires a stable order such as %q, not %q", checkpointOrderBy, cfg.OrderBy)
+		}
+	}
+
+	query, err := cfg.query(ctx, client)
+	if err != nil {
+		return FetchAllIssuesOutput{}, err
+	}
+
+	query = excludeKeysClause(query, cfg.DocumentOptions)
+
+	out := FetchAllIssuesOutput{
+		EffectiveJQL: query,
+		PageSize:     pageSizeOrDefault(cfg.MaxResults),
+		Limit:        cfg.Limit,
+		OrderBy:      cfg.OrderBy,
+	}
+	if cfg.RawJQL != "" {
+		out.OrderBy = ""
+	} else if out.OrderBy == "" {
+		out.OrderBy = "updated DESC"
+	}
+
+	sink := documentSink{client: client, chunkSize: cfg.ChunkSize, opts: cfg.DocumentOptions, pageAligned: store != nil}
+	pageOpts := paginateOptions{
+		PageSize: out.PageSize,
+		Limit:    cfg.Limit,
+		Fields:   cfg.Fields,
+
+		AutoPageSize:    cfg.AutoPageSize,
+		TargetPageBytes: cfg.TargetPageBytes,
+	}
+	key := cursorKey(cfg.BaseURL, query)
+	if store != nil {
+		pageOpts.Resume, err = store.Load(key)
+		if err != nil {
+			return FetchAllIssuesOutput{}, fmt.Errorf("load cursor: %w", err)
+		}
+		out.Resumed = pageOpts.Resume != ""
+		pageOpts.Checkpoint = func(next string) error {
+			// Only positions with every earlier document stored are safe
+			// to resume from.
+			if len(sink.converted.Docs) > 0 {
+				return nil
+			}
+			if err := store.Save(key, next); err != nil {
+				return fmt.Errorf("save cursor: %w", err)
+			}
+			return nil
+		}
+	}
+
+	fetched := 0
+	out.PageCount, out.FinalCursor, err = paginateSearch(ctx, client, query, pageOpts, func(issues []Issue) error {
+		fetched += len(issues)
+		for _, issue := range issues {
+			updated, err := parseTime(issue.Fields.Updated)
+			if err != nil {
+				continue
+			}
+			if out.MinUpdated.IsZero() || updated.Before(out.MinUpdated) {
+				out.MinUpdated = updated
+			}
+			if updated.After(out.MaxUpdated) {
+				out.MaxUpdated = updated
+			}
+		}
+
+		page, err := issuesToDocuments(ctx, client, issues, cfg.DocumentOptions)
+		if err != nil {
+			return err
+		}
+		return sink.add(ctx, page)
+	})
+	if err != nil {
+		return FetchAllIssuesOutput{}, err
+	}
+
+	out.Ref, out.Refs, err = sink.finish(ctx)
+	if err != nil {
+		return FetchAllIssuesOutput{}, err
+	}
+	// A search stopped by Limit resumes from its last checkpoint next
+	// time; only one that ran out of issues starts over.
+	if store != nil && (cfg.Limit <= 0 || fetched < cfg.Limit) {
+		if err := store.Save(key, ""); err != nil {
+			return FetchAllIssuesOutput{}, fmt.Errorf("clear cursor: %w", err)
+		}
+	}
+	out.Skipped = sink.converted.Skipped
+	out.FailedIssues = sink.converted.Failed
+	out.Excluded = sink.converted.Excluded
+	out.Count = sink.count
+	out.IssueCount, out.CommentCount = sink.issues, sink.comments
+	out.RateLimit = client.LastRateLimit()
+
+	return out, nil
+}
+
+// query returns the JQL searched for cfg: RawJQL, or the query composed
+// from its filters.
+func (cfg FetchAllIssuesConfig) query(ctx context.Context, client *Client) (string, error) {
+	if cfg.RawJQL != "" {
+		return cfg.RawJQL, nil
+	}
+	return projectQuery{
+		Project:          cfg.Project,
+		Projects:         cfg.Projects,
+		Since:            cfg.Since,
+		Until:            cfg.Until,
+		UpdatedBy:        cfg.UpdatedBy,
+		WorklogAuthor:    cfg.WorklogAuthor,
+		Components:       cfg.Components,
+		StatusCategories: cfg.StatusCategories,
+		SinceRelative:    cfg.SinceRelative,
+		OrderBy:          cfg.OrderBy,
+	}.build(ctx, client)
+}
+
+// FetchAllIssues creates a node that fetches ALL issues using pagination.
+// Unlike FetchIssues which fetches a single page, this fetches all pages.
+// It yields the issues page by page, with the query filters, MaxResults
+// and Fields of the config; FetchAllIssueDocuments stores them as
+// documents instead.
+func FetchAllIssues(config FetchAllIssuesConfig) *core.Node[core.PaginateWithInputParams[FetchAllIssuesConfig], core.PaginateWithInputOutput[Issue, FetchAllIssuesConfig]] {
+	fetcher := func(ctx context.Context, cfg FetchAllIssuesConfig, cursor string) (core.PageResult[Issue], error) {
+		client, err := cfg.ClientOptions.newClient(ClientConfig{
+			BaseURL:  cfg.BaseURL,
+			Email:    cfg.Email,
+			APIToken: cfg.APIToken,
+		})
+		if err != nil {
+			return core.PageResult[Issue]{}, err
+		}
+		defer client.Close()
+
+		query, err := cfg.query(ctx, client)
+		if err != nil {
+			return core.PageResult[Issue]{}, err
+		}
+		return fetchIssuePage(ctx, client, query, cfg.MaxResults, cfg.Fields, cursor)
+	}
+
+	return core.PaginateWithConfig[Issue, FetchAllIssuesConfig]("jira.FetchAllIssues", fetcher).
+		WithTimeout(30 * time.Minute) // Long timeout for large datasets
+}
+
+// FetchAllIssueDocuments creates a node that fetches every page of issues
+// matching the config and stores them as documents.
+func FetchAllIssueDocuments(config FetchAllIssuesConfig) *core.Node[FetchAllIssuesConfig, FetchAllIssuesOutput] {
+	return core.NewNode("jira.FetchAllIssueDocuments", FetchAllIssuesActivity, config).
+		WithTimeout(30 * time.Minute)
+}
+
+// fetchIssuePage fetches the page of query starting at cursor, for the
+// paginated nodes. The cursor is a startAt offset or, in cursor-search
+// mode, where no total is reported, the page token.
+func fetchIssuePage(ctx context.Context, client *Client, query string, pageSize int, fields []string, cursor string) (core.PageResult[Issue], error) {
+	params := SearchJQLParams{
+		JQL:        query,
+		MaxResults: pageSizeOrDefault(pageSize),
+		Fields:     fields,
+	}
+	if client.cursorSearch {
+		params.NextPageToken = cursor
+	} else if cursor != "" {
+		var err error
+		params.StartAt, err = strconv.Atoi(cursor)
+		if err != nil {
+			return core.PageResult[Issue]{}, fmt.Errorf("parse cursor: %w", err)
+		}
+	}
+
+	result, err := client.SearchJQLWithParams(ctx, params)
+	if err != nil {
+		return core.PageResult[Issue]{}, fmt.Errorf("search jql: %w", err)
+	}
+
+	var hasMore bool
+	var nextCursor string
+	if client.cursorSearch {
+		hasMore = !result.IsLast && result.NextPageToken != ""
+		if hasMore {
+			nextCursor = result.NextPageToken
+		}
+	} else {
+		nextStartAt := params.StartAt + len(result.Issues)
+		hasMore = len(result.Issues) > 0 && nextStartAt < result.Total
+		if hasMore {
+			nextCursor = strconv.Itoa(nextStartAt)
+		}
+	}
+
+	return core.PageResult[Issue]{
+		Items:      result.Issues,
+		NextCursor: nextCursor,
+		HasMore:    hasMore,
+	}, nil
+}
+
+// ShardByProject splits a config spanning several projects (Project plus
+// Projects) into one config per project, each keeping every other setting,
+// so a workflow can run them as parallel FetchAllIssueDocuments nodes and
+// merge their refs. A config with at most one project, or with RawJQL, is
+// returned as is.
+//
+// Limit applies per shard, so the merged result can hold up to Limit
+// issues per project; re-apply it after merging when a global cap matters.
+func ShardByProject(cfg FetchAllIssuesConfig) []FetchAllIssuesConfig {
+	if cfg.RawJQL != "" {
+		return []FetchAllIssuesConfig{cfg}
+	}
+
+	var projects []string
+	seen := make(map[string]bool)
+	for _, project := range append([]string{cfg.Project}, cfg.Projects...) {
+		if project != "" && !seen[project] {
+			seen[project] = true
+			projects = append(projects, project)
+		}
+	}
+	if len(projects) <= 1 {
+		return []FetchAllIssuesConfig{cfg}
+	}
+
+	shards := make([]FetchAllIssuesConfig, 0, len(projects))
+	for _, project := range projects {
+		shard := cfg
+		shard.Project = project
+		shard.Projects = nil
+		shards = append(shards, shard)
+	}
+	return shards
+}
+
+// MergeIssues merges the issues of several shards into one slice sorted
+// by key, as lessIssueKey orders them. An issue present in several sets is
+// kept once, in the version with the newest updated time; on a tie the
+// version from the earliest set wins, so the result does not depend on
+// which shard finished first.
+func MergeIssues(sets ...[]Issue) []Issue {
+	index := make(map[string]int)
+	var merged []Issue
+	for _, set := range sets {
+		for _, issue := range set {
+			i, ok := index[issue.Key]
+			if !ok {
+				index[issue.Key] = len(merged)
+				merged = append(merged, issue)
+				continue
+			}
+			if issueUpdated(issue).After(issueUpdated(merged[i])) {
+				merged[i] = issue
+			}
+		}
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		return lessIssueKey(merged[i].Key, merged[j].Key)
+	})
+	return merged
+}
+
+// issueUpdated returns the updated time of an issue, or the zero time
+// when it is missing or malformed.
+func issueUpdated(issue Issue) time.Time {
+	t, _ := parseTime(issue.Fields.Updated)
+	return t
+}
+
+// LatestNInput is the input for LatestNActivity.
+type LatestNInput struct {
+	BaseURL  string
+	Email    string
+	APIToken string
+	Project  string
+	N        int
+
+	DocumentOptions
+	ClientOptions
+}
+
+// LatestNActivity fetches the N most recently updated issues of a project
+// in ceil(N/100) requests.
+func LatestNActivity(ctx context.Context, input LatestNInput) (FetchAllIssuesOutput, error) {
+	if input.N <= 0 {
+		return FetchAllIssuesOutput{}, fmt.Errorf("n must be positive, got %d", input.N)
+	}
+
+	return FetchAllIssuesActivity(ctx, FetchAllIssuesConfig{
+		BaseURL:         input.BaseURL,
+		Email:           input.Email,
+		APIToken:        input.APIToken,
+		Project:         input.Project,
+		OrderBy:         "updated DESC",
+		Limit:           input.N,
+		DocumentOptions: input.DocumentOptions,
+		ClientOptions:   input.ClientOptions,
+	})
+}
+
+// LatestN creates a node for fetching a project's N most recently updated issues.
+func LatestN(input LatestNInput) *core.Node[LatestNInput, FetchAllIssuesOutput] {
+	return core.NewNode("jira.LatestN", LatestNActivity, input)
+}
+
+// SearchAllJQLConfig contains configuration for fetching all issues matching a JQL query.
+type SearchAllJQLConfig struct {
+	BaseURL    string
+	Email      string
+	APIToken   string
+	JQL        string
+	MaxResults int // per page, default 100
+
+	// Limit stops fetching after this many issues; 0 means no limit.
+	Limit int
+
+	// Fields selects the fields fetched per issue, with the syntax of
+	// SearchJQLParams.Fields.
+	Fields []string
+
+	// AutoPageSize starts with small pages and tunes their size toward
+	// TargetPageBytes (default 1 MiB) per response, from the average issue
+	// size observed on the previous page, so pages of issues with huge
+	// descriptions stay small while lean ones grow. MaxResults becomes the
+	// upper bound.
+	AutoPageSize    bool
+	TargetPageBytes int
+
+	// ChunkSize, when set, stores the documents in chunks of this many as
+	// they are fetched, returned in Refs, instead of in one Ref at the
+	// end, so they are never all held in memory. SortDocumentsBy then
+	// orders documents within each chunk only.
+	ChunkSize int
+
+	// SubstituteUser, when set, replaces currentUser() in JQL with this
+	// user (email or account ID), so saved queries can run on someone's
+	// behalf without acting as them.
+	SubstituteUser string
+
+	DocumentOptions
+	ClientOptions
+}
+
+// SearchAllJQLOutput is the output of SearchAllJQLActivity.
+type SearchAllJQLOutput struct {
+	Ref         core.DataRef
+	Count       int
+	PageCount   int
+	FinalCursor string
+
+	// Refs holds the chunk refs, in fetch order, when ChunkSize is set;
+	// Ref is empty then.
+	Refs []core.DataRef
+
+	// IssueCount and CommentCount split Count into issue documents and,
+	// with ExplodeComments, comment documents.
+	IssueCount   int
+	CommentCount int
+
+	// Skipped lists the keys of issues dropped for thin content.
+	Skipped []string
+
+	// FailedIssues lists the issues left out because they failed to
+	// convert, with ContinueOnError.
+	FailedIssues []FailedIssue
+
+	// EffectiveJQL, PageSize and Limit record the query (after user
+	// substitution) and resolved settings the search actually ran with.
+	EffectiveJQL string
+	PageSize     int
+	Limit        int
+
+	// Excluded counts the fetched issues dropped by ExcludeKeys. Issues
+	// excluded in the JQL are never fetched and not counted.
+	Excluded int
+
+	// RateLimit is the rate-limit state Jira reported at the end of the
+	// search, zero when it sent none.
+	RateLimit RateLimitInfo
+}
+
+// SearchAllJQLActivity fetches every page of issues matching a JQL query
+// and stores them as documents.
+func SearchAllJQLActivity(ctx context.Context, cfg SearchAllJQLConfig) (SearchAllJQLOutput, error) {
+	client, err := cfg.ClientOptions.newClient(ClientConfig{
+		BaseURL:  cfg.BaseURL,
+		Email:    cfg.Email,
+		APIToken: cfg.APIToken,
+	})
+	if err != nil {
+		return SearchAllJQLOutput{}, err
+	}
+	defer client.Close()
+
+	query, err := cfg.query(ctx, client)
+	if err != nil {
+		return SearchAllJQLOutput{}, err
+	}
+
+	query = excludeKeysClause(query, cfg.DocumentOptions)
+
+	out := SearchAllJQLOutput{
+		EffectiveJQL: query,
+		PageSize:     pageSizeOrDefault(cfg.MaxResults),
+		Limit:        cfg.Limit,
+	}
+
+	sink := documentSink{client: client, chunkSize: cfg.ChunkSize, opts: cfg.DocumentOptions}
+	out.PageCount, out.FinalCursor, err = paginateSearch(ctx, client, query, paginateOptions{
+		PageSize: out.PageSize,
+		Limit:    cfg.Limit,
+		Fields:   cfg.Fields,
+
+		AutoPageSize:    cfg.AutoPageSize,
+		TargetPageBytes: cfg.TargetPageBytes,
+	}, func(issues []Issue) error {
+		page, err := issuesToDocuments(ctx, client, issues, cfg.DocumentOptions)
+		if err != nil {
+			return err
+		}
+		return sink.add(ctx, page)
+	})
+	if err != nil {
+		return SearchAllJQLOutput{}, err
+	}
+
+	out.Ref, out.Refs, err = sink.finish(ctx)
+	if err != nil {
+		return SearchAllJQLOutput{}, err
+	}
+	out.Skipped = sink.converted.Skipped
+	out.FailedIssues = sink.converted.Failed
+	out.Excluded = sink.converted.Excluded
+	out.Count = sink.count
+	out.IssueCount, out.CommentCount = sink.issues, sink.comments
+	out.RateLimit = client.LastRateLimit()
+
+	return out, nil
+}
+
+// query returns the JQL searched for cfg, with SubstituteUser applied.
+func (cfg SearchAllJQLConfig) query(ctx context.Context, client *Client) (string, error) {
+	if cfg.SubstituteUser == "" {
+		return cfg.JQL, nil
+	}
+	accountID, err := client.FindAccountID(ctx, cfg.SubstituteUser)
+	if err != nil {
+		return "", fmt.Errorf("resolve substitute user: %w", err)
+	}
+	return jql.SubstituteCurrentUser(cfg.JQL, accountID), nil
+}
+
+// SearchAllJQL creates a node that searches with JQL and fetches all results.
+// It yields the issues page by page; SearchAllJQLDocuments stores them as
+// documents instead.
+func SearchAllJQL(config SearchAllJQLConfig) *core.Node[core.PaginateWithInputParams[SearchAllJQLConfig], core.PaginateWithInputOutput[Issue, SearchAllJQLConfig]] {
+	fetcher := func(ctx context.Context, cfg SearchAllJQLConfig, cursor string) (core.PageResult[Issue], error) {
+		client, err := cfg.ClientOptions.newClient(ClientConfig{
+			BaseURL:  cfg.BaseURL,
+			Email:    cfg.Email,
+			APIToken: cfg.APIToken,
+		})
+		if err != nil {
+			return core.PageResult[Issue]{}, err
+		}
+		defer client.Close()
+
+		query, err := cfg.query(ctx, client)
+		if err != nil {
+			return core.PageResult[Issue]{}, err
+		}
+		return fetchIssuePage(ctx, client, query, cfg.MaxResults, cfg.Fields, cursor)
+	}
+
+	return core.PaginateWithConfig[Issue, SearchAllJQLConfig]("jira.SearchAllJQL", fetcher).
+		WithTimeout(30 * time.Minute)
+}
+
+// SearchAllJQLDocuments creates a node that searches with JQL and stores
+// every result as documents.
+func SearchAllJQLDocuments(config SearchAllJQLConfig) *core.Node[SearchAllJQLConfig, SearchAllJQLOutput] {
+	return core.NewNode("jira.SearchAllJQLDocuments", SearchAllJQLActivity, config).
+		WithTimeout(30 * time.Minute)
+}
+
+// documentSink accumulates the converted pages of a paginated fetch and
+// stores their documents, all at once or, with a chunk size, in chunks as
+// they fill up.
+type documentSink struct {
+	client    *Client
+	chunkSize int
+	opts      DocumentOptions
+
+	// converted holds the pages added so far; with a chunk size its Docs
+	// only holds the documents not stored yet.
+	converted conversion
+	refs      []core.DataRef
+	stored    int // documents stored in refs
+
+	// pageAligned stores every pending document once a chunk is full, so
+	// chunks end on page boundaries.
+	pageAligned bool
+
+	count, issues, comments int
+}
+
+// add adds a converted page, storing every full chunk.
+func (s *documentSink) add(ctx context.Context, page conversion) error {
+	issues, comments := countDocuments(page.Docs)
+	s.count += len(page.Docs)
+	s.issues += issues
+	s.comments += comments
+	s.converted.add(page)
+
+	for s.chunkSize > 0 && len(s.converted.Docs) >= s.chunkSize {
+		if s.pageAligned {
+			if err := s.store(ctx, s.converted.Docs); err != nil {
+				return err
+			}
+			s.converted.Docs = nil
+			break
+		}
+		if err := s.store(ctx, s.converted.Docs[:s.chunkSize]); err != nil {
+			return err
+		}
+		// Copy the rest so the stored chunk can be collected.
+		s.converted.Docs = append([]transform.Document(nil), s.converted.Docs[s.chunkSize:]...)
+	}
+	return nil
+}
+
+// finish stores the remaining documents and returns the single ref, or
+// the chunk refs with a chunk size.
+func (s *documentSink) finish(ctx context.Context) (core.DataRef, []core.DataRef, error) {
+	if s.chunkSize <= 0 {
+		docs := s.converted.Docs
+		ref, err := storeConversion(ctx, s.client, &s.converted, s.opts)
+		if err != nil {
+			return core.DataRef{}, nil, fmt.Errorf("store documents: %w", err)
+		}
+		if len(s.converted.Docs) < len(docs) {
+			s.uncount(docs)
+		}
+		return ref, nil, nil
+	}
+
+	if len(s.converted.Docs) > 0 {
+		if err := s.store(ctx, s.converted.Docs); err != nil {
+			return core.DataRef{}, nil, err
+		}
+		s.converted.Docs = nil
+	}
+	return core.DataRef{}, s.refs, nil
+}
+
+// store stores one chunk. Storage failures are retried for this chunk
+// alone, the chunks stored before it being kept. With ContinueOnError a
+// chunk that still fails is reported in FailedIssues and skipped.
+func (s *documentSink) store(ctx context.Context, docs []transform.Document) error {
+	ref, err := storeDocuments(ctx, docs, s.opts)
+	if err != nil && s.opts.ContinueOnError && ctx.Err() == nil {
+		s.client.logger.WarnContext(ctx, "jira: storing chunk failed",
+			"chunk", len(s.refs)+1, "documents", len(docs), "error", err)
+		s.converted.Failed = append(s.converted.Failed, storeFailures(docs, err)...)
+		s.uncount(docs)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("store chunk %d (documents %d-%d): %w",
+			len(s.refs)+1, s.stored+1, s.stored+len(docs), err)
+	}
+	s.refs = append(s.refs, ref)
+	s.stored += len(docs)
+	return nil
+}
+
+// uncount removes docs, which failed to store, from the document counts.
+func (s *documentSink) uncount(docs []transform.Document) {
+	issues, comments := countDocuments(docs)
+	s.count -= len(docs)
+	s.issues -= issues
+	s.comments -= comments
+}
+
+// paginateOptions controls paginateSearch.
+type paginateOptions struct {
+	PageSize int      // default 100
+	Limit    int      // stop after this many issues; 0 means no limit
+	Fields   []string // see SearchJQLParams.Fields
+
+	// AutoPageSize tunes the page size toward TargetPageBytes per
+	// response, with PageSize as the upper bound; see nextPageSize.
+	AutoPageSize    bool
+	TargetPageBytes int // default 1 MiB
+
+	// Resume starts at a checkpoint passed to Checkpoint by an earlier
+	// search of the same query instead of at the first page.
+	Resume string
+
+	// Checkpoint is called after each page is visited with the checkpoint
+	// to resume after it.
+	Checkpoint func(next string) error
+}
+
+// Auto page sizing settings.
+const (
+	autoPageSizeStart      = 10
+	defaultTargetPageBytes = 1 << 20
+)
+
+// nextPageSize returns the page size to request after a page of count
+// issues took responseBytes. It estimates the bytes per issue from that
+// page and derives the size that would hit target. It shrinks to that size
+// at once, since oversized responses are what it guards against, but only
+// grows halfway there so one lean page does not swing it wildly. The result
+// stays within 1 and upper.
+func nextPageSize(current, count int, responseBytes int64, target, upper int) int {
+	if count == 0 || responseBytes <= 0 {
+		return current
+	}
+	perIssue := max(responseBytes/int64(count), 1)
+	ideal := max(int(min(int64(target)/perIssue, int64(upper))), 1)
+	if ideal < current {
+		return ideal
+	}
+	return min((current+ideal)/2, upper)
+}
+
+// paginateSearch runs a JQL search page by page, calling visit with the
+// issues of each page. It heartbeats after every page and returns the number
+// of pages fetched and the cursor of the last one: its startAt, or its page
+// token in cursor-search mode. With a Limit, the last page is shrunk so that
+// exactly ceil(Limit/PageSize) requests are made at most with a fixed page
+// size.
+func paginateSearch(ctx context.Context, client *Client, query string, opts paginateOptions, visit func([]Issue) error) (int, string, error) {
+	maxPageSize := pageSizeOrDefault(opts.PageSize)
+	pageSize := maxPageSize
+	target := opts.TargetPageBytes
+	if opts.AutoPageSize {
+		pageSize = min(autoPageSizeStart, maxPageSize)
+		if target <= 0 {
+			target = defaultTargetPageBytes
+		}
+	}
+
+	pageCount := 0
+	fetched := 0
+	cursor := ""
+	params := SearchJQLParams{JQL: query, Fields: opts.Fields}
+	if err := applyCheckpoint(&params, opts.Resume, client.cursorSearch); err != nil {
+		return 0, "", err
+	}
+	for {
+		params.MaxResults = pageSize
+		if opts.Limit > 0 {
+			params.MaxResults = min(pageSize, opts.Limit-fetched)
+		}
+
+		result, err := client.SearchJQLWithParams(ctx, params)
+		if err != nil {
+			return pageCount, cursor, fmt.Errorf("search jql: %w", err)
+		}
+
+		pageCount++
+		if opts.AutoPageSize {
+			pageSize = nextPageSize(pageSize, len(result.Issues), result.ResponseBytes, target, maxPageSize)
+		}
+		if client.cursorSearch {
+			cursor = params.NextPageToken
+		} else {
+			cursor = strconv.Itoa(params.StartAt)
+		}
+
+		issues := result.Issues
+		if opts.Limit > 0 && len(issues) > opts.Limit-fetched {
+			issues = issues[:opts.Limit-fetched]
+		}
+		fetched += len(issues)
+
+		if err := visit(issues); err != nil {
+			return pageCount, cursor, err
+		}
+		if opts.Checkpoint != nil {
+			// Resume after the issues visited, which Limit may have cut
+			// short of the page. A page token cannot point into a page, so
+			// a cut page is fetched again from its start.
+			next := checkpointStartAt + strconv.Itoa(params.StartAt+len(issues))
+			if client.cursorSearch {
+				next = checkpointToken + result.NextPageToken
+				if len(issues) < len(result.Issues) {
+					next = checkpointToken + params.NextPageToken
+				}
+			}
+			if err := opts.Checkpoint(next); err != nil {
+				return pageCount, cursor, err
+			}
+		}
+		activity.RecordHeartbeat(ctx, cursor)
+
+		if len(result.Issues) == 0 || (opts.Limit > 0 && fetched >= opts.Limit) {
+			return pageCount, cursor, nil
+		}
+		if client.cursorSearch {
+			if result.IsLast {
+				return pageCount, cursor, nil
+			}
+			params.NextPageToken = result.NextPageToken
+			continue
+		}
+		params.StartAt += len(result.Issues)
+		if params.StartAt >= result.Total {
+			return pageCount, cursor, nil
+		}
+	}
+}
+
+// pageSizeOrDefault returns pageSize, or the default of 100 when unset.
+func pageSizeOrDefault(pageSize int) int {
+	if pageSize <= 0 {
+		return 100
+	}
+	return pageSize
+}
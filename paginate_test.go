@@ -0,0 +1,550 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/resolute-sh/resolute/core"
+)
+
+// testIssues returns n issues PROJ-1 to PROJ-n, updated a day apart
+// starting on 2024-03-01, in ascending or descending order.
+func testIssues(n int, descending bool) []map[string]any {
+	issues := make([]map[string]any, n)
+	for i := range issues {
+		number := i + 1
+		if descending {
+			number = n - i
+		}
+		updated := time.Date(2024, 3, number, 10, 0, 0, 0, time.UTC)
+		issues[i] = testIssue(fmt.Sprintf("PROJ-%d", number), map[string]any{
+			"updated": updated.Format("2006-01-02T15:04:05.000-0700"),
+		})
+	}
+	return issues
+}
+
+// memoryCursorStore is a CursorStore keeping positions in memory.
+type memoryCursorStore struct {
+	mu      sync.Mutex
+	cursors map[string]string
+}
+
+func (s *memoryCursorStore) Load(key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cursors[key], nil
+}
+
+func (s *memoryCursorStore) Save(key, cursor string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cursors == nil {
+		s.cursors = make(map[string]string)
+	}
+	if cursor == "" {
+		delete(s.cursors, key)
+	} else {
+		s.cursors[key] = cursor
+	}
+	return nil
+}
+
+func TestFetchAllIssuesUpdatedBounds(t *testing.T) {
+	tests := []struct {
+		name       string
+		orderBy    string
+		descending bool
+		chunkSize  int
+	}{
+		{name: "ascending", orderBy: "updated ASC"},
+		{name: "descending", orderBy: "updated DESC", descending: true},
+		// The bounds span all chunks, not the last one stored.
+		{name: "chunked", orderBy: "updated DESC", descending: true, chunkSize: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeJira{issues: testIssues(5, tt.descending)}
+			out, err := runActivity(t, FetchAllIssuesActivity, FetchAllIssuesConfig{
+				BaseURL:    fake.start(t),
+				Project:    "PROJ",
+				MaxResults: 2,
+				OrderBy:    tt.orderBy,
+				ChunkSize:  tt.chunkSize,
+			})
+			if err != nil {
+				t.Fatalf("FetchAllIssuesActivity: %v", err)
+			}
+
+			if out.Count != 5 || out.PageCount != 3 {
+				t.Errorf("count = %d in %d pages, want 5 in 3", out.Count, out.PageCount)
+			}
+			wantMin := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+			wantMax := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+			if !out.MinUpdated.Equal(wantMin) || !out.MaxUpdated.Equal(wantMax) {
+				t.Errorf("bounds = %s to %s, want %s to %s", out.MinUpdated, out.MaxUpdated, wantMin, wantMax)
+			}
+			if want := `project = "PROJ" ORDER BY ` + tt.orderBy; out.EffectiveJQL != want {
+				t.Errorf("EffectiveJQL = %q, want %q", out.EffectiveJQL, want)
+			}
+
+			refs := out.Refs
+			if tt.chunkSize == 0 {
+				refs = []core.DataRef{out.Ref}
+			}
+			stored := 0
+			for _, ref := range refs {
+				stored += len(loadDocuments(t, ref))
+			}
+			if stored != 5 {
+				t.Errorf("stored %d documents, want 5", stored)
+			}
+		})
+	}
+}
+
+func TestFetchAllIssuesCursorStore(t *testing.T) {
+	store := &memoryCursorStore{}
+	RegisterCursorStore("test-memory", store)
+
+	fake := &fakeJira{issues: testIssues(5, false)}
+	cfg := FetchAllIssuesConfig{
+		BaseURL:         fake.start(t),
+		Project:         "PROJ",
+		MaxResults:      2,
+		Limit:           3,
+		ChunkSize:       1,
+		CursorStoreName: "test-memory",
+	}
+
+	runs := []struct {
+		wantResumed bool
+		wantStartAt []int
+		wantCount   int
+		wantChunks  int
+		wantSaved   string
+	}{
+		// Stopped by Limit, so the position after the third issue is kept.
+		// Chunks end on page boundaries.
+		{wantStartAt: []int{0, 2}, wantCount: 3, wantChunks: 2, wantSaved: "startAt:3"},
+		// Runs out of issues, so the position is cleared.
+		{wantResumed: true, wantStartAt: []int{3}, wantCount: 2, wantChunks: 1},
+		// Starts over.
+		{wantStartAt: []int{0, 2}, wantCount: 3, wantChunks: 2, wantSaved: "startAt:3"},
+	}
+
+	for i, run := range runs {
+		searched := len(fake.recorded())
+		out, err := runActivity(t, FetchAllIssuesActivity, cfg)
+		if err != nil {
+			t.Fatalf("run %d: FetchAllIssuesActivity: %v", i+1, err)
+		}
+
+		var startAts []int
+		for _, search := range fake.recorded()[searched:] {
+			startAts = append(startAts, search.StartAt)
+		}
+		if out.Resumed != run.wantResumed {
+			t.Errorf("run %d: resumed = %v, want %v", i+1, out.Resumed, run.wantResumed)
+		}
+		if fmt.Sprint(startAts) != fmt.Sprint(run.wantStartAt) {
+			t.Errorf("run %d: searched at %v, want %v", i+1, startAts, run.wantStartAt)
+		}
+		if out.Count != run.wantCount || len(out.Refs) != run.wantChunks {
+			t.Errorf("run %d: count = %d in %d chunks, want %d in %d", i+1, out.Count, len(out.Refs), run.wantCount, run.wantChunks)
+		}
+		saved, _ := store.Load(cursorKey(cfg.BaseURL, out.EffectiveJQL))
+		if saved != run.wantSaved {
+			t.Errorf("run %d: saved cursor = %q, want %q", i+1, saved, run.wantSaved)
+		}
+	}
+
+	cfg.ChunkSize = 0
+	if _, err := runActivity(t, FetchAllIssuesActivity, cfg); err == nil || !strings.Contains(err.Error(), "requires a chunk size") {
+		t.Errorf("error without a chunk size = %v, want one", err)
+	}
+
+	direct := &memoryCursorStore{}
+	cfg.CursorStore = direct
+	if got, err := cfg.cursorStore(); got != CursorStore(direct) || err != nil {
+		t.Errorf("cursorStore() = %v, %v, want the CursorStore field", got, err)
+	}
+}
+
+func TestFetchAllIssuesCursorStoreMutation(t *testing.T) {
+	RegisterCursorStore("test-mutation", &memoryCursorStore{})
+
+	// Issues PROJ-1 to PROJ-4, created and updated a day apart. The server
+	// sorts them by the first ORDER BY term of the query.
+	stamp := func(day int) string {
+		return time.Date(2024, 3, day, 10, 0, 0, 0, time.UTC).Format("2006-01-02T15:04:05.000-0700")
+	}
+	issue := func(number, updated int) map[string]any {
+		return testIssue(fmt.Sprintf("PROJ-%d", number), map[string]any{
+			"created": stamp(number),
+			"updated": stamp(updated),
+		})
+	}
+	var mu sync.Mutex
+	issues := []map[string]any{issue(1, 1), issue(2, 2), issue(3, 3), issue(4, 4)}
+	search := func(w http.ResponseWriter, r *http.Request) {
+		term := strings.Fields(strings.Split(r.URL.Query().Get("jql"), " ORDER BY ")[1])
+		mu.Lock()
+		sorted := append([]map[string]any(nil), issues...)
+		mu.Unlock()
+		sort.SliceStable(sorted, func(i, j int) bool {
+			a := sorted[i]["fields"].(map[string]any)[term[0]].(string)
+			b := sorted[j]["fields"].(map[string]any)[term[0]].(string)
+			if term[1] == "DESC" {
+				return a > b
+			}
+			return a < b
+		})
+		(&fakeJira{issues: sorted}).search(w, r)
+	}
+	fake := &fakeJira{routes: map[string]http.HandlerFunc{"/rest/api/3/search": search}}
+	cfg := FetchAllIssuesConfig{
+		BaseURL:         fake.start(t),
+		Project:         "PROJ",
+		MaxResults:      2,
+		Limit:           2,
+		ChunkSize:       2,
+		CursorStoreName: "test-mutation",
+	}
+
+	var keys []string
+	collect := func(out FetchAllIssuesOutput) {
+		for _, ref := range out.Refs {
+			for _, doc := range loadDocuments(t, ref) {
+				keys = append(keys, doc.ID)
+			}
+		}
+	}
+
+	out, err := runActivity(t, FetchAllIssuesActivity, cfg)
+	if err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	if out.OrderBy != checkpointOrderBy {
+		t.Errorf("OrderBy = %q, want %q", out.OrderBy, checkpointOrderBy)
+	}
+	collect(out)
+
+	// Between runs PROJ-1 is edited and PROJ-5 is created.
+	mu.Lock()
+	issues[0] = issue(1, 10)
+	issues = append(issues, issue(5, 5))
+	mu.Unlock()
+
+	cfg.Limit = 0
+	out, err = runActivity(t, FetchAllIssuesActivity, cfg)
+	if err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+	if !out.Resumed {
+		t.Error("second run did not resume")
+	}
+	collect(out)
+
+	if want := []string{"PROJ-1", "PROJ-2", "PROJ-3", "PROJ-4", "PROJ-5"}; fmt.Sprint(keys) != fmt.Sprint(want) {
+		t.Errorf("stored %v over both runs, want %v", keys, want)
+	}
+
+	cfg.OrderBy = "updated DESC"
+	if _, err := runActivity(t, FetchAllIssuesActivity, cfg); err == nil || !strings.Contains(err.Error(), "stable order") {
+		t.Errorf("error with OrderBy %q = %v, want one", cfg.OrderBy, err)
+	}
+}
+
+func TestPaginateSearchCheckpoint(t *testing.T) {
+	// The server ignores maxResults, so Limit cuts its pages short.
+	page := func(cursor bool) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			response := map[string]any{"issues": testIssues(3, false)}
+			if cursor {
+				response["nextPageToken"] = "next"
+			} else {
+				response["startAt"], _ = strconv.Atoi(r.URL.Query().Get("startAt"))
+				response["total"] = 100
+			}
+			writeJSON(w, response)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		cursor bool
+		resume string
+		limit  int
+		want   string
+	}{
+		{name: "offset full page", resume: "startAt:4", limit: 3, want: "startAt:7"},
+		{name: "offset cut page", resume: "startAt:4", limit: 2, want: "startAt:6"},
+		{name: "token full page", cursor: true, resume: "token:current", limit: 3, want: "token:next"},
+		{name: "token cut page", cursor: true, resume: "token:current", limit: 2, want: "token:current"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				page(tt.cursor)(w, r)
+			}), ClientConfig{CursorSearch: tt.cursor})
+
+			var checkpoints []string
+			search := func(ctx context.Context, query string) (int, error) {
+				pages, _, err := paginateSearch(ctx, client, query, paginateOptions{
+					Limit:  tt.limit,
+					Resume: tt.resume,
+					Checkpoint: func(next string) error {
+						checkpoints = append(checkpoints, next)
+						return nil
+					},
+				}, func([]Issue) error { return nil })
+				return pages, err
+			}
+			if _, err := runActivity(t, search, "project = PROJ"); err != nil {
+				t.Fatalf("paginateSearch: %v", err)
+			}
+			if len(checkpoints) != 1 || checkpoints[0] != tt.want {
+				t.Errorf("checkpoints = %v, want [%s]", checkpoints, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyCheckpoint(t *testing.T) {
+	tests := []struct {
+		checkpoint  string
+		cursor      bool
+		wantStartAt int
+		wantToken   string
+		wantErr     bool
+	}{
+		{checkpoint: ""},
+		{checkpoint: "startAt:40", wantStartAt: 40},
+		{checkpoint: "token:abc", cursor: true, wantToken: "abc"},
+		{checkpoint: "startAt:40", cursor: true, wantErr: true},
+		{checkpoint: "token:abc", wantErr: true},
+		{checkpoint: "startAt:-1", wantErr: true},
+		{checkpoint: "startAt:x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.checkpoint, func(t *testing.T) {
+			var params SearchJQLParams
+			err := applyCheckpoint(&params, tt.checkpoint, tt.cursor)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if params.StartAt != tt.wantStartAt || params.NextPageToken != tt.wantToken {
+				t.Errorf("position = %d, %q, want %d, %q", params.StartAt, params.NextPageToken, tt.wantStartAt, tt.wantToken)
+			}
+		})
+	}
+}
+
+func TestSearchAllJQLChunks(t *testing.T) {
+	fake := &fakeJira{issues: testIssues(5, false)}
+	out, err := runActivity(t, SearchAllJQLActivity, SearchAllJQLConfig{
+		BaseURL:    fake.start(t),
+		JQL:        "project = PROJ",
+		MaxResults: 3,
+		ChunkSize:  2,
+	})
+	if err != nil {
+		t.Fatalf("SearchAllJQLActivity: %v", err)
+	}
+
+	var sizes []int
+	for _, ref := range out.Refs {
+		sizes = append(sizes, len(loadDocuments(t, ref)))
+	}
+	if fmt.Sprint(sizes) != "[2 2 1]" {
+		t.Errorf("chunk sizes = %v, want [2 2 1]", sizes)
+	}
+	if out.Count != 5 || out.PageCount != 2 {
+		t.Errorf("count = %d in %d pages, want 5 in 2", out.Count, out.PageCount)
+	}
+}
+
+func TestLatestN(t *testing.T) {
+	fake := &fakeJira{issues: testIssues(250, true)}
+	baseURL := fake.start(t)
+
+	out, err := runActivity(t, LatestNActivity, LatestNInput{BaseURL: baseURL, Project: "PROJ", N: 150})
+	if err != nil {
+		t.Fatalf("LatestNActivity: %v", err)
+	}
+	if out.Count != 150 {
+		t.Errorf("count = %d, want 150", out.Count)
+	}
+
+	var pages []string
+	for _, search := range fake.recorded() {
+		pages = append(pages, fmt.Sprintf("%d+%d", search.StartAt, search.MaxResults))
+		if !strings.HasSuffix(search.JQL, "ORDER BY updated DESC") {
+			t.Errorf("JQL = %q, want it ordered by updated DESC", search.JQL)
+		}
+	}
+	if fmt.Sprint(pages) != "[0+100 100+50]" {
+		t.Errorf("pages = %v, want [0+100 100+50]", pages)
+	}
+
+	if _, err := runActivity(t, LatestNActivity, LatestNInput{BaseURL: baseURL, Project: "PROJ"}); err == nil {
+		t.Errorf("LatestNActivity with N = 0 succeeded")
+	}
+}
+
+func TestFetchAllIssuesRawJQL(t *testing.T) {
+	fake := &fakeJira{issues: testIssues(2, false)}
+	raw := "filter = 10001 ORDER BY rank"
+	out, err := runActivity(t, FetchAllIssuesActivity, FetchAllIssuesConfig{
+		BaseURL: fake.start(t),
+		RawJQL:  raw,
+		Project: "IGNORED",
+		OrderBy: "created ASC",
+	})
+	if err != nil {
+		t.Fatalf("FetchAllIssuesActivity: %v", err)
+	}
+	if out.EffectiveJQL != raw || out.OrderBy != "" {
+		t.Errorf("EffectiveJQL = %q, OrderBy = %q, want the raw JQL and no order", out.EffectiveJQL, out.OrderBy)
+	}
+	if searches := fake.recorded(); len(searches) != 1 || searches[0].JQL != raw {
+		t.Errorf("searches = %v, want one with the raw JQL", searches)
+	}
+}
+
+func TestFetchIssuePage(t *testing.T) {
+	fake := &fakeJira{issues: testIssues(5, false)}
+	baseURL := fake.start(t)
+
+	// fakeJira's page tokens are the offsets of the next page.
+	tests := []struct {
+		name         string
+		cursorSearch bool
+		cursor       string
+		wantKeys     string
+		wantCursor   string
+		wantErr      bool
+	}{
+		{name: "first", wantKeys: "PROJ-1,PROJ-2", wantCursor: "2"},
+		{name: "middle", cursor: "2", wantKeys: "PROJ-3,PROJ-4", wantCursor: "4"},
+		{name: "last", cursor: "4", wantKeys: "PROJ-5"},
+		{name: "invalid", cursor: "next", wantErr: true},
+		{name: "cursor first", cursorSearch: true, wantKeys: "PROJ-1,PROJ-2", wantCursor: "2"},
+		{name: "cursor middle", cursorSearch: true, cursor: "2", wantKeys: "PROJ-3,PROJ-4", wantCursor: "4"},
+		{name: "cursor last", cursorSearch: true, cursor: "4", wantKeys: "PROJ-5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(ClientConfig{BaseURL: baseURL, CursorSearch: tt.cursorSearch})
+			defer client.Close()
+
+			page, err := fetchIssuePage(context.Background(), client, "project = PROJ", 2, nil, tt.cursor)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			var keys []string
+			for _, issue := range page.Items {
+				keys = append(keys, issue.Key)
+			}
+			if got := strings.Join(keys, ","); got != tt.wantKeys {
+				t.Errorf("issues = %s, want %s", got, tt.wantKeys)
+			}
+			if page.NextCursor != tt.wantCursor || page.HasMore != (tt.wantCursor != "") {
+				t.Errorf("next = %q (more %v), want %q", page.NextCursor, page.HasMore, tt.wantCursor)
+			}
+		})
+	}
+}
+
+func TestNextPageSize(t *testing.T) {
+	tests := []struct {
+		name          string
+		current       int
+		count         int
+		responseBytes int64
+		want          int
+	}{
+		{name: "empty page", current: 10, want: 10},
+		{name: "shrinks at once", current: 50, count: 50, responseBytes: 50 << 20, want: 1},
+		{name: "grows halfway", current: 10, count: 10, responseBytes: 10 << 10, want: 55},
+		{name: "capped", current: 100, count: 100, responseBytes: 100, want: 100},
+		{name: "steady", current: 64, count: 64, responseBytes: 1 << 20, want: 64},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextPageSize(tt.current, tt.count, tt.responseBytes, 1<<20, 100); got != tt.want {
+				t.Errorf("nextPageSize = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShardByProject(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  FetchAllIssuesConfig
+		want []string
+	}{
+		{name: "single project", cfg: FetchAllIssuesConfig{Project: "A"}, want: []string{"A"}},
+		{name: "projects", cfg: FetchAllIssuesConfig{Project: "A", Projects: []string{"B", "A", "C"}}, want: []string{"A", "B", "C"}},
+		{name: "projects only", cfg: FetchAllIssuesConfig{Projects: []string{"B", "C"}}, want: []string{"B", "C"}},
+		{name: "raw JQL", cfg: FetchAllIssuesConfig{Projects: []string{"B", "C"}, RawJQL: "project = X"}, want: []string{""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Limit = 10
+			var projects []string
+			for _, shard := range ShardByProject(tt.cfg) {
+				projects = append(projects, shard.Project)
+				if shard.Limit != 10 {
+					t.Errorf("shard %s lost its limit", shard.Project)
+				}
+				if len(tt.want) > 1 && len(shard.Projects) > 0 {
+					t.Errorf("shard %s kept projects %v", shard.Project, shard.Projects)
+				}
+			}
+			if fmt.Sprint(projects) != fmt.Sprint(tt.want) {
+				t.Errorf("shards = %v, want %v", projects, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeIssues(t *testing.T) {
+	issue := func(key, updated string) Issue {
+		return Issue{Key: key, Fields: IssueFields{Updated: updated, Summary: key + "@" + updated}}
+	}
+
+	merged := MergeIssues(
+		[]Issue{issue("PROJ-10", "2024-03-01T10:00:00.000+0000"), issue("PROJ-2", "2024-03-02T10:00:00.000+0000")},
+		[]Issue{issue("PROJ-2", "2024-03-01T10:00:00.000+0000"), issue("PROJ-10", "2024-03-03T10:00:00.000+0000")},
+		[]Issue{issue("PROJ-2", "2024-03-02T10:00:00.000+0000"), issue("OPS-1", "")},
+	)
+
+	var got []string
+	for _, issue := range merged {
+		got = append(got, issue.Fields.Summary)
+	}
+	want := []string{
+		"OPS-1@",
+		"PROJ-2@2024-03-02T10:00:00.000+0000",
+		"PROJ-10@2024-03-03T10:00:00.000+0000",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("merged = %v, want %v", got, want)
+	}
+}
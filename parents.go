@@ -0,0 +1,95 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/resolute-sh/resolute-jira/jql"
+)
+
+// getParents returns the issues with the given keys, keyed by key. Issues
+// are cached for the life of the client, so each parent is fetched once
+// however many of its sub-tasks are converted. Keys of deleted or
+// inaccessible issues are left out.
+func (c *Client) getParents(ctx context.Context, keys []string) (map[string]Issue, error) {
+	const batchSize = 100
+
+	parents := make(map[string]Issue, len(keys))
+	var missing []string
+	c.parentCacheMu.Lock()
+	for _, key := range keys {
+		if parent, ok := c.parentCache[key]; ok {
+			parents[key] = parent
+		} else {
+			missing = append(missing, key)
+		}
+	}
+	c.parentCacheMu.Unlock()
+
+	for start := 0; start < len(missing); start += batchSize {
+		batch := missing[start:min(start+batchSize, len(missing))]
+
+		query := url.Values{}
+		query.Set("jql", jql.In("key", batch))
+		query.Set("fields", "summary,description")
+		query.Set("maxResults", fmt.Sprint(batchSize))
+		// Keys of deleted issues would otherwise fail the whole query.
+		query.Set("validateQuery", "warn")
+
+		var result SearchResult
+		if err := c.do(ctx, http.MethodGet, "/rest/api/3/search", query, nil, &result); err != nil {
+			return nil, fmt.Errorf("search parents: %w", err)
+		}
+
+		c.parentCacheMu.Lock()
+		if c.parentCache == nil {
+			c.parentCache = make(map[string]Issue)
+		}
+		for _, parent := range result.Issues {
+			c.parentCache[parent.Key] = parent
+			parents[parent.Key] = parent
+		}
+		c.parentCacheMu.Unlock()
+	}
+
+	return parents, nil
+}
+
+// resolveParents fetches the parents of the sub-tasks among issues.
+func (c *Client) resolveParents(ctx context.Context, issues []Issue) (map[string]Issue, error) {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, issue := range issues {
+		parent := issue.Fields.Parent
+		if !issue.Fields.IssueType.Subtask || parent == nil || seen[parent.Key] {
+			continue
+		}
+		seen[parent.Key] = true
+		keys = append(keys, parent.Key)
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	return c.getParents(ctx, keys)
+}
+
+// parentContext renders the block prepended to a sub-task's content, or ""
+// when the issue is not a sub-task or its parent is unavailable.
+func parentContext(issue Issue, parents map[string]Issue, opts DocumentOptions) string {
+	if !issue.Fields.IssueType.Subtask || issue.Fields.Parent == nil {
+		return ""
+	}
+	parent, ok := parents[issue.Fields.Parent.Key]
+	if !ok {
+		return ""
+	}
+
+	block := fmt.Sprintf("Parent: %s — %s", parent.Key, parent.Fields.Summary)
+	if description := renderADF(parent.Fields.DescriptionADF, parent.Fields.Description, opts.ADF); description != "" {
+		block += "\n" + description
+	}
+	return block
+}
@@ -0,0 +1,65 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestIncludeParentContext(t *testing.T) {
+	var searches []string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		searches = append(searches, r.URL.Query().Get("jql"))
+		writeJSON(w, map[string]any{"issues": []map[string]any{
+			testIssue("PROJ-1", map[string]any{"summary": "Checkout rework", "description": "Move checkout to the new API."}),
+		}})
+	}), ClientConfig{})
+
+	subtask := func(key, parent string) Issue {
+		return decodeIssue(t, key, map[string]any{
+			"summary":   "Part of " + parent,
+			"issuetype": map[string]any{"name": "Sub-task", "subtask": true},
+			"parent":    map[string]any{"key": parent},
+		})
+	}
+	issues := []Issue{
+		subtask("PROJ-2", "PROJ-1"),
+		subtask("PROJ-3", "PROJ-1"),
+		subtask("PROJ-4", "PROJ-9"),
+		decodeIssue(t, "PROJ-5", map[string]any{"summary": "Standalone"}),
+	}
+	opts := DocumentOptions{IncludeParentContext: true}
+
+	converted, err := issuesToDocuments(context.Background(), client, issues, opts)
+	if err != nil {
+		t.Fatalf("issuesToDocuments: %v", err)
+	}
+	if len(searches) != 1 || !strings.Contains(searches[0], "PROJ-1") || !strings.Contains(searches[0], "PROJ-9") {
+		t.Fatalf("parent searches = %q, want one for PROJ-1 and PROJ-9", searches)
+	}
+
+	want := []string{
+		"Parent: PROJ-1 — Checkout rework\nMove checkout to the new API.\n\nPart of PROJ-1",
+		"Parent: PROJ-1 — Checkout rework\nMove checkout to the new API.\n\nPart of PROJ-1",
+		// PROJ-9 is deleted or inaccessible.
+		"Part of PROJ-9",
+		"Standalone",
+	}
+	if len(converted.Docs) != len(want) {
+		t.Fatalf("got %d documents, want %d", len(converted.Docs), len(want))
+	}
+	for i, doc := range converted.Docs {
+		if doc.Content != want[i] {
+			t.Errorf("%s content = %q, want %q", doc.ID, doc.Content, want[i])
+		}
+	}
+
+	// Parents are cached on the client.
+	if _, err := issuesToDocuments(context.Background(), client, issues[:2], opts); err != nil {
+		t.Fatalf("second issuesToDocuments: %v", err)
+	}
+	if len(searches) != 1 {
+		t.Errorf("sent %d parent searches, want 1 with the cache", len(searches))
+	}
+}
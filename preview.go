@@ -0,0 +1,58 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// errPreviewRequest is returned for requests attempted in preview mode.
+var errPreviewRequest = errors.New("jira: preview mode makes no requests")
+
+// previewIssues implements FetchIssuesActivity for PreviewIssues. It runs
+// the canned issues through the same conversion and storage as a real
+// fetch, without credentials or network access, so tests and dry runs can
+// exercise a graph end to end. Search filters are ignored. Document
+// options that need more data from Jira, such as IncludeAllComments or
+// ResolveSprintNames, fail with errPreviewRequest.
+func previewIssues(ctx context.Context, input FetchIssuesInput) (FetchIssuesOutput, error) {
+	issues := make([]Issue, 0, len(input.PreviewIssues))
+	for i, raw := range input.PreviewIssues {
+		var issue Issue
+		if err := json.Unmarshal(raw, &issue); err != nil {
+			return FetchIssuesOutput{}, fmt.Errorf("decode preview issue %d: %w", i, err)
+		}
+		issues = append(issues, issue)
+	}
+
+	client := NewClient(ClientConfig{
+		Transport: RoundTripperFunc(func(*http.Request) (*http.Response, error) {
+			return nil, errPreviewRequest
+		}),
+	})
+	defer client.Close()
+
+	converted, err := issuesToDocuments(ctx, client, issues, input.DocumentOptions)
+	if err != nil {
+		return FetchIssuesOutput{}, err
+	}
+
+	ref, err := storeConversion(ctx, client, &converted, input.DocumentOptions)
+	if err != nil {
+		return FetchIssuesOutput{}, fmt.Errorf("store documents: %w", err)
+	}
+	docs := converted.Docs
+
+	issueCount, commentCount := countDocuments(docs)
+	return FetchIssuesOutput{
+		Ref:          ref,
+		Count:        len(docs),
+		Total:        len(issues),
+		IssueCount:   issueCount,
+		CommentCount: commentCount,
+		Skipped:      converted.Skipped,
+		FailedIssues: converted.Failed,
+	}, nil
+}
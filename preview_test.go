@@ -0,0 +1,95 @@
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// previewFixture returns the raw issues of testdata/preview_issues.json.
+func previewFixture(t *testing.T) [][]byte {
+	t.Helper()
+
+	data, err := os.ReadFile("testdata/preview_issues.json")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("decode fixture: %v", err)
+	}
+	issues := make([][]byte, len(raw))
+	for i, issue := range raw {
+		issues[i] = issue
+	}
+	return issues
+}
+
+func TestPreviewIssues(t *testing.T) {
+	out, err := runActivity(t, FetchIssuesActivity, FetchIssuesInput{
+		PreviewIssues: previewFixture(t),
+		DocumentOptions: DocumentOptions{
+			ExplodeComments: true,
+			LabelsAsKeys:    true,
+			ClassifierName:  DefaultClassifierName,
+		},
+	})
+	if err != nil {
+		t.Fatalf("FetchIssuesActivity: %v", err)
+	}
+	if out.Total != 2 || out.Count != 3 || out.IssueCount != 2 || out.CommentCount != 1 {
+		t.Errorf("total %d, count %d (%d issues, %d comments), want 2, 3 (2, 1)",
+			out.Total, out.Count, out.IssueCount, out.CommentCount)
+	}
+
+	docs := loadDocuments(t, out.Ref)
+	var ids []string
+	for _, doc := range docs {
+		ids = append(ids, doc.ID)
+	}
+	if got := strings.Join(ids, ","); got != "PROJ-1,PROJ-1#comment-20001,PROJ-2" {
+		t.Fatalf("documents = %s", got)
+	}
+
+	bug := docs[0]
+	wantContent := "Checkout fails for saved cards\n\nPaying with a saved card returns a 500.\n\n- Only Visa cards\n- Since the 2.3 release"
+	if bug.Content != wantContent {
+		t.Errorf("content = %q, want %q", bug.Content, wantContent)
+	}
+	for key, want := range map[string]string{
+		"status":           "In Progress",
+		"priority":         "High",
+		"assignee":         "Dana Dev",
+		"category":         "bug",
+		"label_regression": "true",
+		"created":          "2024-03-01T04:00:00Z",
+		"updated":          "2024-03-05T02:15:00Z",
+	} {
+		if got := bug.Metadata[key]; got != want {
+			t.Errorf("metadata[%s] = %q, want %q", key, got, want)
+		}
+	}
+	if comment := docs[1]; comment.Content != "Three customers reported it today." || comment.Metadata["parent_issue"] != "PROJ-1" {
+		t.Errorf("comment document = %+v", comment)
+	}
+	if story := docs[2]; story.Content != "Add Apple Pay" || story.Metadata["category"] != "feature" {
+		t.Errorf("story document = %+v", story)
+	}
+}
+
+func TestPreviewIssuesMakesNoRequests(t *testing.T) {
+	_, err := runActivity(t, FetchIssuesActivity, FetchIssuesInput{
+		PreviewIssues:   previewFixture(t),
+		DocumentOptions: DocumentOptions{IncludeAllComments: true},
+	})
+	if err == nil || !strings.Contains(err.Error(), errPreviewRequest.Error()) {
+		t.Errorf("error = %v, want %v", err, errPreviewRequest)
+	}
+
+	_, err = runActivity(t, FetchIssuesActivity, FetchIssuesInput{PreviewIssues: [][]byte{[]byte(`{"key":`)}})
+	if err == nil || errors.Is(err, errPreviewRequest) {
+		t.Errorf("error for a malformed issue = %v, want a decode error", err)
+	}
+}
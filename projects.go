@@ -0,0 +1,136 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/resolute-sh/resolute-jira/jql"
+)
+
+// ProjectCategory groups projects, e.g. "Engineering" or "Support".
+type ProjectCategory struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Component is a project component. Its Lead is usually only set when
+// the component is read from the project, not embedded in an issue.
+type Component struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Lead *User  `json:"lead,omitempty"`
+}
+
+// GetProject returns a project by key or ID. Projects are cached for the
+// life of the client; failures are not cached.
+func (c *Client) GetProject(ctx context.Context, keyOrID string) (*Project, error) {
+	c.projectCacheMu.Lock()
+	defer c.projectCacheMu.Unlock()
+
+	if project, ok := c.projectCache[keyOrID]; ok {
+		return &project, nil
+	}
+
+	var project Project
+	if err := c.do(ctx, http.MethodGet, "/rest/api/3/project/"+url.PathEscape(keyOrID), nil, nil, &project); err != nil {
+		return nil, err
+	}
+
+	if c.projectCache == nil {
+		c.projectCache = make(map[string]Project)
+	}
+	c.projectCache[keyOrID] = project
+	return &project, nil
+}
+
+// GetProjectComponents returns all components of a project with their
+// leads. They are cached for the life of the client; failures are not
+// cached.
+func (c *Client) GetProjectComponents(ctx context.Context, projectKey string) ([]Component, error) {
+	c.componentCacheMu.Lock()
+	defer c.componentCacheMu.Unlock()
+
+	if components, ok := c.componentCache[projectKey]; ok {
+		return append([]Component(nil), components...), nil
+	}
+
+	var components []Component
+	path := "/rest/api/3/project/" + url.PathEscape(projectKey) + "/components"
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &components); err != nil {
+		return nil, err
+	}
+
+	if c.componentCache == nil {
+		c.componentCache = make(map[string][]Component)
+	}
+	c.componentCache[projectKey] = components
+	return append([]Component(nil), components...), nil
+}
+
+// resolveComponentLeads fills in the lead of issue components that lack
+// one from their project's components, looking up each project once.
+func (c *Client) resolveComponentLeads(ctx context.Context, issues []Issue) error {
+	for i := range issues {
+		// Copy so the caller's issues are not modified.
+		components := append([]Component(nil), issues[i].Fields.Components...)
+		issues[i].Fields.Components = components
+		for j := range components {
+			if components[j].Lead != nil {
+				continue
+			}
+
+			project := issues[i].Fields.Project.Key
+			all, err := c.GetProjectComponents(ctx, project)
+			if err != nil {
+				return fmt.Errorf("get components of %s: %w", project, err)
+			}
+			for _, component := range all {
+				if component.ID == components[j].ID {
+					components[j].Lead = component.Lead
+					break
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ProjectLastUpdated returns the newest updated time of the issues in a
+// project, fetching a single issue with only its updated field, so a
+// scheduler can cheaply skip dormant projects. It returns the zero time
+// when the project has no issues.
+func (c *Client) ProjectLastUpdated(ctx context.Context, projectKey string) (time.Time, error) {
+	result, err := c.SearchJQLWithParams(ctx, SearchJQLParams{
+		JQL:        jql.Equals("project", projectKey) + " ORDER BY updated DESC",
+		MaxResults: 1,
+		Fields:     []string{"updated"},
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("search latest issue: %w", err)
+	}
+	if len(result.Issues) == 0 {
+		return time.Time{}, nil
+	}
+	return parseTime(result.Issues[0].Fields.Updated)
+}
+
+// resolveProjectCategories fills in the category of issues whose embedded
+// project lacks one, looking up each distinct project once.
+func (c *Client) resolveProjectCategories(ctx context.Context, issues []Issue) error {
+	for i := range issues {
+		project := &issues[i].Fields.Project
+		if project.ProjectCategory != nil || project.Key == "" {
+			continue
+		}
+
+		full, err := c.GetProject(ctx, project.Key)
+		if err != nil {
+			return fmt.Errorf("get project %s: %w", project.Key, err)
+		}
+		project.ProjectCategory = full.ProjectCategory
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestResolveProjectCategories(t *testing.T) {
+	var lookups []string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lookups = append(lookups, r.URL.Path)
+		switch r.URL.Path {
+		case "/rest/api/3/project/OPS":
+			writeJSON(w, map[string]any{"key": "OPS", "projectCategory": map[string]any{"id": "2", "name": "Support"}})
+		case "/rest/api/3/project/INT":
+			writeJSON(w, map[string]any{"key": "INT"})
+		default:
+			http.NotFound(w, r)
+		}
+	}), ClientConfig{})
+
+	issue := func(key string, project map[string]any) Issue {
+		return decodeIssue(t, key, map[string]any{"project": project})
+	}
+	issues := []Issue{
+		issue("ENG-1", map[string]any{"key": "ENG", "projectCategory": map[string]any{"id": "1", "name": "Engineering"}}),
+		issue("OPS-1", map[string]any{"key": "OPS"}),
+		issue("OPS-2", map[string]any{"key": "OPS"}),
+		issue("INT-1", map[string]any{"key": "INT"}),
+	}
+
+	converted, err := issuesToDocuments(context.Background(), client, issues, DocumentOptions{ResolveProjectCategories: true})
+	if err != nil {
+		t.Fatalf("issuesToDocuments: %v", err)
+	}
+
+	want := map[string]string{"ENG-1": "Engineering", "OPS-1": "Support", "OPS-2": "Support"}
+	for _, doc := range converted.Docs {
+		got, ok := doc.Metadata["project_category"]
+		if want, wantOK := want[doc.ID]; got != want || ok != wantOK {
+			t.Errorf("%s project_category = %q, %v, want %q, %v", doc.ID, got, ok, want, wantOK)
+		}
+	}
+	if len(lookups) != 2 {
+		t.Errorf("project lookups = %v, want one each for OPS and INT", lookups)
+	}
+}
+
+func TestProjectLastUpdated(t *testing.T) {
+	tests := []struct {
+		name   string
+		issues []map[string]any
+		want   time.Time
+	}{
+		{
+			name:   "latest issue",
+			issues: []map[string]any{testIssue("PROJ-7", map[string]any{"updated": "2024-03-05T12:30:00.000+0200"})},
+			want:   time.Date(2024, 3, 5, 10, 30, 0, 0, time.UTC),
+		},
+		{name: "no issues"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var query url.Values
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				query = r.URL.Query()
+				writeJSON(w, map[string]any{"issues": tt.issues, "total": len(tt.issues)})
+			}), ClientConfig{})
+
+			got, err := client.ProjectLastUpdated(context.Background(), "PROJ")
+			if err != nil {
+				t.Fatalf("ProjectLastUpdated: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("last updated = %s, want %s", got, tt.want)
+			}
+			if query.Get("jql") != `project = "PROJ" ORDER BY updated DESC` || query.Get("maxResults") != "1" || query.Get("fields") != "updated" {
+				t.Errorf("query = %v, want one issue of PROJ by updated DESC with only updated", query)
+			}
+		})
+	}
+}
+
+func TestComponentLeads(t *testing.T) {
+	var lookups int
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/project/PROJ/components" {
+			http.NotFound(w, r)
+			return
+		}
+		lookups++
+		writeJSON(w, []map[string]any{
+			{"id": "1", "name": "API", "lead": map[string]any{"accountId": "acc-ada"}},
+			{"id": "2", "name": "Web", "lead": map[string]any{"accountId": "acc-grace"}},
+			{"id": "3", "name": "Docs"},
+		})
+	}), ClientConfig{})
+
+	issue := func(key string, components ...map[string]any) Issue {
+		return decodeIssue(t, key, map[string]any{"project": map[string]any{"key": "PROJ"}, "components": components})
+	}
+	issues := []Issue{
+		// Leads embedded in the issue are used as they are.
+		issue("PROJ-1",
+			map[string]any{"id": "1", "name": "API", "lead": map[string]any{"accountId": "acc-linus"}},
+			map[string]any{"id": "3", "name": "Docs"}),
+		issue("PROJ-2", map[string]any{"id": "1", "name": "API"}, map[string]any{"id": "2", "name": "Web"}),
+		issue("PROJ-3", map[string]any{"id": "2", "name": "Web"}, map[string]any{"id": "1", "name": "API"}),
+		issue("PROJ-4", map[string]any{"id": "3", "name": "Docs"}),
+	}
+
+	tests := []struct {
+		name string
+		opts DocumentOptions
+		want map[string]string
+	}{
+		{
+			name: "embedded",
+			opts: DocumentOptions{IncludeComponents: true},
+			want: map[string]string{"PROJ-1": "acc-linus"},
+		},
+		{
+			name: "resolved",
+			opts: DocumentOptions{IncludeComponents: true, ResolveComponentLeads: true},
+			want: map[string]string{"PROJ-1": "acc-linus", "PROJ-2": "acc-ada,acc-grace", "PROJ-3": "acc-grace,acc-ada"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converted, err := issuesToDocuments(context.Background(), client, issues, tt.opts)
+			if err != nil {
+				t.Fatalf("issuesToDocuments: %v", err)
+			}
+			for _, doc := range converted.Docs {
+				got, ok := doc.Metadata["component_leads"]
+				if want, wantOK := tt.want[doc.ID]; got != want || ok != wantOK {
+					t.Errorf("%s component_leads = %q, %v, want %q, %v", doc.ID, got, ok, want, wantOK)
+				}
+			}
+		})
+	}
+
+	if lookups != 1 {
+		t.Errorf("fetched the components of PROJ %d times, want once", lookups)
+	}
+	if issues[2].Fields.Components[0].Lead != nil {
+		t.Errorf("resolving leads modified the caller's issues")
+	}
+}
@@ -1,4 +1,13 @@
 // Package jira provides Jira integration activities for resolute workflows.
+//
+// Activity inputs cross process boundaries as data, so options that take
+// code, such as classifiers, comment author filters, cursor stores, metrics
+// and retry policies, are named in inputs and looked up in the worker
+// process, where implementations are registered with RegisterClassifier,
+// RegisterCommentAuthorFilter, RegisterCursorStore, RegisterMetrics and
+// RegisterRetryPolicy, typically from an init function. Go code setting the
+// options directly, or building a Client with ClientConfig, passes the
+// implementations themselves.
 package jira
 
 import (
@@ -16,7 +25,34 @@ func Provider() core.Provider {
 	return core.NewProvider(ProviderName, ProviderVersion).
 		AddActivity("jira.FetchIssues", FetchIssuesActivity).
 		AddActivity("jira.FetchIssue", FetchIssueActivity).
-		AddActivity("jira.SearchJQL", SearchJQLActivity)
+		AddActivity("jira.SearchJQL", SearchJQLActivity).
+		AddActivity("jira.FetchAllIssueDocuments", FetchAllIssuesActivity).
+		AddActivity("jira.SearchAllJQLDocuments", SearchAllJQLActivity).
+		AddActivity("jira.FetchSprints", FetchSprintsActivity).
+		AddActivity("jira.FetchIssueContext", FetchIssueContextActivity).
+		AddActivity("jira.CountJQL", CountJQLActivity).
+		AddActivity("jira.FetchIssuesSince", FetchIssuesSinceActivity).
+		AddActivity("jira.LatestN", LatestNActivity).
+		AddActivity("jira.FetchEditMeta", FetchEditMetaActivity).
+		AddActivity("jira.Mine", MineActivity).
+		AddActivity("jira.BulkAddLabel", BulkAddLabelActivity).
+		AddActivity("jira.FetchSprint", FetchSprintActivity).
+		AddActivity("jira.FetchEpic", FetchEpicActivity).
+		AddActivity("jira.AddComment", AddCommentActivity).
+		AddActivity("jira.CreateIssue", CreateIssueActivity).
+		AddActivity("jira.ListFields", ListFieldsActivity).
+		AddActivity("jira.CreateIssueLink", CreateIssueLinkActivity).
+		AddActivity("jira.FetchIssueLinkTypes", FetchIssueLinkTypesActivity).
+		AddActivity("jira.Facet", FacetActivity).
+		AddActivity("jira.CommentAndTransition", CommentAndTransitionActivity).
+		AddActivity("jira.UpdateComment", UpdateCommentActivity).
+		AddActivity("jira.AddWorklog", AddWorklogActivity).
+		AddActivity("jira.SetWatchers", SetWatchersActivity).
+		AddActivity("jira.FetchBoardColumns", FetchBoardColumnsActivity).
+		AddActivity("jira.FetchIssuesModifiedByWebhookReplay", FetchIssuesModifiedByWebhookReplayActivity).
+		AddActivity("jira.SetFixVersions", SetFixVersionsActivity).
+		AddActivity("jira.FetchEpicBundle", FetchEpicBundleActivity).
+		AddActivity("jira.FetchSubtasks", FetchSubtasksActivity)
 }
 
 // RegisterActivities registers all Jira activities with a Temporal worker.
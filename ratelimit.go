@@ -0,0 +1,81 @@
+package jira
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimitInfo is the rate-limit state Jira last reported in the
+// X-RateLimit-* response headers. The zero value means no response carried
+// them yet.
+type RateLimitInfo struct {
+	// Limit is the size of the request budget, -1 when not reported.
+	Limit int
+
+	// Remaining is the budget left in the current window, -1 when not
+	// reported.
+	Remaining int
+
+	// Reset is when the budget refills, zero when not reported.
+	Reset time.Time
+
+	// NearLimit is set when Jira flags that less than 20% of the budget
+	// remains.
+	NearLimit bool
+
+	// ObservedAt is when the headers were received.
+	ObservedAt time.Time
+}
+
+// LastRateLimit returns the rate-limit state from the most recent response
+// that reported one.
+func (c *Client) LastRateLimit() RateLimitInfo {
+	c.rateLimitMu.Lock()
+	defer c.rateLimitMu.Unlock()
+	return c.rateLimit
+}
+
+// recordRateLimit stores the rate-limit headers of a response, if any.
+func (c *Client) recordRateLimit(header http.Header) {
+	info, ok := parseRateLimit(header)
+	if !ok {
+		return
+	}
+
+	c.rateLimitMu.Lock()
+	c.rateLimit = info
+	c.rateLimitMu.Unlock()
+}
+
+// parseRateLimit reads the X-RateLimit-* headers, reporting false when none
+// are present.
+func parseRateLimit(header http.Header) (RateLimitInfo, bool) {
+	limit := header.Get("X-RateLimit-Limit")
+	remaining := header.Get("X-RateLimit-Remaining")
+	reset := header.Get("X-RateLimit-Reset")
+	nearLimit := header.Get("X-RateLimit-NearLimit")
+	if limit == "" && remaining == "" && reset == "" && nearLimit == "" {
+		return RateLimitInfo{}, false
+	}
+
+	info := RateLimitInfo{
+		Limit:      headerInt(limit),
+		Remaining:  headerInt(remaining),
+		NearLimit:  strings.EqualFold(nearLimit, "true"),
+		ObservedAt: time.Now(),
+	}
+	if t, err := time.Parse(time.RFC3339, reset); err == nil {
+		info.Reset = t
+	}
+	return info, true
+}
+
+func headerInt(value string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return -1
+	}
+	return n
+}
@@ -0,0 +1,59 @@
+package jira
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	reset := time.Date(2024, 3, 1, 10, 5, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   RateLimitInfo
+		wantOK bool
+	}{
+		{name: "none"},
+		{
+			name:   "near limit only",
+			header: map[string]string{"X-RateLimit-NearLimit": "True"},
+			want:   RateLimitInfo{Limit: -1, Remaining: -1, NearLimit: true},
+			wantOK: true,
+		},
+		{
+			name:   "malformed values",
+			header: map[string]string{"X-RateLimit-Limit": "lots", "X-RateLimit-Remaining": " 7 ", "X-RateLimit-Reset": "in 5 minutes"},
+			want:   RateLimitInfo{Limit: -1, Remaining: 7},
+			wantOK: true,
+		},
+		{
+			name:   "reset",
+			header: map[string]string{"X-RateLimit-Reset": reset.Format(time.RFC3339)},
+			want:   RateLimitInfo{Limit: -1, Remaining: -1, Reset: reset},
+			wantOK: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for name, value := range tt.header {
+				header.Set(name, value)
+			}
+
+			got, ok := parseRateLimit(header)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && got.ObservedAt.IsZero() {
+				t.Errorf("ObservedAt not set")
+			}
+			got.ObservedAt = time.Time{}
+			if got != tt.want {
+				t.Errorf("info = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package jira
+
+import (
+	"fmt"
+	"sync"
+)
+
+// registry holds the implementations of one kind of option, such as
+// classifiers, under the names activity inputs refer to them by.
+type registry[T any] struct {
+	kind string
+
+	mu      sync.RWMutex
+	entries map[string]T
+}
+
+func newRegistry[T any](kind string) *registry[T] {
+	return &registry[T]{kind: kind, entries: make(map[string]T)}
+}
+
+func (r *registry[T]) register(name string, v T) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[name] = v
+}
+
+// lookup returns the entry registered under name, the zero T for an empty
+// name.
+func (r *registry[T]) lookup(name string) (T, error) {
+	var zero T
+	if name == "" {
+		return zero, nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	v, ok := r.entries[name]
+	if !ok {
+		return zero, fmt.Errorf("unknown %s %q", r.kind, name)
+	}
+	return v, nil
+}
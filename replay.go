@@ -0,0 +1,154 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/resolute-sh/resolute/core"
+)
+
+// FetchIssuesModifiedByWebhookReplayInput is the input for
+// FetchIssuesModifiedByWebhookReplayActivity.
+type FetchIssuesModifiedByWebhookReplayInput struct {
+	BaseURL  string
+	Email    string
+	APIToken string
+	Project  string
+	Projects []string
+
+	// From and To bound the missed window, both inclusive.
+	From time.Time
+	To   time.Time
+
+	// DetectDeletions also reports the KnownKeys that no longer exist as
+	// DeletedKeys. Jira does not record when an issue was deleted, so this
+	// covers deletions at any time, not only within the window.
+	DetectDeletions bool
+	KnownKeys       []string
+
+	MaxResults int // per page, default 100
+
+	DocumentOptions
+	ClientOptions
+}
+
+// FetchIssuesModifiedByWebhookReplayOutput is the output of
+// FetchIssuesModifiedByWebhookReplayActivity.
+type FetchIssuesModifiedByWebhookReplayOutput struct {
+	Ref          core.DataRef
+	Count        int
+	IssueCount   int
+	CommentCount int
+	DeletedKeys  []string
+
+	// Skipped lists the keys of issues dropped for thin content.
+	Skipped []string
+
+	// FailedIssues lists the issues left out because they failed to
+	// convert, with ContinueOnError.
+	FailedIssues []FailedIssue
+}
+
+// FetchIssuesModifiedByWebhookReplayActivity replays the events missed
+// during a webhook outage: it fetches the issues updated between From and
+// To and converts them to the same documents the webhook path produces,
+// each issue once in its latest state, so upserting them makes the index
+// converge.
+//
+// To use it, have the webhook consumer record the time of the last event
+// it processed. When a gap is detected, e.g. on restart or after a
+// delivery failure alert, run this activity with From set to that time and
+// To set to when webhooks resumed, then upsert the documents and delete
+// the DeletedKeys. Overlapping the window with events the webhook path did
+// deliver is harmless, as documents are keyed by issue.
+func FetchIssuesModifiedByWebhookReplayActivity(ctx context.Context, input FetchIssuesModifiedByWebhookReplayInput) (FetchIssuesModifiedByWebhookReplayOutput, error) {
+	if input.From.IsZero() || input.To.IsZero() || input.To.Before(input.From) {
+		return FetchIssuesModifiedByWebhookReplayOutput{}, fmt.Errorf("from and to must be set, with from not after to")
+	}
+
+	client, err := input.ClientOptions.newClient(ClientConfig{
+		BaseURL:  input.BaseURL,
+		Email:    input.Email,
+		APIToken: input.APIToken,
+	})
+	if err != nil {
+		return FetchIssuesModifiedByWebhookReplayOutput{}, err
+	}
+	defer client.Close()
+
+	// JQL dates have minute precision, so widen the window to whole
+	// minutes and trim it exactly below.
+	from := input.From.Truncate(time.Minute)
+	to := input.To.Truncate(time.Minute).Add(time.Minute)
+	query, err := projectQuery{
+		Project:  input.Project,
+		Projects: input.Projects,
+		Since:    &from,
+		Until:    &to,
+		OrderBy:  "updated ASC, key ASC",
+	}.build(ctx, client)
+	if err != nil {
+		return FetchIssuesModifiedByWebhookReplayOutput{}, err
+	}
+
+	// An issue updated while paging can show up on two pages; keep its
+	// latest state only.
+	latest := make(map[string]int)
+	var issues []Issue
+	_, _, err = paginateSearch(ctx, client, query, paginateOptions{PageSize: input.MaxResults}, func(page []Issue) error {
+		for _, issue := range page {
+			updated, err := parseTime(issue.Fields.Updated)
+			if err == nil && (updated.Before(input.From) || updated.After(input.To)) {
+				continue
+			}
+			if i, ok := latest[issue.Key]; ok {
+				issues[i] = issue
+				continue
+			}
+			latest[issue.Key] = len(issues)
+			issues = append(issues, issue)
+		}
+		return nil
+	})
+	if err != nil {
+		return FetchIssuesModifiedByWebhookReplayOutput{}, err
+	}
+
+	converted, err := issuesToDocuments(ctx, client, issues, input.DocumentOptions)
+	if err != nil {
+		return FetchIssuesModifiedByWebhookReplayOutput{}, err
+	}
+
+	var deleted []string
+	if input.DetectDeletions {
+		deleted, err = client.FindDeletedKeys(ctx, input.KnownKeys)
+		if err != nil {
+			return FetchIssuesModifiedByWebhookReplayOutput{}, fmt.Errorf("reconcile deletions: %w", err)
+		}
+	}
+
+	ref, err := storeConversion(ctx, client, &converted, input.DocumentOptions)
+	if err != nil {
+		return FetchIssuesModifiedByWebhookReplayOutput{}, fmt.Errorf("store documents: %w", err)
+	}
+	docs := converted.Docs
+
+	issueCount, commentCount := countDocuments(docs)
+	return FetchIssuesModifiedByWebhookReplayOutput{
+		Ref:          ref,
+		Count:        len(docs),
+		IssueCount:   issueCount,
+		CommentCount: commentCount,
+		DeletedKeys:  deleted,
+		Skipped:      converted.Skipped,
+		FailedIssues: converted.Failed,
+	}, nil
+}
+
+// FetchIssuesModifiedByWebhookReplay creates a node for catching up on
+// issues changed during a webhook outage.
+func FetchIssuesModifiedByWebhookReplay(input FetchIssuesModifiedByWebhookReplayInput) *core.Node[FetchIssuesModifiedByWebhookReplayInput, FetchIssuesModifiedByWebhookReplayOutput] {
+	return core.NewNode("jira.FetchIssuesModifiedByWebhookReplay", FetchIssuesModifiedByWebhookReplayActivity, input).
+		WithTimeout(30 * time.Minute)
+}
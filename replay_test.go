@@ -0,0 +1,67 @@
+package jira
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFetchIssuesModifiedByWebhookReplayActivity(t *testing.T) {
+	at := func(s string) string {
+		t, _ := time.Parse(time.RFC3339Nano, s)
+		return t.Format("2006-01-02T15:04:05.000-0700")
+	}
+	// Jira's minute-wide window also returns issues just outside the gap;
+	// PROJ-2 shows up twice after an edit while paging.
+	fake := &fakeJira{issues: []map[string]any{
+		testIssue("PROJ-1", map[string]any{"updated": at("2024-03-01T10:00:10Z")}),
+		testIssue("PROJ-2", map[string]any{"updated": at("2024-03-01T10:00:30Z"), "summary": "before"}),
+		testIssue("PROJ-3", map[string]any{"updated": at("2024-03-01T10:02:00Z")}),
+		testIssue("PROJ-2", map[string]any{"updated": at("2024-03-01T10:03:00Z"), "summary": "after"}),
+		testIssue("PROJ-4", map[string]any{"updated": at("2024-03-01T10:05:50Z")}),
+	}}
+	baseURL := fake.start(t)
+
+	out, err := runActivity(t, FetchIssuesModifiedByWebhookReplayActivity, FetchIssuesModifiedByWebhookReplayInput{
+		BaseURL:         baseURL,
+		Project:         "PROJ",
+		From:            time.Date(2024, 3, 1, 10, 0, 20, 0, time.UTC),
+		To:              time.Date(2024, 3, 1, 10, 5, 40, 0, time.UTC),
+		DetectDeletions: true,
+		KnownKeys:       []string{"PROJ-1", "PROJ-9"},
+		MaxResults:      2,
+	})
+	if err != nil {
+		t.Fatalf("FetchIssuesModifiedByWebhookReplayActivity: %v", err)
+	}
+
+	docs := loadDocuments(t, out.Ref)
+	var ids []string
+	for _, doc := range docs {
+		ids = append(ids, doc.ID)
+	}
+	if got := strings.Join(ids, ","); got != "PROJ-2,PROJ-3" {
+		t.Fatalf("documents = %s, want PROJ-2,PROJ-3", got)
+	}
+	if !strings.HasPrefix(docs[0].Content, "after") {
+		t.Errorf("PROJ-2 content = %q, want its latest state", docs[0].Content)
+	}
+	if strings.Join(out.DeletedKeys, ",") != "PROJ-9" {
+		t.Errorf("deleted = %v, want [PROJ-9]", out.DeletedKeys)
+	}
+
+	jql := fake.recorded()[0].JQL
+	if !strings.Contains(jql, `updated >= "2024-03-01 10:00"`) || !strings.Contains(jql, `updated <= "2024-03-01 10:06"`) {
+		t.Errorf("jql = %s, want the window widened to whole minutes", jql)
+	}
+
+	_, err = runActivity(t, FetchIssuesModifiedByWebhookReplayActivity, FetchIssuesModifiedByWebhookReplayInput{
+		BaseURL: baseURL,
+		Project: "PROJ",
+		From:    time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+		To:      time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err == nil {
+		t.Errorf("replay with from after to succeeded")
+	}
+}
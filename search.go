@@ -0,0 +1,133 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/resolute-sh/resolute/core"
+)
+
+// ApproximateCount returns Jira's estimate of the number of issues matching
+// jql. The value is an estimate and may lag recent changes; use it for
+// "about N results" displays, not for exact accounting.
+func (c *Client) ApproximateCount(ctx context.Context, jql string) (int, error) {
+	body := map[string]string{"jql": jql}
+
+	var result struct {
+		Count int `json:"count"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/rest/api/3/search/approximate-count", nil, body, &result); err != nil {
+		return 0, err
+	}
+
+	return result.Count, nil
+}
+
+// JQL validation levels for ValidateJQL.
+const (
+	// JQLValidateStrict reports every problem as an error.
+	JQLValidateStrict = "strict"
+
+	// JQLValidateWarn reports problems that do not stop the query from
+	// running, such as a reference to an archived value, as warnings.
+	JQLValidateWarn = "warn"
+
+	// JQLValidateNone only checks the syntax.
+	JQLValidateNone = "none"
+)
+
+// JQLValidation is the result of validating a JQL query.
+type JQLValidation struct {
+	Query    string   `json:"query"`
+	Errors   []string `json:"errors,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Valid reports whether the query can run, possibly with warnings.
+func (v JQLValidation) Valid() bool {
+	return len(v.Errors) == 0
+}
+
+// ValidateJQL parses query with Jira's JQL parser at the given validation
+// level, default JQLValidateStrict. An invalid query is not an error: it
+// is reported in the result's Errors, and problems that would not stop it
+// from running are reported in Warnings.
+func (c *Client) ValidateJQL(ctx context.Context, query, level string) (*JQLValidation, error) {
+	switch level {
+	case "":
+		level = JQLValidateStrict
+	case JQLValidateStrict, JQLValidateWarn, JQLValidateNone:
+	default:
+		return nil, fmt.Errorf("unknown validation level %q", level)
+	}
+
+	params := url.Values{}
+	params.Set("validation", level)
+	body := map[string][]string{"queries": {query}}
+
+	var result struct {
+		Queries []JQLValidation `json:"queries"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/rest/api/3/jql/parse", params, body, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Queries) == 0 {
+		return nil, fmt.Errorf("parse jql: empty response")
+	}
+
+	return &result.Queries[0], nil
+}
+
+// CountJQLInput is the input for CountJQLActivity.
+type CountJQLInput struct {
+	BaseURL  string
+	Email    string
+	APIToken string
+	JQL      string
+
+	ClientOptions
+}
+
+// CountJQLOutput is the output of CountJQLActivity.
+type CountJQLOutput struct {
+	Count int
+
+	// Approximate is true when Count is an estimate, which is the case in
+	// cursor-search mode where the search endpoint reports no total.
+	Approximate bool
+}
+
+// CountJQLActivity counts the issues matching a JQL query.
+func CountJQLActivity(ctx context.Context, input CountJQLInput) (CountJQLOutput, error) {
+	client, err := input.ClientOptions.newClient(ClientConfig{
+		BaseURL:  input.BaseURL,
+		Email:    input.Email,
+		APIToken: input.APIToken,
+	})
+	if err != nil {
+		return CountJQLOutput{}, err
+	}
+	defer client.Close()
+
+	if client.cursorSearch {
+		count, err := client.ApproximateCount(ctx, input.JQL)
+		if err != nil {
+			return CountJQLOutput{}, fmt.Errorf("approximate count: %w", err)
+		}
+		return CountJQLOutput{Count: count, Approximate: true}, nil
+	}
+
+	result, err := client.SearchJQL(ctx, input.JQL, 1)
+	if err != nil {
+		return CountJQLOutput{}, fmt.Errorf("search jql: %w", err)
+	}
+
+	return CountJQLOutput{Count: result.Total}, nil
+}
+
+// CountJQL creates a node for counting issues matching a JQL query.
+func CountJQL(input CountJQLInput) *core.Node[CountJQLInput, CountJQLOutput] {
+	return core.NewNode("jira.CountJQL", CountJQLActivity, input)
+}
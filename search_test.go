@@ -0,0 +1,328 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSearchJQLPaging(t *testing.T) {
+	fake := &fakeJira{issues: testIssues(3, false)}
+	baseURL := fake.start(t)
+
+	tests := []struct {
+		name      string
+		cursor    bool
+		params    SearchJQLParams
+		wantKeys  string
+		wantTotal int
+		wantToken string
+		wantLast  bool
+	}{
+		{name: "offset", params: SearchJQLParams{StartAt: 1, MaxResults: 1}, wantKeys: "[PROJ-2]", wantTotal: 3},
+		{name: "cursor first page", cursor: true, params: SearchJQLParams{MaxResults: 2}, wantKeys: "[PROJ-1 PROJ-2]", wantToken: "2"},
+		{name: "cursor last page", cursor: true, params: SearchJQLParams{NextPageToken: "2", MaxResults: 2}, wantKeys: "[PROJ-3]", wantLast: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(ClientConfig{BaseURL: baseURL, CursorSearch: tt.cursor})
+			defer client.Close()
+
+			tt.params.JQL = "project = PROJ"
+			result, err := client.SearchJQLWithParams(context.Background(), tt.params)
+			if err != nil {
+				t.Fatalf("SearchJQLWithParams: %v", err)
+			}
+			var keys []string
+			for _, issue := range result.Issues {
+				keys = append(keys, issue.Key)
+			}
+			if fmt.Sprint(keys) != tt.wantKeys {
+				t.Errorf("issues = %v, want %s", keys, tt.wantKeys)
+			}
+			if result.Total != tt.wantTotal || result.NextPageToken != tt.wantToken || result.IsLast != tt.wantLast {
+				t.Errorf("total = %d, token = %q, last = %v, want %d, %q, %v",
+					result.Total, result.NextPageToken, result.IsLast, tt.wantTotal, tt.wantToken, tt.wantLast)
+			}
+			if result.ResponseBytes == 0 {
+				t.Errorf("ResponseBytes not counted")
+			}
+		})
+	}
+}
+
+func TestSearchJQLNames(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Jira sends the names after the issues.
+		io.WriteString(w, `{"startAt":0,"total":2,"issues":[{"key":"PROJ-1","fields":{}},{"key":"PROJ-2","fields":{}}],`+
+			`"names":{"customfield_10016":"Story Points"}}`)
+	}), ClientConfig{})
+
+	result, err := client.SearchJQLWithParams(context.Background(), SearchJQLParams{JQL: "project = PROJ", Expand: []string{"names"}})
+	if err != nil {
+		t.Fatalf("SearchJQLWithParams: %v", err)
+	}
+	for _, issue := range result.Issues {
+		if issue.Names["customfield_10016"] != "Story Points" {
+			t.Errorf("%s names = %v, want the page's names", issue.Key, issue.Names)
+		}
+	}
+}
+
+func TestSearchJQLErrors(t *testing.T) {
+	var requests atomic.Int32
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"errorMessages":["The search could not be completed."]}`)
+	}), ClientConfig{})
+
+	tests := []struct {
+		name         string
+		params       SearchJQLParams
+		wantErr      error
+		wantAPIError bool
+		wantRequest  bool
+	}{
+		{name: "unbounded", params: SearchJQLParams{JQL: "ORDER BY created DESC"}, wantErr: ErrUnboundedJQL},
+		{name: "empty", params: SearchJQLParams{}, wantErr: ErrUnboundedJQL},
+		{name: "invalid field", params: SearchJQLParams{JQL: "project = PROJ", Fields: []string{"summary,status"}}},
+		{name: "deep offset", params: SearchJQLParams{JQL: "project = PROJ", StartAt: 10000}, wantErr: ErrDeepPagination, wantRequest: true},
+		{name: "first page", params: SearchJQLParams{JQL: "project = PROJ"}, wantAPIError: true, wantRequest: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := requests.Load()
+			_, err := client.SearchJQLWithParams(context.Background(), tt.params)
+			if err == nil {
+				t.Fatalf("SearchJQLWithParams succeeded")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (errors.Is(err, ErrDeepPagination) || errors.Is(err, ErrUnboundedJQL)) {
+				t.Errorf("error = %v, want neither sentinel", err)
+			}
+			var apiErr *APIError
+			if errors.As(err, &apiErr) != tt.wantAPIError {
+				t.Errorf("error = %v, want an APIError %v", err, tt.wantAPIError)
+			}
+			if sent := requests.Load() > before; sent != tt.wantRequest {
+				t.Errorf("request sent = %v, want %v", sent, tt.wantRequest)
+			}
+		})
+	}
+}
+
+func TestSearchJQLFields(t *testing.T) {
+	var rawQuery string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rawQuery = r.URL.RawQuery
+		writeJSON(w, map[string]any{"issues": []any{}, "total": 0})
+	}), ClientConfig{})
+
+	_, err := client.SearchJQLWithParams(context.Background(), SearchJQLParams{
+		JQL:    "project = PROJ",
+		Fields: []string{"*all", "-comment"},
+	})
+	if err != nil {
+		t.Fatalf("SearchJQLWithParams: %v", err)
+	}
+	if !strings.Contains(rawQuery, "fields=%2Aall%2C-comment") {
+		t.Errorf("query = %q, want fields=%%2Aall%%2C-comment", rawQuery)
+	}
+}
+
+func TestSearchJQLProperties(t *testing.T) {
+	var properties string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		properties = r.URL.Query().Get("properties")
+		issue := testIssue("PROJ-1", nil)
+		issue["properties"] = map[string]any{
+			"sync.state": map[string]any{"rev": 3, "hash": "abc"},
+			"sync.owner": "etl",
+		}
+		writeJSON(w, map[string]any{"issues": []any{issue, testIssue("PROJ-2", nil)}, "total": 2})
+	}), ClientConfig{})
+
+	result, err := client.SearchJQLWithParams(context.Background(), SearchJQLParams{
+		JQL:               "project = PROJ",
+		RequestProperties: []string{"sync.state", "sync.owner"},
+	})
+	if err != nil {
+		t.Fatalf("SearchJQLWithParams: %v", err)
+	}
+	if properties != "sync.state,sync.owner" {
+		t.Errorf("properties = %q, want sync.state,sync.owner", properties)
+	}
+
+	var state struct {
+		Rev  int    `json:"rev"`
+		Hash string `json:"hash"`
+	}
+	if err := json.Unmarshal(result.Issues[0].Properties["sync.state"], &state); err != nil || state.Rev != 3 || state.Hash != "abc" {
+		t.Errorf("sync.state = %+v, %v, want rev 3, hash abc", state, err)
+	}
+	if owner := string(result.Issues[0].Properties["sync.owner"]); owner != `"etl"` {
+		t.Errorf("sync.owner = %s, want \"etl\"", owner)
+	}
+	if result.Issues[1].Properties != nil {
+		t.Errorf("PROJ-2 properties = %v, want none", result.Issues[1].Properties)
+	}
+}
+
+func TestCountJQL(t *testing.T) {
+	var body map[string]string
+	fake := &fakeJira{
+		issues: testIssues(7, false),
+		routes: map[string]http.HandlerFunc{
+			"/rest/api/3/search/approximate-count": func(w http.ResponseWriter, r *http.Request) {
+				json.NewDecoder(r.Body).Decode(&body)
+				writeJSON(w, map[string]any{"count": 42})
+			},
+		},
+	}
+	baseURL := fake.start(t)
+
+	tests := []struct {
+		name            string
+		cursor          bool
+		wantCount       int
+		wantApproximate bool
+	}{
+		{name: "offset", wantCount: 7},
+		{name: "cursor", cursor: true, wantCount: 42, wantApproximate: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := runActivity(t, CountJQLActivity, CountJQLInput{
+				BaseURL:       baseURL,
+				JQL:           "project = PROJ",
+				ClientOptions: ClientOptions{CursorSearch: tt.cursor},
+			})
+			if err != nil {
+				t.Fatalf("CountJQLActivity: %v", err)
+			}
+			if out.Count != tt.wantCount || out.Approximate != tt.wantApproximate {
+				t.Errorf("count = %d (approximate %v), want %d (%v)", out.Count, out.Approximate, tt.wantCount, tt.wantApproximate)
+			}
+		})
+	}
+	if body["jql"] != "project = PROJ" {
+		t.Errorf("approximate count body = %v, want the JQL", body)
+	}
+}
+
+func TestValidateJQL(t *testing.T) {
+	var level string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		level = r.URL.Query().Get("validation")
+		writeJSON(w, map[string]any{"queries": []map[string]any{{
+			"query":    "project = PROJ AND fixVersion = 1.0",
+			"warnings": []string{"The value '1.0' is archived."},
+		}}})
+	}), ClientConfig{})
+
+	tests := []struct {
+		level     string
+		wantLevel string
+		wantErr   bool
+	}{
+		{level: "", wantLevel: JQLValidateStrict},
+		{level: JQLValidateWarn, wantLevel: JQLValidateWarn},
+		{level: "lenient", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			level = ""
+			validation, err := client.ValidateJQL(context.Background(), "project = PROJ AND fixVersion = 1.0", tt.level)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if level != tt.wantLevel {
+				t.Errorf("validation = %q, want %q", level, tt.wantLevel)
+			}
+			if err == nil && (!validation.Valid() || len(validation.Warnings) != 1) {
+				t.Errorf("validation = %+v, want valid with a warning", validation)
+			}
+		})
+	}
+}
+
+// searchResponse returns a search response of n issues with large ADF
+// descriptions.
+func searchResponse(b *testing.B, n int) []byte {
+	b.Helper()
+
+	issues := make([]map[string]any, n)
+	for i := range issues {
+		issues[i] = testIssue(fmt.Sprintf("PROJ-%d", i+1), map[string]any{"description": adfParagraphs(500)})
+	}
+	data, err := json.Marshal(map[string]any{"startAt": 0, "maxResults": n, "total": n, "issues": issues})
+	if err != nil {
+		b.Fatal(err)
+	}
+	return data
+}
+
+// BenchmarkSearchDecode compares decoding a search page whole with
+// streaming its issues. Both allocate about the same in total; the
+// retained-B metric is the heap still live once a page is decoded, which
+// streaming keeps to one issue at a time.
+func BenchmarkSearchDecode(b *testing.B) {
+	data := searchResponse(b, 100)
+
+	decoders := []struct {
+		name   string
+		decode func() (any, error)
+	}{
+		{name: "whole", decode: func() (any, error) {
+			var result SearchResult
+			err := json.Unmarshal(data, &result)
+			return &result, err
+		}},
+		{name: "streaming", decode: func() (any, error) {
+			var result SearchResult
+			err := decodeSearchResult(bytes.NewReader(data), &result, func(Issue) error { return nil })
+			return &result, err
+		}},
+	}
+
+	for _, d := range decoders {
+		b.Run(d.name, func(b *testing.B) {
+			var before, after runtime.MemStats
+			runtime.GC()
+			runtime.ReadMemStats(&before)
+			result, err := d.decode()
+			if err != nil {
+				b.Fatal(err)
+			}
+			runtime.GC()
+			runtime.ReadMemStats(&after)
+			runtime.KeepAlive(result)
+
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+			b.ResetTimer()
+			for range b.N {
+				if _, err := d.decode(); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(int64(after.HeapAlloc)-int64(before.HeapAlloc)), "retained-B")
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+// SLAStatus is the state of a Jira Service Management SLA field.
+type SLAStatus struct {
+	Name            string
+	Breached        bool
+	RemainingMillis int64 // negative once breached
+	Ongoing         bool  // false when read from the last completed cycle
+}
+
+// slaCycle is an ongoing or completed cycle of a JSM SLA field.
+type slaCycle struct {
+	Breached      bool `json:"breached"`
+	RemainingTime struct {
+		Millis int64 `json:"millis"`
+	} `json:"remainingTime"`
+}
+
+// DecodeSLA decodes the value of a JSM SLA custom field, such as "Time to
+// resolution", from its ongoing cycle, or from its last completed cycle
+// when none is ongoing. It reports false for empty values, values of other
+// shapes, and SLAs with no cycle yet.
+func DecodeSLA(raw json.RawMessage) (SLAStatus, bool) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || raw[0] != '{' {
+		return SLAStatus{}, false
+	}
+
+	var field struct {
+		Name            string     `json:"name"`
+		OngoingCycle    *slaCycle  `json:"ongoingCycle"`
+		CompletedCycles []slaCycle `json:"completedCycles"`
+	}
+	if err := json.Unmarshal(raw, &field); err != nil {
+		return SLAStatus{}, false
+	}
+
+	status := SLAStatus{Name: field.Name}
+	cycle := field.OngoingCycle
+	if cycle != nil {
+		status.Ongoing = true
+	} else if n := len(field.CompletedCycles); n > 0 {
+		cycle = &field.CompletedCycles[n-1]
+	} else {
+		return SLAStatus{}, false
+	}
+	status.Breached = cycle.Breached
+	status.RemainingMillis = cycle.RemainingTime.Millis
+
+	return status, true
+}
+
+// slaMetadata returns the sla_breached and sla_remaining_ms metadata of an
+// SLA field value, or nil when it holds no SLA.
+func slaMetadata(raw json.RawMessage) map[string]string {
+	status, ok := DecodeSLA(raw)
+	if !ok {
+		return nil
+	}
+	return map[string]string{
+		"sla_breached":     strconv.FormatBool(status.Breached),
+		"sla_remaining_ms": strconv.FormatInt(status.RemainingMillis, 10),
+	}
+}
@@ -0,0 +1,40 @@
+package jira
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDecodeSLA(t *testing.T) {
+	tests := []struct {
+		name   string
+		raw    string
+		want   SLAStatus
+		wantOK bool
+	}{
+		{
+			name:   "ongoing",
+			raw:    `{"name":"Time to first response","ongoingCycle":{"breached":false,"remainingTime":{"millis":600000}}}`,
+			want:   SLAStatus{Name: "Time to first response", RemainingMillis: 600000, Ongoing: true},
+			wantOK: true,
+		},
+		{
+			name:   "last completed cycle",
+			raw:    `{"name":"Time to resolution","completedCycles":[{"breached":false,"remainingTime":{"millis":1000}},{"breached":true,"remainingTime":{"millis":-3600000}}]}`,
+			want:   SLAStatus{Name: "Time to resolution", Breached: true, RemainingMillis: -3600000},
+			wantOK: true,
+		},
+		{name: "no cycle", raw: `{"name":"Time to resolution","completedCycles":[]}`},
+		{name: "null", raw: `null`},
+		{name: "other shape", raw: `"2024-03-01"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := DecodeSLA(json.RawMessage(tt.raw))
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("DecodeSLA = %+v, %v, want %+v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,327 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	transform "github.com/resolute-sh/resolute-transform"
+	"github.com/resolute-sh/resolute/core"
+)
+
+// Sprint states accepted by ListSprints.
+const (
+	SprintStateActive = "active"
+	SprintStateClosed = "closed"
+	SprintStateFuture = "future"
+)
+
+// Sprint represents an agile sprint.
+type Sprint struct {
+	ID            int    `json:"id"`
+	Self          string `json:"self"`
+	State         string `json:"state"`
+	Name          string `json:"name"`
+	StartDate     string `json:"startDate"`
+	EndDate       string `json:"endDate"`
+	CompleteDate  string `json:"completeDate"`
+	OriginBoardID int    `json:"originBoardId"`
+	Goal          string `json:"goal"`
+}
+
+// sprintPage is a page of the agile sprint listing.
+type sprintPage struct {
+	StartAt    int      `json:"startAt"`
+	MaxResults int      `json:"maxResults"`
+	IsLast     bool     `json:"isLast"`
+	Values     []Sprint `json:"values"`
+}
+
+// ListSprints returns all sprints of a board in the given states, following
+// pagination. When states is empty, sprints in every state are returned.
+func (c *Client) ListSprints(ctx context.Context, boardID int, states []string) ([]Sprint, error) {
+	if len(states) == 0 {
+		states = []string{SprintStateActive, SprintStateClosed, SprintStateFuture}
+	}
+
+	path := fmt.Sprintf("/rest/agile/1.0/board/%d/sprint", boardID)
+
+	var sprints []Sprint
+	startAt := 0
+	for {
+		query := url.Values{}
+		query.Set("state", strings.Join(states, ","))
+		query.Set("startAt", strconv.Itoa(startAt))
+		query.Set("maxResults", "50")
+
+		var page sprintPage
+		if err := c.do(ctx, http.MethodGet, path, query, nil, &page); err != nil {
+			return nil, err
+		}
+
+		sprints = append(sprints, page.Values...)
+		if page.IsLast || len(page.Values) == 0 {
+			return sprints, nil
+		}
+		startAt += len(page.Values)
+	}
+}
+
+// GetSprint returns a sprint by ID. A missing sprint yields an error
+// matching ErrNotFound. Sprints are cached for the life of the
+// client, so looking up the same sprint again makes no request.
+func (c *Client) GetSprint(ctx context.Context, sprintID int) (*Sprint, error) {
+	c.sprintCacheMu.Lock()
+	cached, ok := c.sprintCache[sprintID]
+	c.sprintCacheMu.Unlock()
+	if ok {
+		return &cached, nil
+	}
+
+	var sprint Sprint
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/rest/agile/1.0/sprint/%d", sprintID), nil, nil, &sprint); err != nil {
+		return nil, err
+	}
+
+	c.sprintCacheMu.Lock()
+	if c.sprintCache == nil {
+		c.sprintCache = make(map[int]Sprint)
+	}
+	c.sprintCache[sprintID] = sprint
+	c.sprintCacheMu.Unlock()
+
+	return &sprint, nil
+}
+
+// legacySprintID matches the ID in the legacy string form of the sprint
+// field, "com.atlassian.greenhopper.service.sprint.Sprint@1a2b[id=42,...]".
+var legacySprintID = regexp.MustCompile(`\bid=(\d+)`)
+
+// sprintIDs extracts sprint IDs from a sprint field value, which depending
+// on the instance is an array of sprint objects, of plain IDs, or of legacy
+// sprint strings.
+func sprintIDs(raw json.RawMessage) []int {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || raw[0] != '[' {
+		return nil
+	}
+
+	var items []json.RawMessage
+	if json.Unmarshal(raw, &items) != nil {
+		return nil
+	}
+
+	var ids []int
+	for _, item := range items {
+		var id int
+		var obj struct {
+			ID int `json:"id"`
+		}
+		var legacy string
+		switch {
+		case json.Unmarshal(item, &id) == nil:
+		case json.Unmarshal(item, &obj) == nil && obj.ID != 0:
+			id = obj.ID
+		case json.Unmarshal(item, &legacy) == nil:
+			m := legacySprintID.FindStringSubmatch(legacy)
+			if m == nil {
+				continue
+			}
+			id, _ = strconv.Atoi(m[1])
+		default:
+			continue
+		}
+		if id != 0 {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// resolveSprints looks up every distinct sprint referenced by the sprint
+// field of issues, once per sprint. Sprints that no longer exist or are not
+// visible are left out.
+func (c *Client) resolveSprints(ctx context.Context, issues []Issue, fieldID string) (map[int]Sprint, error) {
+	sprints := make(map[int]Sprint)
+	for _, issue := range issues {
+		for _, id := range sprintIDs(issue.Fields.CustomFields[fieldID]) {
+			if _, done := sprints[id]; done {
+				continue
+			}
+			sprint, err := c.GetSprint(ctx, id)
+			switch {
+			case errors.Is(err, ErrNotFound), errors.Is(err, ErrForbidden):
+				c.logger.WarnContext(ctx, "jira: sprint not resolvable", "sprint", id, "error", err)
+				sprints[id] = Sprint{}
+			case err != nil:
+				return nil, fmt.Errorf("get sprint %d: %w", id, err)
+			default:
+				sprints[id] = *sprint
+			}
+		}
+	}
+	return sprints, nil
+}
+
+// sprintMetadata returns the sprint_name and sprint_state metadata for an
+// issue: the names of all its sprints and the state of the latest one.
+func sprintMetadata(raw json.RawMessage, sprints map[int]Sprint) map[string]string {
+	var names []string
+	var state string
+	for _, id := range sprintIDs(raw) {
+		sprint := sprints[id]
+		if sprint.Name == "" {
+			continue
+		}
+		names = append(names, sprint.Name)
+		state = sprint.State
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	return map[string]string{
+		"sprint_name":  strings.Join(names, ", "),
+		"sprint_state": state,
+	}
+}
+
+// FetchSprintsInput is the input for FetchSprintsActivity.
+type FetchSprintsInput struct {
+	BaseURL  string
+	Email    string
+	APIToken string
+	BoardID  int
+	States   []string // default all states
+
+	ClientOptions
+}
+
+// FetchSprintsOutput is the output of FetchSprintsActivity.
+type FetchSprintsOutput struct {
+	Ref   core.DataRef
+	Count int
+}
+
+// FetchSprintsActivity fetches a board's sprints and stores one document per sprint.
+func FetchSprintsActivity(ctx context.Context, input FetchSprintsInput) (FetchSprintsOutput, error) {
+	client, err := input.ClientOptions.newClient(ClientConfig{
+		BaseURL:  input.BaseURL,
+		Email:    input.Email,
+		APIToken: input.APIToken,
+	})
+	if err != nil {
+		return FetchSprintsOutput{}, err
+	}
+	defer client.Close()
+
+	sprints, err := client.ListSprints(ctx, input.BoardID, input.States)
+	if err != nil {
+		return FetchSprintsOutput{}, fmt.Errorf("list sprints: %w", err)
+	}
+
+	docs := make([]transform.Document, 0, len(sprints))
+	for _, sprint := range sprints {
+		docs = append(docs, sprintToDocument(sprint, input.BoardID))
+	}
+
+	ref, err := storeWithRetry(ctx, docs)
+	if err != nil {
+		return FetchSprintsOutput{}, fmt.Errorf("store documents: %w", err)
+	}
+
+	return FetchSprintsOutput{
+		Ref:   ref,
+		Count: len(docs),
+	}, nil
+}
+
+// sprintToDocument converts a sprint to a transform.Document.
+func sprintToDocument(sprint Sprint, boardID int) transform.Document {
+	content := sprint.Name
+	if sprint.Goal != "" {
+		content += "\n\nGoal: " + sprint.Goal
+	}
+
+	metadata := map[string]string{
+		"sprint_id": strconv.Itoa(sprint.ID),
+		"board_id":  strconv.Itoa(boardID),
+		"state":     sprint.State,
+	}
+	for key, value := range map[string]string{
+		"start_date":    sprint.StartDate,
+		"end_date":      sprint.EndDate,
+		"complete_date": sprint.CompleteDate,
+		"goal":          sprint.Goal,
+	} {
+		if value != "" {
+			metadata[key] = value
+		}
+	}
+
+	return transform.Document{
+		ID:       fmt.Sprintf("sprint-%d", sprint.ID),
+		Content:  content,
+		Title:    sprint.Name,
+		Source:   "jira",
+		URL:      sprint.Self,
+		Metadata: metadata,
+	}
+}
+
+// FetchSprintInput is the input for FetchSprintActivity.
+type FetchSprintInput struct {
+	BaseURL  string
+	Email    string
+	APIToken string
+	SprintID int
+
+	ClientOptions
+}
+
+// FetchSprintOutput is the output of FetchSprintActivity.
+type FetchSprintOutput struct {
+	Ref    core.DataRef
+	Sprint Sprint
+}
+
+// FetchSprintActivity fetches a single sprint and stores it as a document.
+func FetchSprintActivity(ctx context.Context, input FetchSprintInput) (FetchSprintOutput, error) {
+	client, err := input.ClientOptions.newClient(ClientConfig{
+		BaseURL:  input.BaseURL,
+		Email:    input.Email,
+		APIToken: input.APIToken,
+	})
+	if err != nil {
+		return FetchSprintOutput{}, err
+	}
+	defer client.Close()
+
+	sprint, err := client.GetSprint(ctx, input.SprintID)
+	if err != nil {
+		return FetchSprintOutput{}, fmt.Errorf("get sprint: %w", err)
+	}
+
+	ref, err := storeWithRetry(ctx, []transform.Document{sprintToDocument(*sprint, sprint.OriginBoardID)})
+	if err != nil {
+		return FetchSprintOutput{}, fmt.Errorf("store documents: %w", err)
+	}
+
+	return FetchSprintOutput{Ref: ref, Sprint: *sprint}, nil
+}
+
+// FetchSprints creates a node for fetching a board's sprints.
+func FetchSprints(input FetchSprintsInput) *core.Node[FetchSprintsInput, FetchSprintsOutput] {
+	return core.NewNode("jira.FetchSprints", FetchSprintsActivity, input)
+}
+
+// FetchSprint creates a node for fetching a single sprint.
+func FetchSprint(input FetchSprintInput) *core.Node[FetchSprintInput, FetchSprintOutput] {
+	return core.NewNode("jira.FetchSprint", FetchSprintActivity, input)
+}
@@ -0,0 +1,144 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSprintIDs(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want []int
+	}{
+		{raw: `null`},
+		{raw: `{"id":1}`},
+		{raw: `[{"id":41,"name":"Sprint 41"},{"id":42,"name":"Sprint 42"}]`, want: []int{41, 42}},
+		{raw: `[41, 42]`, want: []int{41, 42}},
+		{raw: `["com.atlassian.greenhopper.service.sprint.Sprint@1a2b[id=42,rapidViewId=7,state=ACTIVE,name=Sprint 42]"]`, want: []int{42}},
+		{raw: `["no id here", {"name":"nameless"}, 0, true]`},
+	}
+
+	for _, tt := range tests {
+		if got := sprintIDs(json.RawMessage(tt.raw)); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("sprintIDs(%s) = %v, want %v", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestResolveSprints(t *testing.T) {
+	var lookups atomic.Int32
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lookups.Add(1)
+		id, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/rest/agile/1.0/sprint/"))
+		switch id {
+		case 41:
+			writeJSON(w, map[string]any{"id": 41, "name": "Sprint 41", "state": SprintStateClosed})
+		case 42:
+			writeJSON(w, map[string]any{"id": 42, "name": "Sprint 42", "state": SprintStateActive})
+		case 43:
+			http.Error(w, `{"errorMessages":["no"]}`, http.StatusNotFound)
+		default:
+			http.Error(w, `{"errorMessages":["boom"]}`, http.StatusBadRequest)
+		}
+	}), ClientConfig{})
+
+	const field = "customfield_10020"
+	issues := []Issue{
+		decodeIssue(t, "PROJ-1", map[string]any{field: []any{map[string]any{"id": 41}, map[string]any{"id": 42}}}),
+		decodeIssue(t, "PROJ-2", map[string]any{field: []any{42, 43}}),
+		decodeIssue(t, "PROJ-3", nil),
+	}
+
+	sprints, err := client.resolveSprints(context.Background(), issues, field)
+	if err != nil {
+		t.Fatalf("resolveSprints: %v", err)
+	}
+	if n := lookups.Load(); n != 3 {
+		t.Errorf("%d lookups, want one per sprint", n)
+	}
+
+	tests := []struct {
+		issue Issue
+		want  map[string]string
+	}{
+		{issue: issues[0], want: map[string]string{"sprint_name": "Sprint 41, Sprint 42", "sprint_state": SprintStateActive}},
+		{issue: issues[1], want: map[string]string{"sprint_name": "Sprint 42", "sprint_state": SprintStateActive}},
+		{issue: issues[2]},
+	}
+	for _, tt := range tests {
+		if got := sprintMetadata(tt.issue.Fields.CustomFields[field], sprints); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s metadata = %v, want %v", tt.issue.Key, got, tt.want)
+		}
+	}
+
+	// Sprints are cached by the client; only the failing one is requested.
+	broken := []Issue{decodeIssue(t, "PROJ-4", map[string]any{field: []any{41, 99}})}
+	if _, err := client.resolveSprints(context.Background(), broken, field); err == nil || !strings.Contains(err.Error(), "get sprint 99") {
+		t.Errorf("error = %v, want get sprint 99", err)
+	}
+	if n := lookups.Load(); n != 4 {
+		t.Errorf("%d lookups, want 4", n)
+	}
+}
+
+func TestFetchSprintsActivity(t *testing.T) {
+	var states []string
+	fake := &fakeJira{routes: map[string]http.HandlerFunc{
+		"/rest/agile/1.0/board/7/sprint": func(w http.ResponseWriter, r *http.Request) {
+			query := r.URL.Query()
+			states = append(states, query.Get("state"))
+			startAt, _ := strconv.Atoi(query.Get("startAt"))
+			sprint := map[string]any{"id": 40 + startAt, "name": fmt.Sprintf("Sprint %d", 40+startAt), "state": SprintStateClosed}
+			if startAt == 1 {
+				sprint["goal"] = "Ship checkout"
+			}
+			writeJSON(w, map[string]any{"startAt": startAt, "isLast": startAt == 1, "values": []any{sprint}})
+		},
+	}}
+
+	out, err := runActivity(t, FetchSprintsActivity, FetchSprintsInput{BaseURL: fake.start(t), BoardID: 7})
+	if err != nil {
+		t.Fatalf("FetchSprintsActivity: %v", err)
+	}
+	if out.Count != 2 || len(states) != 2 || states[0] != "active,closed,future" {
+		t.Errorf("count %d, requests %v, want 2 pages over every state", out.Count, states)
+	}
+
+	docs := loadDocuments(t, out.Ref)
+	last := docs[1]
+	if last.ID != "sprint-41" || last.Content != "Sprint 41\n\nGoal: Ship checkout" || last.Metadata["board_id"] != "7" || last.Metadata["goal"] != "Ship checkout" {
+		t.Errorf("document = %+v", last)
+	}
+	if _, ok := docs[0].Metadata["goal"]; ok {
+		t.Errorf("metadata %v has an empty goal", docs[0].Metadata)
+	}
+}
+
+func TestGetSprint(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/agile/1.0/sprint/42" {
+			w.WriteHeader(http.StatusNotFound)
+			writeJSON(w, map[string]any{"errorMessages": []string{"Sprint does not exist"}})
+			return
+		}
+		writeJSON(w, map[string]any{"id": 42, "state": "active", "name": "Sprint 42", "goal": "Ship it"})
+	}), ClientConfig{})
+
+	sprint, err := client.GetSprint(context.Background(), 42)
+	if err != nil {
+		t.Fatalf("GetSprint: %v", err)
+	}
+	if sprint.Name != "Sprint 42" || sprint.State != "active" || sprint.Goal != "Ship it" {
+		t.Errorf("sprint = %+v, want Sprint 42", sprint)
+	}
+	if _, err := client.GetSprint(context.Background(), 7); !errors.Is(err, ErrNotFound) {
+		t.Errorf("error for a missing sprint = %v, want ErrNotFound", err)
+	}
+}
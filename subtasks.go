@@ -0,0 +1,112 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/resolute-sh/resolute-jira/jql"
+	"github.com/resolute-sh/resolute/core"
+)
+
+// GetSubtasks returns the sub-tasks of an issue with all their navigable
+// fields, ordered by key. Unlike the sub-task stubs embedded in the parent
+// issue, they carry descriptions, assignees and the like.
+func (c *Client) GetSubtasks(ctx context.Context, parentKey string) ([]Issue, error) {
+	params := SearchJQLParams{
+		JQL:        jql.Equals("parent", parentKey) + " ORDER BY key ASC",
+		MaxResults: 100,
+	}
+
+	var subtasks []Issue
+	for {
+		result, err := c.SearchJQLWithParams(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("search subtasks: %w", err)
+		}
+		subtasks = append(subtasks, result.Issues...)
+
+		if c.cursorSearch {
+			if result.IsLast || result.NextPageToken == "" {
+				return subtasks, nil
+			}
+			params.NextPageToken = result.NextPageToken
+			continue
+		}
+
+		params.StartAt += len(result.Issues)
+		if len(result.Issues) == 0 || params.StartAt >= result.Total {
+			return subtasks, nil
+		}
+	}
+}
+
+// FetchSubtasksInput is the input for FetchSubtasksActivity.
+type FetchSubtasksInput struct {
+	BaseURL   string
+	Email     string
+	APIToken  string
+	ParentKey string
+
+	DocumentOptions
+	ClientOptions
+}
+
+// FetchSubtasksOutput is the output of FetchSubtasksActivity.
+type FetchSubtasksOutput struct {
+	Ref          core.DataRef
+	Count        int
+	SubtaskCount int
+
+	// Skipped lists the keys of issues dropped for thin content.
+	Skipped []string
+
+	// FailedIssues lists the issues left out because they failed to
+	// convert, with ContinueOnError.
+	FailedIssues []FailedIssue
+}
+
+// FetchSubtasksActivity fetches the sub-tasks of an issue and stores them
+// as documents with parent_key metadata.
+func FetchSubtasksActivity(ctx context.Context, input FetchSubtasksInput) (FetchSubtasksOutput, error) {
+	client, err := input.ClientOptions.newClient(ClientConfig{
+		BaseURL:  input.BaseURL,
+		Email:    input.Email,
+		APIToken: input.APIToken,
+	})
+	if err != nil {
+		return FetchSubtasksOutput{}, err
+	}
+	defer client.Close()
+
+	subtasks, err := client.GetSubtasks(ctx, input.ParentKey)
+	if err != nil {
+		return FetchSubtasksOutput{}, fmt.Errorf("get subtasks: %w", err)
+	}
+
+	converted, err := issuesToDocuments(ctx, client, subtasks, input.DocumentOptions)
+	if err != nil {
+		return FetchSubtasksOutput{}, err
+	}
+	for _, doc := range converted.Docs {
+		doc.Metadata["parent_key"] = input.ParentKey
+	}
+
+	ref, err := storeConversion(ctx, client, &converted, input.DocumentOptions)
+	if err != nil {
+		return FetchSubtasksOutput{}, fmt.Errorf("store documents: %w", err)
+	}
+	docs := converted.Docs
+
+	return FetchSubtasksOutput{
+		Ref:          ref,
+		Count:        len(docs),
+		SubtaskCount: len(subtasks),
+		Skipped:      converted.Skipped,
+		FailedIssues: converted.Failed,
+	}, nil
+}
+
+// FetchSubtasks creates a node for fetching the sub-tasks of an issue.
+func FetchSubtasks(input FetchSubtasksInput) *core.Node[FetchSubtasksInput, FetchSubtasksOutput] {
+	return core.NewNode("jira.FetchSubtasks", FetchSubtasksActivity, input)
+}
@@ -0,0 +1,51 @@
+package jira
+
+import "testing"
+
+func TestFetchSubtasksActivity(t *testing.T) {
+	for _, cursor := range []bool{false, true} {
+		name := "offset"
+		if cursor {
+			name = "cursor"
+		}
+		t.Run(name, func(t *testing.T) {
+			jira := &fakeJira{issues: testIssues(130, false)}
+			out, err := runActivity(t, FetchSubtasksActivity, FetchSubtasksInput{
+				BaseURL:       jira.start(t),
+				Email:         "bot@example.com",
+				APIToken:      "token",
+				ParentKey:     "PROJ-0",
+				ClientOptions: ClientOptions{CursorSearch: cursor},
+			})
+			if err != nil {
+				t.Fatalf("FetchSubtasksActivity: %v", err)
+			}
+			if out.SubtaskCount != 130 || out.Count != 130 {
+				t.Errorf("SubtaskCount = %d, Count = %d, want 130 each", out.SubtaskCount, out.Count)
+			}
+
+			searches := jira.recorded()
+			if len(searches) != 2 {
+				t.Fatalf("made %d searches, want 2: %+v", len(searches), searches)
+			}
+			for _, search := range searches {
+				if want := `parent = "PROJ-0" ORDER BY key ASC`; search.JQL != want {
+					t.Errorf("JQL = %q, want %q", search.JQL, want)
+				}
+			}
+			if second := searches[1]; cursor && second.Token != "100" || !cursor && second.StartAt != 100 {
+				t.Errorf("second page = %+v, want it to continue after 100 issues", second)
+			}
+
+			docs := loadDocuments(t, out.Ref)
+			if len(docs) != 130 {
+				t.Fatalf("stored %d documents, want 130", len(docs))
+			}
+			for _, doc := range docs {
+				if doc.Metadata["parent_key"] != "PROJ-0" {
+					t.Errorf("%s parent_key = %q, want PROJ-0", doc.ID, doc.Metadata["parent_key"])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package jira
+
+import (
+	"strings"
+	"time"
+
+	transform "github.com/resolute-sh/resolute-transform"
+)
+
+// IssueSummary is the structured view of an issue recovered from its
+// document by DocumentToIssueSummary. Fields whose metadata was not
+// written, e.g. Labels without LabelsAsKeys, are empty.
+type IssueSummary struct {
+	Key        string
+	Summary    string
+	URL        string
+	Project    string
+	Status     string
+	IssueType  string
+	Priority   string
+	Assignee   string // display name
+	Labels     []string
+	Components []string
+	DueDate    string // YYYY-MM-DD
+	Created    time.Time
+	Updated    time.Time
+}
+
+// DocumentToIssueSummary reconstructs the common fields of an issue from a
+// document written by this package, so consumers need not depend on its
+// metadata key names or re-fetch the issue. It expects the default keys,
+// i.e. documents stored without MetadataKeyAliases. A comment document
+// yields its issue's key and project only.
+func DocumentToIssueSummary(doc transform.Document) IssueSummary {
+	metadata := doc.Metadata
+	if parent := metadata["parent_issue"]; parent != "" {
+		return IssueSummary{Key: parent, URL: doc.URL, Project: metadata["project"]}
+	}
+
+	summary := IssueSummary{
+		Key:       doc.ID,
+		Summary:   doc.Title,
+		URL:       doc.URL,
+		Project:   metadata["project"],
+		Status:    metadata["status"],
+		IssueType: metadata["issue_type"],
+		Priority:  metadata["priority"],
+		Assignee:  metadata["assignee"],
+		DueDate:   metadata["due_date"],
+		Updated:   doc.UpdatedAt,
+	}
+	if key := metadata["issue_key"]; key != "" {
+		summary.Key = key
+	}
+	if labels := metadata["labels"]; labels != "" {
+		summary.Labels = strings.Split(labels, ",")
+	}
+	if components := metadata["components"]; components != "" {
+		summary.Components = strings.Split(components, ",")
+	}
+	if created, err := time.Parse(time.RFC3339, metadata["created"]); err == nil {
+		summary.Created = created
+	}
+	return summary
+}
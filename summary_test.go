@@ -0,0 +1,78 @@
+package jira
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDocumentToIssueSummary(t *testing.T) {
+	issue := decodeIssue(t, "PROJ-7", map[string]any{
+		"summary":    "Checkout fails for EU cards",
+		"project":    map[string]any{"key": "PROJ"},
+		"status":     map[string]any{"name": "In Progress"},
+		"issuetype":  map[string]any{"name": "Bug"},
+		"priority":   map[string]any{"name": "High"},
+		"assignee":   map[string]any{"accountId": "acc-ada", "displayName": "Ada Lovelace"},
+		"labels":     []string{"payments", "eu"},
+		"components": []map[string]any{{"id": "1", "name": "API"}, {"id": "2", "name": "Web"}},
+		"duedate":    "2024-04-01",
+		"created":    "2024-03-01T09:30:00.000+0100",
+		"updated":    "2024-03-05T17:00:00.000+0100",
+		"comment": map[string]any{"comments": []map[string]any{
+			{"id": "10", "body": "Reproduced.", "author": map[string]any{"displayName": "Grace"}, "created": "2024-03-02T10:00:00.000+0000"},
+		}},
+	})
+
+	full := IssueSummary{
+		Key:        "PROJ-7",
+		Summary:    "Checkout fails for EU cards",
+		URL:        issue.Self,
+		Project:    "PROJ",
+		Status:     "In Progress",
+		IssueType:  "Bug",
+		Priority:   "High",
+		Assignee:   "Ada Lovelace",
+		Labels:     []string{"payments", "eu"},
+		Components: []string{"API", "Web"},
+		DueDate:    "2024-04-01",
+		Created:    time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC),
+		Updated:    time.Date(2024, 3, 5, 16, 0, 0, 0, time.UTC),
+	}
+	withoutOptions := full
+	withoutOptions.Labels = nil
+	withoutOptions.Components = nil
+
+	tests := []struct {
+		name string
+		opts DocumentOptions
+		want IssueSummary
+	}{
+		{
+			name: "all metadata",
+			opts: DocumentOptions{LabelsAsKeys: true, IncludeComponents: true},
+			want: full,
+		},
+		{
+			name: "default options",
+			want: withoutOptions,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DocumentToIssueSummary(issueToDocument(issue, tt.opts))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DocumentToIssueSummary =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("comment", func(t *testing.T) {
+		doc := commentToDocument(issue, issue.Fields.Comments.Comments[0], DocumentOptions{})
+		want := IssueSummary{Key: "PROJ-7", URL: issue.Self, Project: "PROJ"}
+		if got := DocumentToIssueSummary(doc); !reflect.DeepEqual(got, want) {
+			t.Errorf("DocumentToIssueSummary =\n%+v\nwant\n%+v", got, want)
+		}
+	})
+}
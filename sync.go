@@ -0,0 +1,263 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/resolute-sh/resolute-jira/jql"
+	"github.com/resolute-sh/resolute/core"
+)
+
+// SyncCursor records how far an incremental sync got: the newest updated
+// timestamp seen and the keys of the issues carrying exactly that timestamp.
+type SyncCursor struct {
+	HighWaterMark time.Time `json:"hwm"`
+	Keys          []string  `json:"keys,omitempty"`
+}
+
+// Encode returns the cursor as an opaque string for persisting between runs.
+func (c SyncCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return string(data)
+}
+
+// ParseSyncCursor decodes a cursor produced by SyncCursor.Encode. An empty
+// string yields the zero cursor.
+func ParseSyncCursor(s string) (SyncCursor, error) {
+	var c SyncCursor
+	if s == "" {
+		return c, nil
+	}
+	if err := json.Unmarshal([]byte(s), &c); err != nil {
+		return SyncCursor{}, fmt.Errorf("parse sync cursor: %w", err)
+	}
+	return c, nil
+}
+
+// dedupAfterCursor drops issues the previous run already delivered: those
+// updated before the high-water mark (JQL dates only have minute precision,
+// so the window re-reads part of a minute) and those at the mark whose key
+// is recorded in the cursor.
+func dedupAfterCursor(issues []Issue, cursor SyncCursor) []Issue {
+	if cursor.HighWaterMark.IsZero() {
+		return issues
+	}
+
+	seen := make(map[string]bool, len(cursor.Keys))
+	for _, key := range cursor.Keys {
+		seen[key] = true
+	}
+
+	kept := issues[:0:0]
+	for _, issue := range issues {
+		updated, err := parseTime(issue.Fields.Updated)
+		if err == nil {
+			if updated.Before(cursor.HighWaterMark) {
+				continue
+			}
+			if updated.Equal(cursor.HighWaterMark) && seen[issue.Key] {
+				continue
+			}
+		}
+		kept = append(kept, issue)
+	}
+	return kept
+}
+
+// advance returns the cursor after delivering issues.
+func (c SyncCursor) advance(issues []Issue) SyncCursor {
+	next := SyncCursor{HighWaterMark: c.HighWaterMark, Keys: append([]string(nil), c.Keys...)}
+	for _, issue := range issues {
+		updated, err := parseTime(issue.Fields.Updated)
+		if err != nil {
+			continue
+		}
+		switch {
+		case updated.After(next.HighWaterMark):
+			next.HighWaterMark = updated
+			next.Keys = []string{issue.Key}
+		case updated.Equal(next.HighWaterMark):
+			next.Keys = append(next.Keys, issue.Key)
+		}
+	}
+	return next
+}
+
+// FindDeletedKeys returns the keys from known that no longer match an
+// existing issue, checking them in batches with `key in (...)` searches.
+// Keys of moved issues are reported as deleted since Jira answers with the
+// new key.
+func (c *Client) FindDeletedKeys(ctx context.Context, known []string) ([]string, error) {
+	const batchSize = 100
+
+	var deleted []string
+	for start := 0; start < len(known); start += batchSize {
+		batch := known[start:min(start+batchSize, len(known))]
+
+		query := url.Values{}
+		query.Set("jql", jql.In("key", batch))
+		query.Set("fields", "key")
+		query.Set("maxResults", fmt.Sprint(batchSize))
+		// Keys of deleted issues would otherwise fail the whole query.
+		query.Set("validateQuery", "warn")
+
+		var result SearchResult
+		if err := c.do(ctx, http.MethodGet, "/rest/api/3/search", query, nil, &result); err != nil {
+			return nil, fmt.Errorf("search keys: %w", err)
+		}
+
+		found := make(map[string]bool, len(result.Issues))
+		for _, issue := range result.Issues {
+			found[issue.Key] = true
+		}
+		for _, key := range batch {
+			if !found[key] {
+				deleted = append(deleted, key)
+			}
+		}
+	}
+
+	return deleted, nil
+}
+
+// FetchIssuesSinceInput is the input for FetchIssuesSinceActivity.
+type FetchIssuesSinceInput struct {
+	BaseURL  string
+	Email    string
+	APIToken string
+	Project  string
+
+	// LastRun is the start of the window on the first run. Later runs use
+	// the high-water mark in Cursor instead.
+	LastRun time.Time
+
+	// Cursor is the Cursor returned by the previous run, empty on the first.
+	Cursor string
+
+	// KnownKeys are the issue keys currently in the caller's index. When
+	// set, keys that no longer exist in Jira are reported as DeletedKeys.
+	KnownKeys []string
+
+	MaxResults int // per page, default 100
+
+	DocumentOptions
+	ClientOptions
+}
+
+// FetchIssuesSinceOutput is the output of FetchIssuesSinceActivity.
+type FetchIssuesSinceOutput struct {
+	Ref           core.DataRef
+	Count         int
+	HighWaterMark time.Time
+	Cursor        string // pass as the next run's Cursor
+	DeletedKeys   []string
+
+	// IssueCount and CommentCount split Count into issue documents and,
+	// with ExplodeComments, comment documents.
+	IssueCount   int
+	CommentCount int
+
+	// Skipped lists the keys of issues dropped for thin content.
+	Skipped []string
+
+	// FailedIssues lists the issues left out because they failed to
+	// convert, with ContinueOnError. The cursor advances past them, so
+	// refetch them individually once fixed.
+	FailedIssues []FailedIssue
+}
+
+// FetchIssuesSinceActivity keeps an index in sync with a project. It fetches
+// issues updated at or after the last high-water mark in ascending updated
+// order, drops those the previous run already delivered, and returns the
+// new cursor together with the keys deleted since.
+//
+// Guarantees: a run that succeeds delivers every issue updated since the
+// previous cursor exactly once, and an issue updated again later is
+// delivered again. Limits: the cursor only advances when the caller persists
+// it, so a failed run is retried from the old cursor; Jira's search index is
+// eventually consistent, so an edit committed just before the search may
+// surface on the next run rather than this one; deletions are only detected
+// for keys passed in KnownKeys.
+func FetchIssuesSinceActivity(ctx context.Context, input FetchIssuesSinceInput) (FetchIssuesSinceOutput, error) {
+	client, err := input.ClientOptions.newClient(ClientConfig{
+		BaseURL:  input.BaseURL,
+		Email:    input.Email,
+		APIToken: input.APIToken,
+	})
+	if err != nil {
+		return FetchIssuesSinceOutput{}, err
+	}
+	defer client.Close()
+
+	cursor, err := ParseSyncCursor(input.Cursor)
+	if err != nil {
+		return FetchIssuesSinceOutput{}, err
+	}
+	if cursor.HighWaterMark.IsZero() {
+		cursor.HighWaterMark = input.LastRun
+	}
+
+	q := projectQuery{
+		Project: input.Project,
+		OrderBy: "updated ASC, key ASC",
+	}
+	if !cursor.HighWaterMark.IsZero() {
+		since := cursor.HighWaterMark
+		q.Since = &since
+	}
+	query, err := q.build(ctx, client)
+	if err != nil {
+		return FetchIssuesSinceOutput{}, err
+	}
+
+	var converted conversion
+	next := cursor
+	_, _, err = paginateSearch(ctx, client, query, paginateOptions{PageSize: input.MaxResults}, func(issues []Issue) error {
+		issues = dedupAfterCursor(issues, cursor)
+		next = next.advance(issues)
+
+		page, err := issuesToDocuments(ctx, client, issues, input.DocumentOptions)
+		if err != nil {
+			return err
+		}
+		converted.add(page)
+		return nil
+	})
+	if err != nil {
+		return FetchIssuesSinceOutput{}, err
+	}
+
+	deleted, err := client.FindDeletedKeys(ctx, input.KnownKeys)
+	if err != nil {
+		return FetchIssuesSinceOutput{}, fmt.Errorf("reconcile deletions: %w", err)
+	}
+
+	ref, err := storeConversion(ctx, client, &converted, input.DocumentOptions)
+	if err != nil {
+		return FetchIssuesSinceOutput{}, fmt.Errorf("store documents: %w", err)
+	}
+	docs := converted.Docs
+
+	issueCount, commentCount := countDocuments(docs)
+	return FetchIssuesSinceOutput{
+		Ref:           ref,
+		Count:         len(docs),
+		IssueCount:    issueCount,
+		CommentCount:  commentCount,
+		Skipped:       converted.Skipped,
+		FailedIssues:  converted.Failed,
+		HighWaterMark: next.HighWaterMark,
+		Cursor:        next.Encode(),
+		DeletedKeys:   deleted,
+	}, nil
+}
+
+// FetchIssuesSince creates a node for incrementally syncing a project.
+func FetchIssuesSince(input FetchIssuesSinceInput) *core.Node[FetchIssuesSinceInput, FetchIssuesSinceOutput] {
+	return core.NewNode("jira.FetchIssuesSince", FetchIssuesSinceActivity, input).
+		WithTimeout(30 * time.Minute)
+}
@@ -0,0 +1,87 @@
+package jira
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyncCursorRoundTrip(t *testing.T) {
+	cursor := SyncCursor{HighWaterMark: time.Date(2024, 3, 3, 10, 0, 0, 0, time.UTC), Keys: []string{"PROJ-3"}}
+	parsed, err := ParseSyncCursor(cursor.Encode())
+	if err != nil {
+		t.Fatalf("ParseSyncCursor: %v", err)
+	}
+	if !parsed.HighWaterMark.Equal(cursor.HighWaterMark) || fmt.Sprint(parsed.Keys) != "[PROJ-3]" {
+		t.Errorf("parsed = %+v, want %+v", parsed, cursor)
+	}
+
+	if zero, err := ParseSyncCursor(""); err != nil || !zero.HighWaterMark.IsZero() {
+		t.Errorf("ParseSyncCursor(\"\") = %+v, %v, want the zero cursor", zero, err)
+	}
+	if _, err := ParseSyncCursor("hwm=2024"); err == nil {
+		t.Errorf("ParseSyncCursor of garbage succeeded")
+	}
+}
+
+func TestFetchIssuesSinceActivity(t *testing.T) {
+	fake := &fakeJira{issues: testIssues(3, false)}
+	baseURL := fake.start(t)
+	run := func(cursor string, known ...string) FetchIssuesSinceOutput {
+		t.Helper()
+		out, err := runActivity(t, FetchIssuesSinceActivity, FetchIssuesSinceInput{
+			BaseURL:   baseURL,
+			Project:   "PROJ",
+			LastRun:   time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			Cursor:    cursor,
+			KnownKeys: known,
+		})
+		if err != nil {
+			t.Fatalf("FetchIssuesSinceActivity: %v", err)
+		}
+		return out
+	}
+	ids := func(out FetchIssuesSinceOutput) string {
+		var ids []string
+		for _, doc := range loadDocuments(t, out.Ref) {
+			ids = append(ids, doc.ID)
+		}
+		return strings.Join(ids, ",")
+	}
+
+	first := run("")
+	if got := ids(first); got != "PROJ-1,PROJ-2,PROJ-3" {
+		t.Errorf("first run delivered %s", got)
+	}
+	if want := time.Date(2024, 3, 3, 10, 0, 0, 0, time.UTC); !first.HighWaterMark.Equal(want) {
+		t.Errorf("high-water mark = %s, want %s", first.HighWaterMark, want)
+	}
+	searches := fake.recorded()
+	if jql := searches[0].JQL; !strings.Contains(jql, `updated >= "2024-03-01 00:00"`) || !strings.HasSuffix(jql, "ORDER BY updated ASC, key ASC") {
+		t.Errorf("jql = %s", jql)
+	}
+
+	// Jira returns the whole window again; only the new issue at the
+	// high-water mark is delivered.
+	fake.issues = append(fake.issues, testIssue("PROJ-4", map[string]any{"updated": "2024-03-03T10:00:00.000+0000"}))
+	second := run(first.Cursor, "PROJ-1", "PROJ-99")
+	if second.Count != 1 || ids(second) != "PROJ-4" {
+		t.Errorf("second run delivered %s, want PROJ-4", ids(second))
+	}
+	if fmt.Sprint(second.DeletedKeys) != "[PROJ-99]" {
+		t.Errorf("deleted = %v, want [PROJ-99]", second.DeletedKeys)
+	}
+	searches = fake.recorded()
+	if jql := searches[len(searches)-2].JQL; !strings.Contains(jql, `updated >= "2024-03-03 10:00"`) {
+		t.Errorf("second run jql = %s, want the high-water mark", jql)
+	}
+
+	if third := run(second.Cursor); third.Count != 0 || third.Cursor != second.Cursor {
+		t.Errorf("third run = %d documents, cursor %s, want none and %s", third.Count, third.Cursor, second.Cursor)
+	}
+	cursor, _ := ParseSyncCursor(second.Cursor)
+	if fmt.Sprint(cursor.Keys) != "[PROJ-3 PROJ-4]" {
+		t.Errorf("cursor keys = %v, want [PROJ-3 PROJ-4]", cursor.Keys)
+	}
+}
@@ -0,0 +1,127 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/resolute-sh/resolute/core"
+)
+
+// Transition is a workflow transition available on an issue.
+type Transition struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	To   Status `json:"to"`
+}
+
+// GetTransitions returns the transitions the caller can perform on an
+// issue in its current status.
+func (c *Client) GetTransitions(ctx context.Context, issueKey string) ([]Transition, error) {
+	var resp struct {
+		Transitions []Transition `json:"transitions"`
+	}
+	path := "/rest/api/3/issue/" + url.PathEscape(issueKey) + "/transitions"
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Transitions, nil
+}
+
+// TransitionIssue performs the transition named name (case-insensitive) on
+// an issue. Fields sets transition-screen fields, e.g. {"resolution":
+// {"name": "Done"}}, and may be nil. A name not available from the issue's
+// current status fails with the list of available transitions.
+func (c *Client) TransitionIssue(ctx context.Context, issueKey, name string, fields map[string]any) error {
+	transitions, err := c.GetTransitions(ctx, issueKey)
+	if err != nil {
+		return fmt.Errorf("get transitions: %w", err)
+	}
+
+	var id string
+	names := make([]string, 0, len(transitions))
+	for _, t := range transitions {
+		if strings.EqualFold(t.Name, strings.TrimSpace(name)) {
+			id = t.ID
+			break
+		}
+		names = append(names, t.Name)
+	}
+	if id == "" {
+		return fmt.Errorf("transition %q not available for %s, want one of %s", name, issueKey, strings.Join(names, ", "))
+	}
+
+	payload := map[string]any{"transition": map[string]string{"id": id}}
+	if len(fields) > 0 {
+		payload["fields"] = fields
+	}
+
+	path := "/rest/api/3/issue/" + url.PathEscape(issueKey) + "/transitions"
+	return c.do(ctx, http.MethodPost, path, nil, payload, nil)
+}
+
+// CommentAndTransitionInput is the input for CommentAndTransitionActivity.
+type CommentAndTransitionInput struct {
+	BaseURL    string
+	Email      string
+	APIToken   string
+	IssueKey   string
+	Comment    string
+	Transition string         // transition name, e.g. "Close Issue"
+	Fields     map[string]any // optional transition-screen fields
+
+	ClientOptions
+}
+
+// CommentAndTransitionOutput is the output of CommentAndTransitionActivity.
+type CommentAndTransitionOutput struct {
+	CommentID string
+
+	// Transitioned is false when the comment was posted but the transition
+	// failed; TransitionError then says why.
+	Transitioned    bool
+	TransitionError string
+}
+
+// CommentAndTransitionActivity posts a comment on an issue and then
+// performs a transition. Jira has no transactions, so this is best-effort:
+// if the comment fails nothing is changed and an error is returned, but if
+// the transition fails after the comment was posted the activity succeeds
+// with Transitioned false, so a retry does not post the comment twice and
+// the workflow can decide how to recover.
+func CommentAndTransitionActivity(ctx context.Context, input CommentAndTransitionInput) (CommentAndTransitionOutput, error) {
+	client, err := input.ClientOptions.newClient(ClientConfig{
+		BaseURL:  input.BaseURL,
+		Email:    input.Email,
+		APIToken: input.APIToken,
+	})
+	if err != nil {
+		return CommentAndTransitionOutput{}, err
+	}
+	defer client.Close()
+
+	comment, err := client.AddComment(ctx, input.IssueKey, input.Comment, nil)
+	if err != nil {
+		return CommentAndTransitionOutput{}, fmt.Errorf("add comment: %w", err)
+	}
+
+	output := CommentAndTransitionOutput{CommentID: comment.ID}
+	if err := client.TransitionIssue(ctx, input.IssueKey, input.Transition, input.Fields); err != nil {
+		client.logger.WarnContext(ctx, "jira: comment posted but transition failed",
+			"issue", input.IssueKey, "comment_id", comment.ID, "transition", input.Transition, "error", err)
+		output.TransitionError = err.Error()
+		return output, nil
+	}
+
+	output.Transitioned = true
+	return output, nil
+}
+
+// CommentAndTransition creates a node for commenting on and transitioning
+// an issue.
+func CommentAndTransition(input CommentAndTransitionInput) *core.Node[CommentAndTransitionInput, CommentAndTransitionOutput] {
+	return core.NewNode("jira.CommentAndTransition", CommentAndTransitionActivity, input)
+}
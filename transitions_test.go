@@ -0,0 +1,89 @@
+package jira
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCommentAndTransitionActivity(t *testing.T) {
+	tests := []struct {
+		name             string
+		transition       string
+		commentStatus    int
+		wantTransitioned bool
+		wantTransitionID string
+		wantTransErr     string
+		wantErr          string
+	}{
+		{name: "case-insensitive name", transition: " close issue ", wantTransitioned: true, wantTransitionID: "31"},
+		{name: "unavailable transition", transition: "Reopen", wantTransErr: `transition "Reopen" not available for PROJ-1, want one of Start Progress, Close Issue`},
+		{name: "comment failed", transition: "Close Issue", commentStatus: http.StatusForbidden, wantErr: "add comment"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var transitionID string
+			var fields map[string]any
+			fake := &fakeJira{routes: map[string]http.HandlerFunc{
+				"/rest/api/3/issue/PROJ-1/comment": func(w http.ResponseWriter, r *http.Request) {
+					if tt.commentStatus != 0 {
+						http.Error(w, `{"errorMessages":["no"]}`, tt.commentStatus)
+						return
+					}
+					writeJSON(w, map[string]any{"id": "10001"})
+				},
+				"/rest/api/3/issue/PROJ-1/transitions": func(w http.ResponseWriter, r *http.Request) {
+					if r.Method == http.MethodGet {
+						writeJSON(w, map[string]any{"transitions": []any{
+							map[string]any{"id": "21", "name": "Start Progress"},
+							map[string]any{"id": "31", "name": "Close Issue"},
+						}})
+						return
+					}
+					var body struct {
+						Transition struct {
+							ID string `json:"id"`
+						} `json:"transition"`
+						Fields map[string]any `json:"fields"`
+					}
+					if err := decodeBody(r, &body); err != nil {
+						t.Errorf("decode body: %v", err)
+					}
+					transitionID, fields = body.Transition.ID, body.Fields
+					w.WriteHeader(http.StatusNoContent)
+				},
+			}}
+
+			out, err := runActivity(t, CommentAndTransitionActivity, CommentAndTransitionInput{
+				BaseURL:    fake.start(t),
+				IssueKey:   "PROJ-1",
+				Comment:    "Closing after deploy.",
+				Transition: tt.transition,
+				Fields:     map[string]any{"resolution": map[string]any{"name": "Done"}},
+			})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				if transitionID != "" {
+					t.Errorf("transitioned after a failed comment")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CommentAndTransitionActivity: %v", err)
+			}
+
+			if out.CommentID != "10001" || out.Transitioned != tt.wantTransitioned || out.TransitionError != tt.wantTransErr {
+				t.Errorf("output = %+v", out)
+			}
+			if transitionID != tt.wantTransitionID {
+				t.Errorf("transition ID = %q, want %q", transitionID, tt.wantTransitionID)
+			}
+			if tt.wantTransitioned && fields["resolution"] == nil {
+				t.Errorf("fields = %v, want the resolution", fields)
+			}
+		})
+	}
+}
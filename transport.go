@@ -0,0 +1,289 @@
+package jira
+
+import (
+	"context"
+	"log/slog"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Middleware wraps a RoundTripper with cross-cutting behavior such as
+// retries, rate limiting, logging or tracing.
+//
+// The client applies authentication before the request enters the
+// transport, so every middleware sees fully authenticated requests.
+type Middleware func(http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc adapts a function to http.RoundTripper.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper.
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Chain wraps base with middlewares. The first middleware is the outermost:
+// it sees the request first and the response last. A typical order is
+//
+//	jira.Chain(base,
+//		jira.WithTracing(trace),  // one span per logical request
+//		jira.WithLogging(logger), // logs every attempt's outcome
+//		jira.WithRetry(3),        // retries below logging/tracing
+//		jira.WithRateLimit(10),   // every attempt, retries included, is limited
+//	)
+func Chain(base http.RoundTripper, middlewares ...Middleware) http.RoundTripper {
+	rt := base
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		rt = middlewares[i](rt)
+	}
+	return chain{RoundTripper: rt, base: base}
+}
+
+// chain is the RoundTripper built by Chain. It keeps the base so that
+// http.Client.CloseIdleConnections, and so Client.Close, reaches it
+// through the middlewares.
+type chain struct {
+	http.RoundTripper
+	base http.RoundTripper
+}
+
+func (c chain) CloseIdleConnections() {
+	if closer, ok := c.base.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+// NewBaseTransport returns the HTTP transport configured with the dial,
+// TLS and response header timeouts from cfg. Use it as the base of a custom
+// ClientConfig.Transport chain.
+func NewBaseTransport(cfg ClientConfig) *http.Transport {
+	dialTimeout := cfg.DialTimeout
+	if dialTimeout == 0 {
+		dialTimeout = 10 * time.Second
+	}
+
+	headerTimeout := cfg.ResponseHeaderTimeout
+	if headerTimeout == 0 {
+		headerTimeout = cfg.Timeout
+	}
+	if headerTimeout == 0 {
+		headerTimeout = 30 * time.Second
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   dialTimeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = dialTimeout
+	transport.ResponseHeaderTimeout = headerTimeout
+
+	return transport
+}
+
+// defaultTransport is the chain used when ClientConfig.Transport is nil.
+func defaultTransport(cfg ClientConfig) http.RoundTripper {
+	middlewares := []Middleware{WithRetryFunc(3, cfg.ShouldRetry)}
+	if cfg.RequestsPerSecond > 0 {
+		middlewares = append(middlewares, WithRateLimit(cfg.RequestsPerSecond))
+	}
+	return Chain(NewBaseTransport(cfg), middlewares...)
+}
+
+// RetryFunc decides whether a failed attempt is retried. Attempt counts
+// from 1; resp is nil when err is set.
+type RetryFunc func(resp *http.Response, err error, attempt int) bool
+
+var retryPolicies = newRegistry[RetryFunc]("retry policy")
+
+// RegisterRetryPolicy makes a retry decision available under name for the
+// ClientOptions.RetryPolicy option.
+func RegisterRetryPolicy(name string, retry RetryFunc) {
+	retryPolicies.register(name, retry)
+}
+
+// WithRetry retries requests up to maxAttempts times in total with
+// exponential backoff, honoring Retry-After. 429 responses are retried for
+// every method; 502, 503, 504 and network errors only for idempotent
+// methods. Retries are reported to the client's Metrics.
+func WithRetry(maxAttempts int) Middleware {
+	return WithRetryFunc(maxAttempts, nil)
+}
+
+// WithRetryFunc is WithRetry with the retry decision made by retry, for
+// deployments whose proxies fail with other codes, such as Cloudflare's
+// 520 to 524. It replaces the default classification entirely, so it
+// should also accept the statuses WithRetry retries. A nil retry uses the
+// default.
+func WithRetryFunc(maxAttempts int, retry RetryFunc) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			var resp *http.Response
+			var err error
+			for attempt := 1; ; attempt++ {
+				attemptReq := req
+				if attempt > 1 {
+					attemptReq, err = rewindRequest(req)
+					if err != nil {
+						return nil, err
+					}
+				}
+
+				resp, err = next.RoundTrip(attemptReq)
+				var retryable bool
+				if retry != nil {
+					retryable = req.Context().Err() == nil && retry(resp, err, attempt)
+				} else {
+					retryable = shouldRetry(req, resp, err)
+				}
+				if attempt >= maxAttempts || !retryable {
+					return resp, err
+				}
+
+				metricsFrom(req.Context()).IncRetry(retryReason(resp, err))
+				wait := backoff(attempt, resp)
+				if resp != nil {
+					resp.Body.Close()
+				}
+				if err := sleep(req.Context(), wait); err != nil {
+					return nil, err
+				}
+			}
+		})
+	}
+}
+
+// shouldRetry reports whether a failed attempt is worth retrying.
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		return req.Context().Err() == nil && isIdempotent(req.Method)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return isIdempotent(req.Method)
+	}
+	return false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// backoff returns the delay before the next attempt, preferring the
+// server's Retry-After over exponential backoff with jitter.
+func backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		if at, err := http.ParseTime(resp.Header.Get("Retry-After")); err == nil {
+			return time.Until(at)
+		}
+	}
+
+	base := 500 * time.Millisecond << (attempt - 1)
+	return base/2 + time.Duration(rand.Int63n(int64(base)))
+}
+
+// rewindRequest returns a copy of req with a fresh body for another attempt.
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		clone.Body = body
+	}
+	return clone, nil
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// WithRateLimit spaces requests so that at most requestsPerSecond are sent.
+func WithRateLimit(requestsPerSecond float64) Middleware {
+	interval := time.Duration(float64(time.Second) / requestsPerSecond)
+
+	var mu sync.Mutex
+	var next time.Time
+
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			now := time.Now()
+			if next.Before(now) {
+				next = now
+			}
+			wait := next.Sub(now)
+			next = next.Add(interval)
+			mu.Unlock()
+
+			if err := sleep(req.Context(), wait); err != nil {
+				return nil, err
+			}
+			return rt.RoundTrip(req)
+		})
+	}
+}
+
+// WithLogging logs every request's method, path, status and duration at
+// debug level, and failures at warn level.
+func WithLogging(logger *slog.Logger) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			duration := time.Since(start)
+
+			if err != nil {
+				logger.WarnContext(req.Context(), "jira: request failed",
+					"method", req.Method, "path", req.URL.Path, "duration", duration, "error", err)
+				return resp, err
+			}
+
+			logger.DebugContext(req.Context(), "jira: request",
+				"method", req.Method, "path", req.URL.Path, "status", resp.StatusCode, "duration", duration)
+			return resp, nil
+		})
+	}
+}
+
+// TraceFunc starts a trace span for an outgoing request. It returns the
+// context to send the request with and a function that ends the span.
+type TraceFunc func(req *http.Request) (context.Context, func(resp *http.Response, err error))
+
+// WithTracing wraps each request in a span started by trace, so any tracing
+// library can be plugged in without this package depending on it.
+func WithTracing(trace TraceFunc) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			ctx, end := trace(req)
+			resp, err := next.RoundTrip(req.WithContext(ctx))
+			end(resp, err)
+			return resp, err
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// recordingMetrics is a Metrics recording what it receives.
+type recordingMetrics struct {
+	mu       sync.Mutex
+	requests []int
+	retries  []string
+	slow     int
+}
+
+func (m *recordingMetrics) IncRequest(method string, status int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = append(m.requests, status)
+}
+
+func (m *recordingMetrics) IncRetry(reason string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries = append(m.retries, reason)
+}
+
+func (m *recordingMetrics) ObserveLatency(string, time.Duration) {}
+
+func (m *recordingMetrics) IncSlowRequest(string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.slow++
+}
+
+// retryCloudflare retries Cloudflare's 52x codes besides the defaults.
+func retryCloudflare(resp *http.Response, err error, attempt int) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case 429, 502, 503, 504, 520, 521, 522, 523, 524:
+		return true
+	}
+	return false
+}
+
+func TestClientRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		status       int
+		shouldRetry  RetryFunc
+		wantAttempts int32
+		wantRetries  []string
+		wantErr      bool
+	}{
+		{
+			name:         "default retries 503 on GET",
+			method:       http.MethodGet,
+			status:       http.StatusServiceUnavailable,
+			wantAttempts: 2,
+			wantRetries:  []string{"503"},
+		},
+		{
+			name:         "default retries 429 on POST",
+			method:       http.MethodPost,
+			status:       http.StatusTooManyRequests,
+			wantAttempts: 2,
+			wantRetries:  []string{"429"},
+		},
+		{
+			name:         "default does not retry 503 on POST",
+			method:       http.MethodPost,
+			status:       http.StatusServiceUnavailable,
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name:         "default does not retry 520",
+			method:       http.MethodGet,
+			status:       520,
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name:         "custom classifier retries 520",
+			method:       http.MethodGet,
+			status:       520,
+			shouldRetry:  retryCloudflare,
+			wantAttempts: 2,
+			wantRetries:  []string{"520"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			metrics := &recordingMetrics{}
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if attempts.Add(1) == 1 {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(tt.status)
+					return
+				}
+				writeJSON(w, map[string]any{})
+			}), ClientConfig{ShouldRetry: tt.shouldRetry, Metrics: metrics})
+
+			err := client.do(context.Background(), tt.method, "/rest/api/3/thing", nil, nil, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if n := attempts.Load(); n != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", n, tt.wantAttempts)
+			}
+			if strings.Join(metrics.retries, ",") != strings.Join(tt.wantRetries, ",") {
+				t.Errorf("retries = %v, want %v", metrics.retries, tt.wantRetries)
+			}
+		})
+	}
+}
+
+func TestChain(t *testing.T) {
+	var order []string
+	record := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name+" in")
+				resp, err := next.RoundTrip(req)
+				order = append(order, name+" out")
+				return resp, err
+			})
+		}
+	}
+	errBase := errors.New("base")
+	base := RoundTripperFunc(func(*http.Request) (*http.Response, error) {
+		order = append(order, "base")
+		return nil, errBase
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "http://jira.example.com", nil)
+	_, err := Chain(base, record("outer"), record("inner")).RoundTrip(req)
+	if !errors.Is(err, errBase) {
+		t.Fatalf("error = %v, want the base error", err)
+	}
+
+	want := "outer in, inner in, base, inner out, outer out"
+	if got := strings.Join(order, ", "); got != want {
+		t.Errorf("order = %s, want %s", got, want)
+	}
+}
+
+func TestRequestsPerSecond(t *testing.T) {
+	tests := []struct {
+		name              string
+		requestsPerSecond float64
+		minElapsed        time.Duration
+	}{
+		{name: "unlimited"},
+		{name: "20 per second", requestsPerSecond: 20, minElapsed: 150 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				writeJSON(w, map[string]any{})
+			}), ClientConfig{RequestsPerSecond: tt.requestsPerSecond})
+
+			start := time.Now()
+			for range 4 {
+				if err := client.do(context.Background(), http.MethodGet, "/rest/api/3/thing", nil, nil, nil); err != nil {
+					t.Fatalf("do: %v", err)
+				}
+			}
+			if elapsed := time.Since(start); elapsed < tt.minElapsed {
+				t.Errorf("4 requests took %s, want at least %s", elapsed, tt.minElapsed)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// UpdateIssue sets fields on an issue. Field values use the shapes of the
+// Jira edit API, e.g. {"summary": "New title"}.
+func (c *Client) UpdateIssue(ctx context.Context, issueKey string, fields map[string]any) error {
+	body := map[string]any{"fields": fields}
+	return c.do(ctx, http.MethodPut, "/rest/api/3/issue/"+url.PathEscape(issueKey), nil, body, nil)
+}
+
+// UpdateIssueIfUnchanged applies fields only if the issue's updated
+// timestamp still equals expectedUpdated, returning ErrConflict otherwise.
+//
+// Jira has no conditional edit, so this re-reads the issue and then edits
+// it: a concurrent edit landing between the two requests is not detected.
+// Treat it as a best-effort guard against lost updates, not a lock.
+func (c *Client) UpdateIssueIfUnchanged(ctx context.Context, issueKey string, expectedUpdated time.Time, fields map[string]any) error {
+	query := url.Values{}
+	query.Set("fields", "updated")
+
+	current, err := c.readIssue(ctx, issueKey, query)
+	if err != nil {
+		return fmt.Errorf("get issue: %w", err)
+	}
+
+	updated, err := parseTime(current.Fields.Updated)
+	if err != nil {
+		return err
+	}
+	if !updated.Equal(expectedUpdated.Truncate(time.Millisecond)) {
+		return fmt.Errorf("%w: %s updated at %s, expected %s",
+			ErrConflict, issueKey, updated.Format(time.RFC3339Nano), expectedUpdated.Format(time.RFC3339Nano))
+	}
+
+	return c.UpdateIssue(ctx, issueKey, fields)
+}
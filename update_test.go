@@ -0,0 +1,46 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestUpdateIssueIfUnchanged(t *testing.T) {
+	updated := time.Date(2024, 3, 1, 10, 0, 0, 123_000_000, time.UTC)
+
+	tests := []struct {
+		name     string
+		expected time.Time
+		wantErr  error
+	}{
+		{name: "unchanged", expected: updated},
+		{name: "unchanged, other zone and extra precision", expected: updated.Add(456 * time.Microsecond).In(time.FixedZone("", 5*3600+1800))},
+		{name: "changed", expected: updated.Add(-time.Minute), wantErr: ErrConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var edited bool
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.Method {
+				case http.MethodGet:
+					writeJSON(w, testIssue("PROJ-1", map[string]any{"updated": "2024-03-01T15:30:00.123+0530"}))
+				case http.MethodPut:
+					edited = true
+					w.WriteHeader(http.StatusNoContent)
+				}
+			}), ClientConfig{})
+
+			err := client.UpdateIssueIfUnchanged(context.Background(), "PROJ-1", tt.expected, map[string]any{"summary": "New title"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if edited != (tt.wantErr == nil) {
+				t.Errorf("edited = %v, want %v", edited, tt.wantErr == nil)
+			}
+		})
+	}
+}
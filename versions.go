@@ -0,0 +1,125 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/resolute-sh/resolute/core"
+)
+
+// Version is a project version, used for an issue's fix versions.
+type Version struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Released    bool   `json:"released"`
+	Archived    bool   `json:"archived"`
+	ReleaseDate string `json:"releaseDate,omitempty"`
+}
+
+// GetProjectVersions returns all versions of a project. They are cached
+// for the life of the client; failures are not cached.
+func (c *Client) GetProjectVersions(ctx context.Context, projectKey string) ([]Version, error) {
+	c.versionCacheMu.Lock()
+	defer c.versionCacheMu.Unlock()
+
+	if versions, ok := c.versionCache[projectKey]; ok {
+		return append([]Version(nil), versions...), nil
+	}
+
+	var versions []Version
+	path := "/rest/api/3/project/" + url.PathEscape(projectKey) + "/versions"
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &versions); err != nil {
+		return nil, err
+	}
+
+	if c.versionCache == nil {
+		c.versionCache = make(map[string][]Version)
+	}
+	c.versionCache[projectKey] = versions
+	return append([]Version(nil), versions...), nil
+}
+
+// SetFixVersions makes an issue's fix versions exactly the versions of
+// projectKey named in versionNames, replacing any others. Names are
+// resolved to IDs before the edit, which is not sent if any name matches
+// no version of the project. An empty list clears the fix versions.
+func (c *Client) SetFixVersions(ctx context.Context, key string, versionNames []string, projectKey string) error {
+	versions, err := c.GetProjectVersions(ctx, projectKey)
+	if err != nil {
+		return fmt.Errorf("get versions of %s: %w", projectKey, err)
+	}
+
+	ids := make(map[string]string, len(versions))
+	for _, version := range versions {
+		ids[version.Name] = version.ID
+	}
+
+	refs := make([]map[string]string, 0, len(versionNames))
+	seen := make(map[string]bool, len(versionNames))
+	var unknown []string
+	for _, name := range versionNames {
+		id, ok := ids[name]
+		if !ok {
+			unknown = append(unknown, fmt.Sprintf("%q", name))
+			continue
+		}
+		if !seen[id] {
+			seen[id] = true
+			refs = append(refs, map[string]string{"id": id})
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("no version named %s in project %s", strings.Join(unknown, ", "), projectKey)
+	}
+
+	return c.UpdateIssue(ctx, key, map[string]any{"fixVersions": refs})
+}
+
+// SetFixVersionsInput is the input for SetFixVersionsActivity.
+type SetFixVersionsInput struct {
+	BaseURL      string
+	Email        string
+	APIToken     string
+	IssueKeys    []string
+	ProjectKey   string
+	VersionNames []string
+
+	ClientOptions
+}
+
+// SetFixVersionsOutput is the output of SetFixVersionsActivity.
+type SetFixVersionsOutput struct {
+	Updated int
+}
+
+// SetFixVersionsActivity sets the fix versions of each issue to the named
+// versions of ProjectKey. Version names are resolved once for all issues.
+func SetFixVersionsActivity(ctx context.Context, input SetFixVersionsInput) (SetFixVersionsOutput, error) {
+	client, err := input.ClientOptions.newClient(ClientConfig{
+		BaseURL:  input.BaseURL,
+		Email:    input.Email,
+		APIToken: input.APIToken,
+	})
+	if err != nil {
+		return SetFixVersionsOutput{}, err
+	}
+	defer client.Close()
+
+	var output SetFixVersionsOutput
+	for _, key := range input.IssueKeys {
+		if err := client.SetFixVersions(ctx, key, input.VersionNames, input.ProjectKey); err != nil {
+			return output, fmt.Errorf("set fix versions of %s: %w", key, err)
+		}
+		output.Updated++
+	}
+
+	return output, nil
+}
+
+// SetFixVersions creates a node for assigning fix versions to issues.
+func SetFixVersions(input SetFixVersionsInput) *core.Node[SetFixVersionsInput, SetFixVersionsOutput] {
+	return core.NewNode("jira.SetFixVersions", SetFixVersionsActivity, input)
+}
@@ -0,0 +1,80 @@
+package jira
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSetFixVersionsActivity(t *testing.T) {
+	tests := []struct {
+		name      string
+		names     []string
+		wantSent  string
+		wantEdits int32
+		wantErr   string
+	}{
+		{name: "resolved once", names: []string{"2.4", "2.3", "2.4"}, wantSent: `[{"id":"102"},{"id":"101"}]`, wantEdits: 2},
+		{name: "cleared", names: nil, wantSent: `[]`, wantEdits: 2},
+		{name: "unknown names", names: []string{"2.3", "3.0", "next"}, wantErr: `no version named "3.0", "next" in project PROJ`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lookups, edits atomic.Int32
+			var sent atomic.Value
+			edit := func(w http.ResponseWriter, r *http.Request) {
+				edits.Add(1)
+				var body struct {
+					Fields struct {
+						FixVersions json.RawMessage `json:"fixVersions"`
+					} `json:"fields"`
+				}
+				if err := decodeBody(r, &body); err != nil {
+					t.Errorf("decode body: %v", err)
+				}
+				sent.Store(string(body.Fields.FixVersions))
+				w.WriteHeader(http.StatusNoContent)
+			}
+			fake := &fakeJira{routes: map[string]http.HandlerFunc{
+				"/rest/api/3/project/PROJ/versions": func(w http.ResponseWriter, r *http.Request) {
+					lookups.Add(1)
+					writeJSON(w, []any{
+						map[string]any{"id": "101", "name": "2.3", "released": true},
+						map[string]any{"id": "102", "name": "2.4"},
+					})
+				},
+				"/rest/api/3/issue/PROJ-1": edit,
+				"/rest/api/3/issue/PROJ-2": edit,
+			}}
+
+			out, err := runActivity(t, SetFixVersionsActivity, SetFixVersionsInput{
+				BaseURL:      fake.start(t),
+				IssueKeys:    []string{"PROJ-1", "PROJ-2"},
+				ProjectKey:   "PROJ",
+				VersionNames: tt.names,
+			})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("SetFixVersionsActivity: %v", err)
+			} else if out.Updated != 2 {
+				t.Errorf("updated = %d, want 2", out.Updated)
+			}
+
+			if n := lookups.Load(); n != 1 {
+				t.Errorf("versions looked up %d times, want once", n)
+			}
+			if n := edits.Load(); n != tt.wantEdits {
+				t.Errorf("%d edits, want %d", n, tt.wantEdits)
+			}
+			if got, _ := sent.Load().(string); got != tt.wantSent {
+				t.Errorf("fixVersions = %s, want %s", got, tt.wantSent)
+			}
+		})
+	}
+}
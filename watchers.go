@@ -0,0 +1,169 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/resolute-sh/resolute/core"
+)
+
+// GetWatchers returns the users watching an issue.
+func (c *Client) GetWatchers(ctx context.Context, issueKey string) ([]User, error) {
+	var resp struct {
+		Watchers []User `json:"watchers"`
+	}
+	path := "/rest/api/3/issue/" + url.PathEscape(issueKey) + "/watchers"
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Watchers, nil
+}
+
+// AddWatcher adds a user, by account ID, to an issue's watchers. Adding a
+// current watcher is a no-op.
+func (c *Client) AddWatcher(ctx context.Context, issueKey, accountID string) error {
+	path := "/rest/api/3/issue/" + url.PathEscape(issueKey) + "/watchers"
+	// The body is the account ID as a bare JSON string.
+	return c.do(ctx, http.MethodPost, path, nil, accountID, nil)
+}
+
+// RemoveWatcher removes a user, by account ID, from an issue's watchers.
+func (c *Client) RemoveWatcher(ctx context.Context, issueKey, accountID string) error {
+	query := url.Values{}
+	query.Set("accountId", accountID)
+
+	path := "/rest/api/3/issue/" + url.PathEscape(issueKey) + "/watchers"
+	return c.do(ctx, http.MethodDelete, path, query, nil, nil)
+}
+
+// SetWatchersInput is the input for SetWatchersActivity.
+type SetWatchersInput struct {
+	BaseURL    string
+	Email      string
+	APIToken   string
+	IssueKey   string
+	AccountIDs []string // the desired watchers
+
+	// Concurrency bounds the number of watcher changes sent at once.
+	// Default 4.
+	Concurrency int
+
+	ClientOptions
+}
+
+// SetWatchersOutput is the output of SetWatchersActivity.
+type SetWatchersOutput struct {
+	Added   []string
+	Removed []string
+}
+
+// SetWatchersActivity makes an issue's watchers exactly AccountIDs: it
+// reads the current watchers, then adds the missing and removes the extra
+// ones with bounded concurrency. Watchers in both sets are not touched, so
+// re-running after a partial failure only sends the remaining changes.
+// Failed changes are returned as a joined error.
+func SetWatchersActivity(ctx context.Context, input SetWatchersInput) (SetWatchersOutput, error) {
+	client, err := input.ClientOptions.newClient(ClientConfig{
+		BaseURL:  input.BaseURL,
+		Email:    input.Email,
+		APIToken: input.APIToken,
+	})
+	if err != nil {
+		return SetWatchersOutput{}, err
+	}
+	defer client.Close()
+
+	current, err := client.GetWatchers(ctx, input.IssueKey)
+	if err != nil {
+		return SetWatchersOutput{}, fmt.Errorf("get watchers: %w", err)
+	}
+
+	desired := make(map[string]bool, len(input.AccountIDs))
+	for _, id := range input.AccountIDs {
+		desired[id] = true
+	}
+	watching := make(map[string]bool, len(current))
+	for _, user := range current {
+		watching[user.AccountID] = true
+	}
+
+	type change struct {
+		accountID string
+		add       bool
+	}
+	var changes []change
+	for _, id := range input.AccountIDs {
+		if !watching[id] {
+			watching[id] = true // also dedupes AccountIDs
+			changes = append(changes, change{accountID: id, add: true})
+		}
+	}
+	for _, user := range current {
+		if !desired[user.AccountID] {
+			changes = append(changes, change{accountID: user.AccountID})
+		}
+	}
+
+	concurrency := input.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	var out SetWatchersOutput
+	var errs []error
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for _, ch := range changes {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(ch change) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			var err error
+			if ch.add {
+				err = client.AddWatcher(ctx, input.IssueKey, ch.accountID)
+			} else {
+				err = client.RemoveWatcher(ctx, input.IssueKey, ch.accountID)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err != nil && ch.add:
+				errs = append(errs, fmt.Errorf("add watcher %s: %w", ch.accountID, err))
+			case err != nil:
+				errs = append(errs, fmt.Errorf("remove watcher %s: %w", ch.accountID, err))
+			case ch.add:
+				out.Added = append(out.Added, ch.accountID)
+			default:
+				out.Removed = append(out.Removed, ch.accountID)
+			}
+		}(ch)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return out, err
+	}
+
+	return out, errors.Join(errs...)
+}
+
+// SetWatchers creates a node for reconciling an issue's watchers.
+func SetWatchers(input SetWatchersInput) *core.Node[SetWatchersInput, SetWatchersOutput] {
+	return core.NewNode("jira.SetWatchers", SetWatchersActivity, input)
+}
@@ -0,0 +1,89 @@
+package jira
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSetWatchersActivity(t *testing.T) {
+	tests := []struct {
+		name        string
+		desired     []string
+		failRemove  bool
+		wantAdded   string
+		wantRemoved string
+		wantErr     string
+	}{
+		{name: "reconcile", desired: []string{"u2", "u3", "u3", "u4"}, wantAdded: "u3,u4", wantRemoved: "u1"},
+		{name: "unchanged", desired: []string{"u2", "u1"}},
+		{name: "failed removal", desired: []string{"u2", "u3"}, failRemove: true, wantAdded: "u3", wantErr: "remove watcher u1: "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var added []string
+			fake := &fakeJira{routes: map[string]http.HandlerFunc{
+				"/rest/api/3/issue/PROJ-1/watchers": func(w http.ResponseWriter, r *http.Request) {
+					switch r.Method {
+					case http.MethodGet:
+						writeJSON(w, map[string]any{"watchers": []any{
+							map[string]any{"accountId": "u1"},
+							map[string]any{"accountId": "u2"},
+						}})
+					case http.MethodPost:
+						var id string
+						if err := decodeBody(r, &id); err != nil {
+							t.Errorf("decode body: %v", err)
+						}
+						mu.Lock()
+						added = append(added, id)
+						mu.Unlock()
+						w.WriteHeader(http.StatusNoContent)
+					case http.MethodDelete:
+						if tt.failRemove {
+							http.Error(w, `{"errorMessages":["no"]}`, http.StatusForbidden)
+							return
+						}
+						if id := r.URL.Query().Get("accountId"); id != "u1" {
+							t.Errorf("removed %s, want u1", id)
+						}
+						w.WriteHeader(http.StatusNoContent)
+					}
+				},
+			}}
+
+			out, err := runActivity(t, SetWatchersActivity, SetWatchersInput{
+				BaseURL:    fake.start(t),
+				IssueKey:   "PROJ-1",
+				AccountIDs: tt.desired,
+			})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("SetWatchersActivity: %v", err)
+			}
+
+			if tt.wantErr != "" {
+				// A failed activity returns no output; check the requests.
+				slices.Sort(added)
+				if got := strings.Join(added, ","); got != tt.wantAdded {
+					t.Errorf("added = %s, want %s", got, tt.wantAdded)
+				}
+				return
+			}
+			slices.Sort(out.Added)
+			if got := strings.Join(out.Added, ","); got != tt.wantAdded {
+				t.Errorf("added = %s, want %s", got, tt.wantAdded)
+			}
+			if got := strings.Join(out.Removed, ","); got != tt.wantRemoved {
+				t.Errorf("removed = %s, want %s", got, tt.wantRemoved)
+			}
+		})
+	}
+}
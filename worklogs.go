@@ -0,0 +1,223 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/resolute-sh/resolute-jira/adf"
+	"github.com/resolute-sh/resolute/core"
+)
+
+// Worklog is a single work log entry on an issue.
+type Worklog struct {
+	ID               string `json:"id"`
+	Author           User   `json:"author"`
+	Comment          string `json:"comment"`
+	Started          string `json:"started"`
+	Created          string `json:"created"`
+	Updated          string `json:"updated"`
+	TimeSpent        string `json:"timeSpent"`
+	TimeSpentSeconds int    `json:"timeSpentSeconds"`
+}
+
+// UnmarshalJSON decodes a worklog, converting an ADF comment to plain text.
+func (w *Worklog) UnmarshalJSON(data []byte) error {
+	type plain Worklog
+	aux := struct {
+		*plain
+		Comment json.RawMessage `json:"comment"`
+	}{plain: (*plain)(w)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	// A malformed comment is kept as its raw JSON rather than failing the
+	// decode, so one bad worklog does not fail the whole fetch.
+	comment, err := adf.PlainText(aux.Comment)
+	if err != nil {
+		comment = string(aux.Comment)
+	}
+	w.Comment = comment
+
+	return nil
+}
+
+// worklogPage is a page of an issue's worklogs.
+type worklogPage struct {
+	StartAt    int       `json:"startAt"`
+	MaxResults int       `json:"maxResults"`
+	Total      int       `json:"total"`
+	Worklogs   []Worklog `json:"worklogs"`
+}
+
+// GetWorklogs returns all worklogs of an issue.
+func (c *Client) GetWorklogs(ctx context.Context, issueKey string) ([]Worklog, error) {
+	path := "/rest/api/3/issue/" + url.PathEscape(issueKey) + "/worklog"
+
+	var worklogs []Worklog
+	startAt := 0
+	for {
+		query := url.Values{}
+		query.Set("startAt", strconv.Itoa(startAt))
+		query.Set("maxResults", "1000")
+
+		var page worklogPage
+		if err := c.do(ctx, http.MethodGet, path, query, nil, &page); err != nil {
+			return nil, err
+		}
+
+		worklogs = append(worklogs, page.Worklogs...)
+		startAt += len(page.Worklogs)
+		if len(page.Worklogs) == 0 || startAt >= page.Total {
+			return worklogs, nil
+		}
+	}
+}
+
+// How AddWorklog adjusts the issue's remaining estimate.
+const (
+	// AdjustEstimateAuto reduces the remaining estimate by the time spent.
+	// It is Jira's default.
+	AdjustEstimateAuto = "auto"
+
+	// AdjustEstimateLeave leaves the remaining estimate unchanged.
+	AdjustEstimateLeave = "leave"
+
+	// AdjustEstimateNew sets the remaining estimate to NewEstimate.
+	AdjustEstimateNew = "new"
+
+	// AdjustEstimateManual reduces the remaining estimate by ReduceBy.
+	AdjustEstimateManual = "manual"
+)
+
+// NewWorklog is a work log entry to add with AddWorklog.
+type NewWorklog struct {
+	TimeSpentSeconds int
+	Started          time.Time // default now
+	Comment          string    // optional, plain text
+
+	// AdjustEstimate is one of the AdjustEstimate constants, default
+	// AdjustEstimateAuto.
+	AdjustEstimate string
+
+	// NewEstimate is the remaining estimate to set with AdjustEstimateNew,
+	// in Jira duration format such as "2d 4h".
+	NewEstimate string
+
+	// ReduceBy is the amount to reduce the remaining estimate by with
+	// AdjustEstimateManual, in Jira duration format such as "3h".
+	ReduceBy string
+}
+
+// worklogQuery validates the estimate adjustment of w and returns its
+// query parameters.
+func (w NewWorklog) worklogQuery() (url.Values, error) {
+	query := url.Values{}
+	switch w.AdjustEstimate {
+	case "", AdjustEstimateAuto, AdjustEstimateLeave:
+		if w.NewEstimate != "" || w.ReduceBy != "" {
+			return nil, fmt.Errorf("newEstimate and reduceBy require adjustEstimate %q or %q", AdjustEstimateNew, AdjustEstimateManual)
+		}
+	case AdjustEstimateNew:
+		if w.NewEstimate == "" || w.ReduceBy != "" {
+			return nil, fmt.Errorf("adjustEstimate %q requires newEstimate and no reduceBy", AdjustEstimateNew)
+		}
+		query.Set("newEstimate", w.NewEstimate)
+	case AdjustEstimateManual:
+		if w.ReduceBy == "" || w.NewEstimate != "" {
+			return nil, fmt.Errorf("adjustEstimate %q requires reduceBy and no newEstimate", AdjustEstimateManual)
+		}
+		query.Set("reduceBy", w.ReduceBy)
+	default:
+		return nil, fmt.Errorf("unknown adjustEstimate %q", w.AdjustEstimate)
+	}
+	if w.AdjustEstimate != "" {
+		query.Set("adjustEstimate", w.AdjustEstimate)
+	}
+	return query, nil
+}
+
+// AddWorklog logs work on an issue and returns the created worklog.
+func (c *Client) AddWorklog(ctx context.Context, issueKey string, w NewWorklog) (*Worklog, error) {
+	if w.TimeSpentSeconds <= 0 {
+		return nil, fmt.Errorf("timeSpentSeconds must be positive")
+	}
+	query, err := w.worklogQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	started := w.Started
+	if started.IsZero() {
+		started = time.Now()
+	}
+
+	payload := struct {
+		TimeSpentSeconds int       `json:"timeSpentSeconds"`
+		Started          string    `json:"started"`
+		Comment          *adf.Node `json:"comment,omitempty"`
+	}{
+		TimeSpentSeconds: w.TimeSpentSeconds,
+		Started:          started.Format("2006-01-02T15:04:05.000-0700"),
+	}
+	if w.Comment != "" {
+		comment := adf.FromText(w.Comment)
+		payload.Comment = &comment
+	}
+
+	var worklog Worklog
+	path := "/rest/api/3/issue/" + url.PathEscape(issueKey) + "/worklog"
+	if err := c.do(ctx, http.MethodPost, path, query, payload, &worklog); err != nil {
+		return nil, err
+	}
+
+	return &worklog, nil
+}
+
+// AddWorklogInput is the input for AddWorklogActivity.
+type AddWorklogInput struct {
+	BaseURL  string
+	Email    string
+	APIToken string
+	IssueKey string
+
+	NewWorklog
+
+	ClientOptions
+}
+
+// AddWorklogOutput is the output of AddWorklogActivity.
+type AddWorklogOutput struct {
+	WorklogID string
+}
+
+// AddWorklogActivity logs work on an issue.
+func AddWorklogActivity(ctx context.Context, input AddWorklogInput) (AddWorklogOutput, error) {
+	client, err := input.ClientOptions.newClient(ClientConfig{
+		BaseURL:  input.BaseURL,
+		Email:    input.Email,
+		APIToken: input.APIToken,
+	})
+	if err != nil {
+		return AddWorklogOutput{}, err
+	}
+	defer client.Close()
+
+	worklog, err := client.AddWorklog(ctx, input.IssueKey, input.NewWorklog)
+	if err != nil {
+		return AddWorklogOutput{}, fmt.Errorf("add worklog: %w", err)
+	}
+
+	return AddWorklogOutput{WorklogID: worklog.ID}, nil
+}
+
+// AddWorklog creates a node for logging work on an issue.
+func AddWorklog(input AddWorklogInput) *core.Node[AddWorklogInput, AddWorklogOutput] {
+	return core.NewNode("jira.AddWorklog", AddWorklogActivity, input)
+}
@@ -0,0 +1,110 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestGetWorklogs(t *testing.T) {
+	var pages []int
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		startAt, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+		pages = append(pages, startAt)
+
+		// The server caps pages at 2 regardless of maxResults.
+		var worklogs []any
+		for i := startAt; i < min(startAt+2, 3); i++ {
+			worklogs = append(worklogs, map[string]any{
+				"id":      strconv.Itoa(i + 1),
+				"comment": map[string]any{"type": "doc", "version": 1, "content": []any{map[string]any{"type": "paragraph", "content": []any{map[string]any{"type": "text", "text": "entry " + strconv.Itoa(i+1)}}}}},
+			})
+		}
+		writeJSON(w, map[string]any{"startAt": startAt, "maxResults": 2, "total": 3, "worklogs": worklogs})
+	}), ClientConfig{})
+
+	worklogs, err := client.GetWorklogs(context.Background(), "PROJ-1")
+	if err != nil {
+		t.Fatalf("GetWorklogs: %v", err)
+	}
+	if len(worklogs) != 3 || worklogs[2].ID != "3" || worklogs[2].Comment != "entry 3" {
+		t.Errorf("worklogs = %+v", worklogs)
+	}
+	if len(pages) != 2 || pages[1] != 2 {
+		t.Errorf("pages started at %v, want [0 2]", pages)
+	}
+}
+
+func TestWorklogCommentDecode(t *testing.T) {
+	var w Worklog
+	if err := json.Unmarshal([]byte(`{"id":"1","comment":{"type":"doc","content":"broken"}}`), &w); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if w.Comment != `{"type":"doc","content":"broken"}` {
+		t.Errorf("comment = %q, want the raw JSON", w.Comment)
+	}
+}
+
+func TestAddWorklog(t *testing.T) {
+	tests := []struct {
+		name      string
+		worklog   NewWorklog
+		wantQuery string
+		wantErr   string
+	}{
+		{name: "default", worklog: NewWorklog{TimeSpentSeconds: 3600}},
+		{name: "leave", worklog: NewWorklog{TimeSpentSeconds: 3600, AdjustEstimate: AdjustEstimateLeave}, wantQuery: "adjustEstimate=leave"},
+		{name: "new estimate", worklog: NewWorklog{TimeSpentSeconds: 3600, AdjustEstimate: AdjustEstimateNew, NewEstimate: "2d 4h"}, wantQuery: "adjustEstimate=new&newEstimate=2d+4h"},
+		{name: "manual", worklog: NewWorklog{TimeSpentSeconds: 3600, AdjustEstimate: AdjustEstimateManual, ReduceBy: "3h"}, wantQuery: "adjustEstimate=manual&reduceBy=3h"},
+		{name: "new without estimate", worklog: NewWorklog{TimeSpentSeconds: 3600, AdjustEstimate: AdjustEstimateNew}, wantErr: `adjustEstimate "new" requires newEstimate and no reduceBy`},
+		{name: "reduce without manual", worklog: NewWorklog{TimeSpentSeconds: 3600, ReduceBy: "3h"}, wantErr: `newEstimate and reduceBy require adjustEstimate "new" or "manual"`},
+		{name: "unknown adjustment", worklog: NewWorklog{TimeSpentSeconds: 3600, AdjustEstimate: "half"}, wantErr: `unknown adjustEstimate "half"`},
+		{name: "no time", worklog: NewWorklog{}, wantErr: "timeSpentSeconds must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var query string
+			var body map[string]any
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				query = r.URL.RawQuery
+				if err := decodeBody(r, &body); err != nil {
+					t.Errorf("decode body: %v", err)
+				}
+				writeJSON(w, map[string]any{"id": "10100", "timeSpentSeconds": 3600})
+			}), ClientConfig{})
+
+			tt.worklog.Started = time.Date(2024, 3, 1, 9, 30, 0, 0, time.FixedZone("", -8*3600))
+			tt.worklog.Comment = "debugging"
+			worklog, err := client.AddWorklog(context.Background(), "PROJ-1", tt.worklog)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				if body != nil {
+					t.Errorf("request sent for an invalid worklog")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AddWorklog: %v", err)
+			}
+
+			if worklog.ID != "10100" {
+				t.Errorf("worklog ID = %s", worklog.ID)
+			}
+			if query != tt.wantQuery {
+				t.Errorf("query = %s, want %s", query, tt.wantQuery)
+			}
+			if body["started"] != "2024-03-01T09:30:00.000-0800" || body["timeSpentSeconds"] != 3600.0 {
+				t.Errorf("body = %v", body)
+			}
+			if comment, _ := body["comment"].(map[string]any); comment["type"] != "doc" {
+				t.Errorf("comment = %v, want an ADF document", body["comment"])
+			}
+		})
+	}
+}